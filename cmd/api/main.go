@@ -1,67 +1,364 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	echoSwagger "github.com/swaggo/echo-swagger"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 
 	_ "github.com/imarrche/nix-ed/docs"
 	"github.com/imarrche/nix-ed/internal/auth"
+	"github.com/imarrche/nix-ed/internal/category"
 	"github.com/imarrche/nix-ed/internal/comment"
+	"github.com/imarrche/nix-ed/internal/config"
 	"github.com/imarrche/nix-ed/internal/model"
 	"github.com/imarrche/nix-ed/internal/post"
+	"github.com/imarrche/nix-ed/internal/problem"
+	"github.com/imarrche/nix-ed/internal/reaction"
+	"github.com/imarrche/nix-ed/internal/render"
+	"github.com/imarrche/nix-ed/internal/search"
+	"github.com/imarrche/nix-ed/internal/tag"
+	"github.com/imarrche/nix-ed/internal/user"
 )
 
 // @title Nix-Ed REST API
 // @version 1.0
-// @description This is simple REST API with CRUD for posts and comments.
+// @description This is simple REST API with CRUD for posts, comments, reactions, tags, categories and user profiles.
 // @host localhost:8080
 // @BasePath /api/
 func main() {
+	bootstrapAdmin := flag.String("bootstrap-admin", "", "make the user with this ID or email the first admin and exit")
+	flag.Parse()
+
 	dsn := os.Getenv("DSN")
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	if err := db.AutoMigrate(&model.Post{}); err != nil {
+	if err := migrate(db); err != nil {
 		log.Fatal(err)
 	}
-	if err := db.AutoMigrate(&model.Comment{}); err != nil {
+
+	if *bootstrapAdmin != "" {
+		u, err := user.NewService(user.NewRepo(db)).BootstrapAdmin(context.Background(), *bootstrapAdmin)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("user %s (%s) is now an admin", u.ID, u.Name)
+		return
+	}
+
+	ss, err := newSearchService(db, config.Get().SearchEngine)
+	if err != nil {
 		log.Fatal(err)
 	}
 
-	as := auth.NewGoogleService()
-	ph := post.NewHandler(post.NewService(post.NewRepo(db)), as)
-	ch := comment.NewHandler(comment.NewService(comment.NewRepo(db)), as)
-	ah := auth.NewHandler(as)
+	secret, err := sessionSecret(config.Get().SessionSecret)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	reg, err := newRegistry(config.Get())
+	if err != nil {
+		log.Fatal(err)
+	}
+	sn := auth.NewSessions(
+		auth.NewSessionRepo(db), secret, config.Get().AccessTokenTTL, config.Get().RefreshTokenTTL,
+	)
+	cr := search.NewCommentRepo(comment.NewRepo(db), ss)
+	pr := search.NewPostRepo(post.NewRepo(db), cr, ss)
+	ps := post.NewService(pr)
+	cs := comment.NewService(cr, pr, config.Get().MaxCommentDepth)
+	rs := reaction.NewService(reaction.NewRepo(db), pr, cr)
+	ph := post.NewHandler(ps, rs)
+	ch := comment.NewHandler(cs, rs)
+	rh := reaction.NewHandler(rs)
+	us := user.NewService(user.NewRepo(db))
+	ah := auth.NewHandler(reg, auth.NewPasswords(auth.NewCredentialRepo(db)), us, sn)
+	uh := user.NewHandler(us)
+	sh := search.NewHandler(ss)
+	th := tag.NewHandler(tag.NewService(tag.NewRepo(db)))
+	kh := category.NewHandler(category.NewService(category.NewRepo(db)))
+
+	// The in-memory index starts empty, MySQL searches the tables directly.
+	if config.Get().SearchEngine == "memory" {
+		if err := search.Reindex(context.Background(), ss, pr, cr); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	go purgeTrash(ps, cs, rs, sn, config.Get().TrashRetention)
+	go publishScheduled(ps, config.Get().PublishInterval)
+
+	authn, optAuthn := auth.Authenticate(sn), auth.OptionalAuthenticate(sn)
+	read, write := timeout(config.Get().ReadTimeout), timeout(config.Get().WriteTimeout)
+	signIn := timeout(config.Get().AuthTimeout)
 
 	e := echo.New()
+	e.HTTPErrorHandler = problem.Handler
+	e.Binder = &render.Binder{}
+	e.Use(middleware.RequestID())
+	// Request bodies fit the largest post with room for JSON escapes.
+	e.Use(middleware.BodyLimit("1M"))
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
-	auth := e.Group("/auth")
-	auth.GET("/google/sign-in", ah.GoogleSignIn)
-	auth.GET("/google/callback", ah.GoogleCallback)
+	ag := e.Group("/auth")
+	ag.POST("/password/sign-up", ah.PasswordSignUp, signIn)
+	ag.POST("/password/sign-in", ah.PasswordSignIn, signIn)
+	ag.GET("/:provider/sign-in", ah.SignIn, signIn)
+	ag.GET("/:provider/callback", ah.Callback, signIn)
+	ag.POST("/refresh", ah.Refresh, write)
+	ag.POST("/sign-out", ah.SignOut, write, authn)
 
 	api := e.Group("/api")
+	api.GET("/search", sh.Search, read)
+
+	pg := api.Group("/posts")
+	pg.GET("", ph.GetAll, read, optAuthn)
+	pg.POST("", ph.Create, write, authn)
+	pg.GET("/trash", ph.GetTrash, read, authn)
+	pg.GET("/by-slug/:slug", ph.GetBySlug, read, optAuthn)
+	pg.GET("/:id", ph.GetByID, read, optAuthn)
+	pg.PATCH("/:id", ph.Update, write, authn, ph.PostAuthor)
+	pg.DELETE("/:id", ph.DeleteByID, write, authn, ph.PostAuthor)
+	pg.POST("/:id/restore", ph.Restore, write, authn, ph.DeletedPostAuthor)
+	pg.POST("/:id/publish", ph.Publish, write, authn, ph.PostAuthor)
+	pg.POST("/:id/unpublish", ph.Unpublish, write, authn, ph.PostAuthor)
+	pg.POST("/:id/archive", ph.Archive, write, authn, ph.PostAuthor)
+	pg.GET("/:id/revisions", ph.GetRevisions, read, optAuthn, ph.Visible)
+	pg.GET("/:id/revisions/:rev", ph.GetRevision, read, optAuthn, ph.Visible)
+	pg.POST("/:id/revisions/:rev/restore", ph.RestoreRevision, write, authn, ph.PostAuthor)
+	pg.GET("/:id/comments", ch.GetAllByPostID, read, optAuthn, ph.Visible)
+	pg.POST("/:id/comments", ch.CreateByPostID, write, authn, ph.Visible)
+	pg.GET("/:id/reactions", rh.GetAllByPostID, read, optAuthn, ph.Visible)
+	pg.POST("/:id/reactions", rh.AddToPost, write, authn, ph.Visible)
+	pg.DELETE("/:id/reactions/:kind", rh.RemoveFromPost, write, authn)
 
-	ps := api.Group("/posts")
-	ps.GET("", ph.GetAll)
-	ps.POST("", ph.Create, ph.Auth)
-	ps.GET("/:id", ph.GetByID)
-	ps.PATCH("/:id", ph.Update, ph.Auth, ph.PostAuthor)
-	ps.DELETE("/:id", ph.DeleteByID, ph.Auth, ph.PostAuthor)
+	ug := api.Group("/users")
+	ug.PATCH("/me", uh.UpdateMe, write, authn)
+	ug.PUT("/:id/role", uh.SetRole, write, authn, auth.Require(model.PermManageRoles))
+	ug.GET("/:id", uh.GetByID, read)
 
-	cs := api.Group("/comments")
-	cs.GET("", ch.GetAll)
-	cs.POST("", ch.Create, ch.Auth)
-	cs.GET("/:id", ch.GetByID)
-	cs.PATCH("/:id", ch.Update, ch.Auth, ch.CommentAuthor)
-	cs.DELETE("/:id", ch.DeleteByID, ch.Auth, ch.CommentAuthor)
+	manageTaxonomy := auth.Require(model.PermManageTaxonomy)
+	tg := api.Group("/tags")
+	tg.GET("", th.GetAll, read)
+	tg.POST("", th.Create, write, authn, manageTaxonomy)
+	tg.GET("/counts", th.GetCounts, read)
+	tg.GET("/:id", th.GetByID, read)
+	tg.PATCH("/:id", th.Update, write, authn, manageTaxonomy)
+	tg.DELETE("/:id", th.DeleteByID, write, authn, manageTaxonomy)
+
+	kg := api.Group("/categories")
+	kg.GET("", kh.GetAll, read)
+	kg.POST("", kh.Create, write, authn, manageTaxonomy)
+	kg.GET("/:id", kh.GetByID, read)
+	kg.PATCH("/:id", kh.Update, write, authn, manageTaxonomy)
+	kg.DELETE("/:id", kh.DeleteByID, write, authn, manageTaxonomy)
+
+	cg := api.Group("/comments")
+	cg.GET("", ch.GetAll, read, optAuthn)
+	cg.POST("", ch.Create, write, authn)
+	cg.GET("/trash", ch.GetTrash, read, authn)
+	cg.GET("/:id", ch.GetByID, read, optAuthn)
+	cg.PATCH("/:id", ch.Update, write, authn, ch.CommentAuthor)
+	cg.DELETE("/:id", ch.DeleteByID, write, authn, ch.CommentAuthor)
+	cg.POST("/:id/restore", ch.Restore, write, authn, ch.DeletedCommentAuthor)
+	cg.GET("/:id/reactions", rh.GetAllByCommentID, read, optAuthn)
+	cg.POST("/:id/reactions", rh.AddToComment, write, authn)
+	cg.DELETE("/:id/reactions/:kind", rh.RemoveFromComment, write, authn)
 
 	e.Logger.Fatal(e.Start(":8080"))
 }
+
+// migrate migrates database schema to the current models.
+func migrate(db *gorm.DB) error {
+	// Orphaned comments would prevent adding the comments foreign key.
+	if db.Migrator().HasTable(&model.Post{}) && db.Migrator().HasTable(&model.Comment{}) {
+		orphans := db.Unscoped().Where("post_id NOT IN (?)", db.Unscoped().Model(&model.Post{}).Select("id"))
+		if err := orphans.Delete(&model.Comment{}).Error; err != nil {
+			return err
+		}
+	}
+
+	// Users, tags and categories are migrated first, the post and comment
+	// tables reference them.
+	if err := db.AutoMigrate(&model.User{}, &model.Tag{}, &model.Category{}); err != nil {
+		return err
+	}
+
+	// Authors of posts created before users were stored get users without
+	// profiles, the profiles are filled in on their next sign-in.
+	if db.Migrator().HasTable(&model.Post{}) {
+		now := db.NowFunc()
+		err := db.Exec(`INSERT INTO users (id, email, email_verified, name, avatar_url, bio, created_at, updated_at)
+			SELECT DISTINCT user_id, '', false, '', '', '', ?, ? FROM posts
+			WHERE user_id IS NOT NULL AND user_id NOT IN (SELECT id FROM users)`, now, now).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, m := range []interface{}{&model.Post{}, &model.Comment{}} {
+		if err := db.AutoMigrate(m); err != nil {
+			return err
+		}
+
+		// Rows created before timestamps were added get the migration time.
+		now := db.NowFunc()
+		err := db.Unscoped().Model(m).Where("created_at IS NULL").
+			UpdateColumns(map[string]interface{}{"created_at": now, "updated_at": now}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	err := db.AutoMigrate(
+		&model.PostRevision{}, &model.PostSlug{}, &model.Reaction{}, &model.Session{},
+		&model.PasswordCredential{},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Posts created before revisions were added get their current version as
+	// the first revision.
+	err = db.Exec(`INSERT INTO post_revisions (post_id, rev, title, body, user_id, created_at)
+		SELECT id, 1, title, body, user_id, updated_at FROM posts
+		WHERE id NOT IN (SELECT post_id FROM post_revisions)`).Error
+	if err != nil {
+		return err
+	}
+
+	if err := post.BackfillSlugs(db); err != nil {
+		return err
+	}
+
+	return comment.BackfillPaths(db)
+}
+
+// sessionSecret returns the configured access token signing secret, or a
+// random one when it isn't configured.
+func sessionSecret(configured string) ([]byte, error) {
+	if configured != "" {
+		return []byte(configured), nil
+	}
+
+	log.Print("SESSION_SECRET isn't set, sessions won't survive restarts")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// newRegistry registers Google and the configured identity providers.
+func newRegistry(cfg config.Config) (*auth.Registry, error) {
+	callback := func(name string) string {
+		return strings.TrimSuffix(cfg.BaseURL, "/") + "/auth/" + name + "/callback"
+	}
+
+	reg := auth.NewRegistry()
+	reg.Register("google", auth.NewGoogleProvider(cfg.ClientID, cfg.ClientSecret, callback("google")))
+	if cfg.GitHubClientID != "" {
+		reg.Register("github", auth.NewGitHubProvider(
+			cfg.GitHubClientID, cfg.GitHubClientSecret, callback("github"),
+		))
+	}
+	if cfg.OIDCIssuer != "" {
+		p, err := auth.NewOIDCProvider(
+			cfg.OIDCName, cfg.OIDCIssuer, cfg.OIDCClientID, cfg.OIDCClientSecret, callback(cfg.OIDCName),
+		)
+		if err != nil {
+			return nil, err
+		}
+		reg.Register(cfg.OIDCName, p)
+	}
+
+	return reg, nil
+}
+
+// timeout is middleware that cancels request's context after d, so database
+// queries and provider calls of slow requests are abandoned.
+func timeout(d time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx, cancel := context.WithTimeout(c.Request().Context(), d)
+			defer cancel()
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
+// newSearchService creates the search service for the configured engine.
+func newSearchService(db *gorm.DB, engine string) (search.Service, error) {
+	switch engine {
+	case "mysql":
+		return search.NewMySQLService(db)
+	case "memory":
+		return search.NewMemoryService(), nil
+	}
+
+	return nil, fmt.Errorf("unknown search engine %q", engine)
+}
+
+// purgeTrash permanently deletes posts and comments that have been in trash
+// longer than retention and their reactions, and expired sessions, it checks
+// trash every hour.
+func purgeTrash(ps post.Service, cs comment.Service, rs reaction.Service, sn auth.Sessions, retention time.Duration) {
+	t := time.NewTicker(time.Hour)
+	defer t.Stop()
+
+	ctx := context.Background()
+	for ; true; <-t.C {
+		before := time.Now().Add(-retention)
+		if n, err := ps.Purge(ctx, before); err != nil {
+			log.Printf("couldn't purge posts: %v", err)
+		} else if n > 0 {
+			log.Printf("purged %d posts", n)
+		}
+		if n, err := cs.Purge(ctx, before); err != nil {
+			log.Printf("couldn't purge comments: %v", err)
+		} else if n > 0 {
+			log.Printf("purged %d comments", n)
+		}
+		if n, err := rs.PurgeOrphans(ctx); err != nil {
+			log.Printf("couldn't purge reactions: %v", err)
+		} else if n > 0 {
+			log.Printf("purged %d reactions", n)
+		}
+		if n, err := sn.PurgeExpired(ctx); err != nil {
+			log.Printf("couldn't purge sessions: %v", err)
+		} else if n > 0 {
+			log.Printf("purged %d sessions", n)
+		}
+	}
+}
+
+// publishScheduled publishes scheduled posts when they are due, it checks
+// them every interval.
+func publishScheduled(ps post.Service, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for ; true; <-t.C {
+		if published, err := ps.PublishDue(context.Background(), time.Now()); err != nil {
+			log.Printf("couldn't publish scheduled posts: %v", err)
+		} else if len(published) > 0 {
+			log.Printf("published %d scheduled posts", len(published))
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/imarrche/nix-ed/internal/store"
+)
+
+var (
+	// ErrInvalidToken is thrown when a token is malformed, has an invalid
+	// signature or isn't the current token of its session.
+	ErrInvalidToken = errors.New("specified token is invalid")
+	// ErrTokenExpired is thrown when a token is past its expiry.
+	ErrTokenExpired = errors.New("specified token has expired")
+	// ErrSessionRevoked is thrown when the token's session was revoked.
+	ErrSessionRevoked = errors.New("specified session was revoked")
+	// ErrSessionNotFound is thrown when specified session was not found in
+	// database.
+	ErrSessionNotFound = store.New(store.ErrNotFound, "specified session was not found")
+	// ErrEmailTaken is thrown when signing up with an email that already has
+	// a password.
+	ErrEmailTaken = store.New(store.ErrConflict, "specified email is already taken")
+	// ErrInvalidCredentials is thrown when the email or the password doesn't
+	// match.
+	ErrInvalidCredentials = errors.New("specified email or password is invalid")
+)
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// githubAPIURL is the URL of GitHub REST API.
+const githubAPIURL = "https://api.github.com"
+
+// githubUser is GitHub user.
+type githubUser struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// githubEmail is an email of GitHub user.
+type githubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+// GitHubProvider is identity provider for GitHub accounts.
+type GitHubProvider struct {
+	c      *oauth2.Config
+	apiURL string
+}
+
+// NewGitHubProvider creates and returns a new GitHubProvider instance.
+func NewGitHubProvider(clientID, clientSecret, redirectURL string) *GitHubProvider {
+	return &GitHubProvider{
+		c: &oauth2.Config{
+			RedirectURL:  redirectURL,
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Scopes:       []string{"read:user", "user:email"},
+			Endpoint:     github.Endpoint,
+		},
+		apiURL: githubAPIURL,
+	}
+}
+
+// AuthCodeURL returns authentication code URL with the state and the S256
+// PKCE code challenge.
+func (p *GitHubProvider) AuthCodeURL(state, challenge string) string {
+	return authCodeURL(p.c, state, challenge)
+}
+
+// Exchange exchanges the code for an access token and returns the user it
+// belongs to. The user's email is the primary email of the GitHub account.
+func (p *GitHubProvider) Exchange(ctx context.Context, code, verifier string) (model.User, error) {
+	token, err := exchange(ctx, p.c, code, verifier)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return p.UserInfo(ctx, token.AccessToken)
+}
+
+// UserInfo returns the user the access token belongs to.
+func (p *GitHubProvider) UserInfo(ctx context.Context, token string) (model.User, error) {
+	gu := githubUser{}
+	if err := getJSON(ctx, p.apiURL+"/user", token, &gu); err != nil {
+		return model.User{}, err
+	}
+	if gu.ID == 0 {
+		return model.User{}, ErrInvalidToken
+	}
+	var emails []githubEmail
+	if err := getJSON(ctx, p.apiURL+"/user/emails", token, &emails); err != nil {
+		return model.User{}, err
+	}
+
+	u := model.User{
+		ID:        providerUserID("github", strconv.FormatInt(gu.ID, 10)),
+		Name:      gu.Name,
+		AvatarURL: gu.AvatarURL,
+	}
+	if u.Name == "" {
+		u.Name = gu.Login
+	}
+	for _, e := range emails {
+		if e.Primary {
+			u.Email, u.EmailVerified = e.Email, e.Verified
+		}
+	}
+
+	return u, nil
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// newFakeGitHub starts a fake OAuth server with GitHub user API and returns it
+// with a GitHubProvider using it.
+func newFakeGitHub(t *testing.T, user, emails string) (*fakeOAuth, *GitHubProvider) {
+	f := newFakeOAuth(t)
+	serve := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer access-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(body))
+		}
+	}
+	f.mux.HandleFunc("/api/user", serve(user))
+	f.mux.HandleFunc("/api/user/emails", serve(emails))
+
+	return f, &GitHubProvider{c: f.config("github"), apiURL: f.URL + "/api"}
+}
+
+func TestGitHubProvider_Exchange(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    string
+		emails  string
+		expUser model.User
+		expErr  error
+	}{
+		{
+			name:   "primary email is used",
+			user:   `{"id":7,"login":"octo","name":"Octo Cat","avatar_url":"https://t.com/o.png"}`,
+			emails: `[{"email":"other@t.com","verified":true},{"email":"o@t.com","primary":true,"verified":true}]`,
+			expUser: model.User{
+				ID: "github:7", Email: "o@t.com", EmailVerified: true, Name: "Octo Cat", AvatarURL: "https://t.com/o.png",
+			},
+		},
+		{
+			name:    "login is the name of users without names",
+			user:    `{"id":7,"login":"octo"}`,
+			emails:  `[{"email":"o@t.com","primary":true}]`,
+			expUser: model.User{ID: "github:7", Email: "o@t.com", Name: "octo"},
+		},
+		{
+			name:   "users without IDs are rejected",
+			user:   `{}`,
+			emails: `[]`,
+			expErr: ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f, p := newFakeGitHub(t, tc.user, tc.emails)
+			code, _ := f.authorize(t, p.AuthCodeURL("state", codeChallenge("verifier")))
+
+			u, err := p.Exchange(context.Background(), code, "verifier")
+
+			assert.Equal(t, tc.expErr, err)
+			assert.Equal(t, tc.expUser, u)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"context"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// googleUserInfoURL is the URL of Google user info endpoint.
+const googleUserInfoURL = "https://www.googleapis.com/oauth2/v2/userinfo"
+
+// googleUserInfo is Google user info.
+type googleUserInfo struct {
+	ID            string `json:"id"`
+	Email         string `json:"email"`
+	VerifiedEmail bool   `json:"verified_email"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+}
+
+// GoogleProvider is identity provider for Google accounts.
+type GoogleProvider struct {
+	c           *oauth2.Config
+	userInfoURL string
+}
+
+// NewGoogleProvider creates and returns a new GoogleProvider instance.
+func NewGoogleProvider(clientID, clientSecret, redirectURL string) *GoogleProvider {
+	return &GoogleProvider{
+		c: &oauth2.Config{
+			RedirectURL:  redirectURL,
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Scopes: []string{
+				"https://www.googleapis.com/auth/userinfo.email",
+				"https://www.googleapis.com/auth/userinfo.profile",
+			},
+			Endpoint: google.Endpoint,
+		},
+		userInfoURL: googleUserInfoURL,
+	}
+}
+
+// AuthCodeURL returns authentication code URL with the state and the S256
+// PKCE code challenge.
+func (p *GoogleProvider) AuthCodeURL(state, challenge string) string {
+	return authCodeURL(p.c, state, challenge)
+}
+
+// Exchange exchanges the code for an access token and returns the user it
+// belongs to. Google users keep their Google account IDs as user IDs.
+func (p *GoogleProvider) Exchange(ctx context.Context, code, verifier string) (model.User, error) {
+	token, err := exchange(ctx, p.c, code, verifier)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return p.UserInfo(ctx, token.AccessToken)
+}
+
+// UserInfo returns the user the access token belongs to.
+func (p *GoogleProvider) UserInfo(ctx context.Context, token string) (model.User, error) {
+	info := googleUserInfo{}
+	if err := getJSON(ctx, p.userInfoURL, token, &info); err != nil {
+		return model.User{}, err
+	}
+	if info.ID == "" {
+		return model.User{}, ErrInvalidToken
+	}
+
+	return model.User{
+		ID:            info.ID,
+		Email:         info.Email,
+		EmailVerified: info.VerifiedEmail,
+		Name:          info.Name,
+		AvatarURL:     info.Picture,
+	}, nil
+}
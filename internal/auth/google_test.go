@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// newFakeGoogle starts a fake OAuth server with Google user info and returns
+// it with a GoogleProvider using it.
+func newFakeGoogle(t *testing.T) (*fakeOAuth, *GoogleProvider) {
+	f := newFakeOAuth(t)
+	f.mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id":"1","email":"u@t.com","verified_email":true,"name":"User","picture":"https://t.com/a.png"}`))
+	})
+
+	return f, &GoogleProvider{c: f.config("google"), userInfoURL: f.URL + "/userinfo"}
+}
+
+func TestGoogleProvider_Exchange(t *testing.T) {
+	f, p := newFakeGoogle(t)
+	verifier := "verifier-verifier-verifier-verifier-verifier"
+
+	code, state := f.authorize(t, p.AuthCodeURL("state", codeChallenge(verifier)))
+	assert.Equal(t, "state", state)
+
+	u, err := p.Exchange(context.Background(), code, verifier)
+	assert.NoError(t, err)
+	assert.Equal(t, model.User{
+		ID: "1", Email: "u@t.com", EmailVerified: true, Name: "User", AvatarURL: "https://t.com/a.png",
+	}, u)
+
+	_, err = p.UserInfo(context.Background(), "other-token")
+	assert.Error(t, err)
+}
+
+func TestGoogleProvider_Exchange_WrongVerifier(t *testing.T) {
+	f, p := newFakeGoogle(t)
+
+	code, _ := f.authorize(t, p.AuthCodeURL("state", codeChallenge("verifier")))
+	_, err := p.Exchange(context.Background(), code, "other-verifier")
+
+	assert.Error(t, err)
+}
@@ -1,44 +1,178 @@
 package auth
 
 import (
+	"context"
 	"net/http"
 
+	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/labstack/echo/v4"
 
-	"github.com/imarrche/nix-ed/internal/config"
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/render"
 )
 
 // Handler is http handler for authorization/authentication.
 type Handler struct {
-	s Service
+	reg *Registry
+	pw  Passwords
+	us  Users
+	ss  Sessions
 }
 
-// NewHandler creates and returns a new Handler instance.
-func NewHandler(s Service) *Handler {
-	return &Handler{s}
+// NewHandler creates and returns a new Handler instance, users sign in with
+// providers of reg or with passwords of pw, are stored by us and get
+// sessions from ss.
+func NewHandler(reg *Registry, pw Passwords, us Users, ss Sessions) *Handler {
+	return &Handler{reg: reg, pw: pw, us: us, ss: ss}
 }
 
-// respond responds to request with XML or JSON.
-func respond(c echo.Context, code int, data interface{}) error {
-	if c.Request().Header.Get("Accept-Encoding") == "text/xml" {
-		return c.XML(code, data)
-	}
+type refreshRequest struct {
+	RefreshToken string `json:"refreshToken" xml:"refreshToken"`
+}
 
-	return c.JSON(code, data)
+// provider returns the provider of the request's provider param and the path
+// of its routes.
+func (h *Handler) provider(c echo.Context) (Provider, string, bool) {
+	name := c.Param("provider")
+	p, ok := h.reg.Get(name)
+
+	return p, "/auth/" + name, ok
 }
 
-// GoogleSignIn is google sign in handler.
-func (h *Handler) GoogleSignIn(c echo.Context) error {
-	url := h.s.AuthCodeURL(config.Get().AuthCodeURL)
+// SignIn is sign in handler, it binds a random state and a PKCE code
+// verifier to the browser before redirecting to the provider.
+func (h *Handler) SignIn(c echo.Context) error {
+	p, path, ok := h.provider(c)
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	state, verifier, err := startFlow(c, path)
+	if err != nil {
+		return err
+	}
+
+	url := p.AuthCodeURL(state, codeChallenge(verifier))
 	return c.Redirect(http.StatusTemporaryRedirect, url)
 }
 
-// GoogleCallback handles redirect after signing in and returns an access token.
-func (h *Handler) GoogleCallback(c echo.Context) error {
-	token, err := h.s.GetAccessToken(c.FormValue("code"))
-	if err != nil {
+// Callback handles redirect after signing in with the provider, stores the
+// user and returns tokens of a new session as JSON, browsers landing on it
+// send Accept headers preferring XML. Callbacks which state doesn't match the
+// sign-in cookie are rejected.
+func (h *Handler) Callback(c echo.Context) error {
+	p, path, ok := h.provider(c)
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	verifier, ok := finishFlow(c, path)
+	if !ok {
+		return echo.NewHTTPError(http.StatusForbidden)
+	}
+
+	u, err := p.Exchange(c.Request().Context(), c.FormValue("code"), verifier)
+	if err == ErrInvalidToken || err == ErrTokenExpired {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	} else if err != nil {
 		return c.Redirect(http.StatusTemporaryRedirect, "/")
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{"token": token})
+	tp, err := h.session(c.Request().Context(), u)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, tp)
+}
+
+// PasswordSignUp signs up a user with an email and a password and returns
+// tokens of a new session.
+func (h *Handler) PasswordSignUp(c echo.Context) error {
+	req := model.PasswordSignUp{}
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	u, err := h.pw.SignUp(c.Request().Context(), req)
+	if err == ErrEmailTaken {
+		return echo.NewHTTPError(http.StatusConflict, err)
+	} else if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+
+	return h.signIn(c, http.StatusCreated, u)
+}
+
+// PasswordSignIn signs in a user with an email and a password and returns
+// tokens of a new session.
+func (h *Handler) PasswordSignIn(c echo.Context) error {
+	req := model.PasswordSignIn{}
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	u, err := h.pw.SignIn(c.Request().Context(), req)
+	if err == ErrInvalidCredentials {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	} else if err != nil {
+		return err
+	}
+
+	return h.signIn(c, http.StatusOK, u)
+}
+
+// signIn stores the user and responds with tokens of a new session.
+func (h *Handler) signIn(c echo.Context, code int, u model.User) error {
+	tp, err := h.session(c.Request().Context(), u)
+	if err != nil {
+		return err
+	}
+
+	return render.Respond(c, code, tp)
+}
+
+// session stores the user and returns tokens of their new session.
+func (h *Handler) session(ctx context.Context, u model.User) (model.TokenPair, error) {
+	u, err := h.us.SignIn(ctx, u)
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+
+	return h.ss.Issue(ctx, u)
+}
+
+// Refresh returns new tokens of a session.
+func (h *Handler) Refresh(c echo.Context) error {
+	req := refreshRequest{}
+	if err := c.Bind(&req); err != nil || req.RefreshToken == "" {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	tp, err := h.ss.Refresh(c.Request().Context(), req.RefreshToken)
+	if err == ErrInvalidToken || err == ErrTokenExpired || err == ErrSessionRevoked {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusOK, tp)
+}
+
+// SignOut revokes the session of the request's access token.
+func (h *Handler) SignOut(c echo.Context) error {
+	p, ok := PrincipalFrom(c)
+	if !ok {
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	if err := h.ss.Revoke(c.Request().Context(), p.SessionID); err == ErrSessionNotFound {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	} else if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
 }
@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/handlertest"
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// stubUsers stores signed in users in memory.
+type stubUsers struct {
+	users []model.User
+}
+
+func (s *stubUsers) SignIn(_ context.Context, u model.User) (model.User, error) {
+	s.users = append(s.users, u)
+
+	return u, nil
+}
+
+// stubSessions issues tokens named after users.
+type stubSessions struct {
+	Sessions
+}
+
+func (stubSessions) Issue(_ context.Context, u model.User) (model.TokenPair, error) {
+	return model.TokenPair{AccessToken: "access-" + u.ID, RefreshToken: "refresh-" + u.ID, TokenType: tokenType}, nil
+}
+
+// providerContext returns the context of the request to the provider's route.
+func providerContext(r *http.Request, w http.ResponseWriter, provider string) echo.Context {
+	c := echo.New().NewContext(r, w)
+	c.SetParamNames("provider")
+	c.SetParamValues(provider)
+
+	return c
+}
+
+// signIn requests the sign-in redirect and returns its location and the
+// sign-in cookie.
+func signIn(t *testing.T, h *Handler) (*url.URL, *http.Cookie) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/auth/google/sign-in", nil)
+
+	assert.NoError(t, h.SignIn(providerContext(r, w, "google")))
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+
+	loc, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != flowCookie {
+		t.Fatalf("sign-in cookie is not set: %v", cookies)
+	}
+	assert.True(t, cookies[0].HttpOnly)
+	assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+	assert.Equal(t, "/auth/google", cookies[0].Path)
+
+	return loc, cookies[0]
+}
+
+// browserAccept is the Accept header browsers send when following redirects.
+const browserAccept = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+// callback requests the callback with the code, the state and the cookie like
+// a browser redirected by the provider.
+func callback(h *Handler, code, state string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	q := url.Values{"code": {code}, "state": {state}}
+	r := httptest.NewRequest(http.MethodGet, "/auth/google/callback?"+q.Encode(), nil)
+	r.Header.Set(echo.HeaderAccept, browserAccept)
+	if cookie != nil {
+		r.AddCookie(cookie)
+	}
+
+	handlertest.Serve(providerContext(r, w, "google"), h.Callback)
+
+	return w
+}
+
+// newTestHandler returns a handler with the provider registered as google.
+func newTestHandler(t *testing.T, p Provider, us Users) *Handler {
+	reg := NewRegistry()
+	reg.Register("google", p)
+
+	return NewHandler(reg, newTestPasswords(t), us, stubSessions{})
+}
+
+func TestHandler_SignIn_Callback(t *testing.T) {
+	f, p := newFakeGoogle(t)
+	us := &stubUsers{}
+	h := newTestHandler(t, p, us)
+
+	loc, cookie := signIn(t, h)
+	assert.NotEqual(t, "", loc.Query().Get("state"))
+	code, state := f.authorize(t, loc.String())
+
+	w := callback(h, code, state, cookie)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, echo.MIMEApplicationJSONCharsetUTF8, w.Header().Get(echo.HeaderContentType))
+	tp := model.TokenPair{}
+	json.NewDecoder(w.Body).Decode(&tp)
+	assert.Equal(t, "access-1", tp.AccessToken)
+	assert.Equal(t, []model.User{
+		{ID: "1", Email: "u@t.com", EmailVerified: true, Name: "User", AvatarURL: "https://t.com/a.png"},
+	}, us.users)
+
+	// The sign-in cookie is cleared, so the callback can't be replayed.
+	cleared := w.Result().Cookies()
+	if assert.Len(t, cleared, 1) {
+		assert.Equal(t, flowCookie, cleared[0].Name)
+		assert.True(t, cleared[0].MaxAge < 0)
+	}
+}
+
+func TestHandler_SignIn_UnknownProvider(t *testing.T) {
+	_, p := newFakeGoogle(t)
+	h := newTestHandler(t, p, &stubUsers{})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/auth/other/sign-in", nil)
+	handlertest.Serve(providerContext(r, w, "other"), h.SignIn)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/auth/other/callback", nil)
+	handlertest.Serve(providerContext(r, w, "other"), h.Callback)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandler_Callback_Rejected(t *testing.T) {
+	f, p := newFakeGoogle(t)
+	h := newTestHandler(t, p, &stubUsers{})
+
+	loc, cookie := signIn(t, h)
+	code, state := f.authorize(t, loc.String())
+
+	// Another browser's cookie, a forged state and a missing cookie are
+	// rejected before the code is exchanged.
+	_, otherCookie := signIn(t, h)
+	assert.Equal(t, http.StatusForbidden, callback(h, code, state, otherCookie).Code)
+	assert.Equal(t, http.StatusForbidden, callback(h, code, "forged", cookie).Code)
+	assert.Equal(t, http.StatusForbidden, callback(h, code, state, nil).Code)
+
+	// A cookie with another code verifier fails the code exchange.
+	tampered := *cookie
+	tampered.Value = state + ".other-verifier"
+	assert.Equal(t, http.StatusTemporaryRedirect, callback(h, code, state, &tampered).Code)
+}
+
+func TestHandler_Password(t *testing.T) {
+	_, p := newFakeGoogle(t)
+	us := &stubUsers{}
+	h := newTestHandler(t, p, us)
+	post := func(handler echo.HandlerFunc, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		handlertest.Serve(echo.New().NewContext(r, w), handler)
+
+		return w
+	}
+
+	w := post(h.PasswordSignUp, `{"email":"u@t.com","password":"password","name":"User"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	if assert.Len(t, us.users, 1) {
+		assert.Equal(t, "User", us.users[0].Name)
+		assert.False(t, us.users[0].EmailVerified)
+	}
+
+	assert.Equal(t, http.StatusConflict, post(h.PasswordSignUp, `{"email":"u@t.com","password":"password"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, post(h.PasswordSignUp, `{"email":"u@t.com","password":"short"}`).Code)
+
+	w = post(h.PasswordSignIn, `{"email":"u@t.com","password":"password"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	tp := model.TokenPair{}
+	json.NewDecoder(w.Body).Decode(&tp)
+	assert.Equal(t, "access-"+us.users[0].ID, tp.AccessToken)
+
+	assert.Equal(t, http.StatusUnauthorized, post(h.PasswordSignIn, `{"email":"u@t.com","password":"other-password"}`).Code)
+}
@@ -1,10 +1,53 @@
 package auth
 
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
 //go:generate mockgen -source=interface.go -destination=mock/mock.go
 
-// Service is the interface all authorization/authentication services must implement.
-type Service interface {
-	AuthCodeURL(string) string
-	GetAccessToken(code string) (string, error)
-	GetUserInfo(token string) ([]byte, error)
+// Provider is the interface all identity providers must implement.
+type Provider interface {
+	AuthCodeURL(state, challenge string) string
+	Exchange(ctx context.Context, code, verifier string) (model.User, error)
+}
+
+// CredentialRepo is the interface all password credential repositories must
+// implement.
+type CredentialRepo interface {
+	Create(context.Context, model.PasswordCredential) (model.PasswordCredential, error)
+	GetByEmail(context.Context, string) (model.PasswordCredential, error)
+}
+
+// Passwords is the interface all password services must implement.
+type Passwords interface {
+	SignUp(context.Context, model.PasswordSignUp) (model.User, error)
+	SignIn(context.Context, model.PasswordSignIn) (model.User, error)
+}
+
+// Users is the interface of services storing signed in users.
+type Users interface {
+	SignIn(context.Context, model.User) (model.User, error)
+}
+
+// SessionRepo is the interface all session repositories must implement.
+type SessionRepo interface {
+	Create(context.Context, model.Session) (model.Session, error)
+	GetByID(context.Context, string) (model.Session, error)
+	GetByRefreshHash(context.Context, string) (model.Session, error)
+	Rotate(context.Context, model.Session, string) (model.Session, error)
+	Revoke(context.Context, string) error
+	PurgeExpired(context.Context, time.Time) (int64, error)
+}
+
+// Sessions is the interface all session services must implement.
+type Sessions interface {
+	Issue(context.Context, model.User) (model.TokenPair, error)
+	Verify(context.Context, string) (Claims, error)
+	Refresh(context.Context, string) (model.TokenPair, error)
+	Revoke(context.Context, string) error
+	PurgeExpired(context.Context) (int64, error)
 }
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+type key int
+
+const (
+	principalKey key = iota
+)
+
+// Principal is the authenticated user who made a request.
+type Principal struct {
+	ID    string
+	Email string
+	Roles []string
+
+	// SessionID is the session of the request's access token.
+	SessionID string
+}
+
+// Can returns whether any of the principal's roles has the permission.
+func (p Principal) Can(perm model.Permission) bool {
+	for _, r := range p.Roles {
+		if model.RoleCan(r, perm) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// principal returns the principal of the access token claims.
+func principal(cl Claims) Principal {
+	p := Principal{ID: cl.Subject, Email: cl.Email, SessionID: cl.SessionID}
+	if cl.Role != "" {
+		p.Roles = []string{cl.Role}
+	}
+
+	return p
+}
+
+// WithPrincipal returns a copy of the context with the principal.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey, p)
+}
+
+// PrincipalFrom returns the principal who made the request, it's false for
+// anonymous requests.
+func PrincipalFrom(c echo.Context) (Principal, bool) {
+	p, ok := c.Request().Context().Value(principalKey).(Principal)
+
+	return p, ok
+}
+
+// Viewer returns ID of the user who made a request, it's empty for anonymous
+// requests.
+func Viewer(c echo.Context) string {
+	return ViewerFrom(c.Request().Context())
+}
+
+// ViewerFrom returns ID of the user who made the request of the context, it's
+// empty for anonymous requests.
+func ViewerFrom(ctx context.Context) string {
+	p, _ := ctx.Value(principalKey).(Principal)
+
+	return p.ID
+}
+
+// Can returns whether the principal who made the request has the permission.
+func Can(c echo.Context, perm model.Permission) bool {
+	p, ok := PrincipalFrom(c)
+
+	return ok && p.Can(perm)
+}
+
+// Authenticate returns middleware that verifies the access token of the
+// Authorization header and puts its principal in the request context.
+func Authenticate(ss Sessions) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token := BearerToken(c.Request().Header.Get("Authorization"))
+			if token == "" {
+				c.Response().Header().Set("WWW-Authenticate", tokenType)
+				return echo.NewHTTPError(http.StatusUnauthorized)
+			}
+
+			cl, err := ss.Verify(c.Request().Context(), token)
+			if err == ErrInvalidToken || err == ErrTokenExpired || err == ErrSessionRevoked {
+				c.Response().Header().Set("WWW-Authenticate", tokenType+` error="invalid_token"`)
+				return echo.NewHTTPError(http.StatusUnauthorized, err)
+			} else if err != nil {
+				return err
+			}
+
+			r := c.Request()
+			c.SetRequest(r.WithContext(WithPrincipal(r.Context(), principal(cl))))
+			return next(c)
+		}
+	}
+}
+
+// OptionalAuthenticate returns middleware like Authenticate that lets
+// requests without credentials through anonymously.
+func OptionalAuthenticate(ss Sessions) echo.MiddlewareFunc {
+	authenticate := Authenticate(ss)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		auth := authenticate(next)
+
+		return func(c echo.Context) error {
+			if c.Request().Header.Get("Authorization") == "" {
+				return next(c)
+			}
+
+			return auth(c)
+		}
+	}
+}
+
+// Require returns middleware that lets through only authenticated requests
+// which principal has the permission, it must run after Authenticate.
+func Require(perm model.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if _, ok := PrincipalFrom(c); !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized)
+			}
+			if !Can(c, perm) {
+				return echo.NewHTTPError(http.StatusForbidden)
+			}
+
+			return next(c)
+		}
+	}
+}
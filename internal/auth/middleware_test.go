@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/handlertest"
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// verifySessions verifies the "token" access token with the claims or the
+// error.
+type verifySessions struct {
+	Sessions
+	claims Claims
+	err    error
+}
+
+func (s verifySessions) Verify(_ context.Context, token string) (Claims, error) {
+	if token != "token" {
+		return Claims{}, ErrInvalidToken
+	}
+
+	return s.claims, s.err
+}
+
+func TestAuthenticate(t *testing.T) {
+	testcases := []struct {
+		name         string
+		ss           verifySessions
+		header       string
+		expCode      int
+		expPrincipal Principal
+	}{
+		{
+			name:    "user is authenticated",
+			ss:      verifySessions{claims: Claims{Subject: "1", Email: "u@t.com", SessionID: "s", Role: model.RoleModerator}},
+			header:  "Bearer token",
+			expCode: http.StatusOK,
+			expPrincipal: Principal{
+				ID: "1", Email: "u@t.com", Roles: []string{model.RoleModerator}, SessionID: "s",
+			},
+		},
+		{
+			name:    "token is missing",
+			expCode: http.StatusUnauthorized,
+		},
+		{
+			name:    "token is invalid",
+			header:  "Bearer other",
+			expCode: http.StatusUnauthorized,
+		},
+		{
+			name:    "token is expired",
+			ss:      verifySessions{err: ErrTokenExpired},
+			header:  "Bearer token",
+			expCode: http.StatusUnauthorized,
+		},
+		{
+			name:    "session is revoked",
+			ss:      verifySessions{err: ErrSessionRevoked},
+			header:  "token",
+			expCode: http.StatusUnauthorized,
+		},
+		{
+			name:    "session store error",
+			ss:      verifySessions{err: errors.New("internal error")},
+			header:  "Bearer token",
+			expCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got Principal
+			next := func(c echo.Context) error {
+				got, _ = PrincipalFrom(c)
+				return c.NoContent(http.StatusOK)
+			}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/posts", nil)
+			if tc.header != "" {
+				r.Header.Set("Authorization", tc.header)
+			}
+
+			handlertest.Serve(echo.New().NewContext(r, w), Authenticate(tc.ss)(next))
+
+			assert.Equal(t, tc.expCode, w.Code)
+			assert.Equal(t, tc.expPrincipal, got)
+			if tc.expCode == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestOptionalAuthenticate(t *testing.T) {
+	next := func(c echo.Context) error {
+		return c.String(http.StatusOK, Viewer(c))
+	}
+	ss := verifySessions{claims: Claims{Subject: "1", SessionID: "s"}}
+	get := func(header string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/posts", nil)
+		if header != "" {
+			r.Header.Set("Authorization", header)
+		}
+		handlertest.Serve(echo.New().NewContext(r, w), OptionalAuthenticate(ss)(next))
+
+		return w
+	}
+
+	w := get("")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "", w.Body.String())
+
+	w = get("Bearer token")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Body.String())
+
+	assert.Equal(t, http.StatusUnauthorized, get("Bearer other").Code)
+}
+
+func TestRequire(t *testing.T) {
+	next := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+
+	testcases := []struct {
+		name      string
+		principal *Principal
+		expCode   int
+	}{
+		{
+			name:      "role has the permission",
+			principal: &Principal{ID: "1", Roles: []string{model.RoleAdmin}},
+			expCode:   http.StatusOK,
+		},
+		{
+			name:      "role doesn't have the permission",
+			principal: &Principal{ID: "1", Roles: []string{model.RoleModerator}},
+			expCode:   http.StatusForbidden,
+		},
+		{
+			name:      "principal has no roles",
+			principal: &Principal{ID: "1"},
+			expCode:   http.StatusForbidden,
+		},
+		{
+			name:    "request isn't authenticated",
+			expCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, "/users/2/role", nil)
+			if tc.principal != nil {
+				r = r.WithContext(WithPrincipal(r.Context(), *tc.principal))
+			}
+
+			handlertest.Serve(echo.New().NewContext(r, w), Require(model.PermManageRoles)(next))
+
+			assert.Equal(t, tc.expCode, w.Code)
+		})
+	}
+}
@@ -5,73 +5,415 @@
 package mock_auth
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
+	auth "github.com/imarrche/nix-ed/internal/auth"
+	model "github.com/imarrche/nix-ed/internal/model"
 	reflect "reflect"
+	time "time"
 )
 
-// MockService is a mock of Service interface
-type MockService struct {
+// MockProvider is a mock of Provider interface
+type MockProvider struct {
 	ctrl     *gomock.Controller
-	recorder *MockServiceMockRecorder
+	recorder *MockProviderMockRecorder
 }
 
-// MockServiceMockRecorder is the mock recorder for MockService
-type MockServiceMockRecorder struct {
-	mock *MockService
+// MockProviderMockRecorder is the mock recorder for MockProvider
+type MockProviderMockRecorder struct {
+	mock *MockProvider
 }
 
-// NewMockService creates a new mock instance
-func NewMockService(ctrl *gomock.Controller) *MockService {
-	mock := &MockService{ctrl: ctrl}
-	mock.recorder = &MockServiceMockRecorder{mock}
+// NewMockProvider creates a new mock instance
+func NewMockProvider(ctrl *gomock.Controller) *MockProvider {
+	mock := &MockProvider{ctrl: ctrl}
+	mock.recorder = &MockProviderMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockService) EXPECT() *MockServiceMockRecorder {
+func (m *MockProvider) EXPECT() *MockProviderMockRecorder {
 	return m.recorder
 }
 
 // AuthCodeURL mocks base method
-func (m *MockService) AuthCodeURL(arg0 string) string {
+func (m *MockProvider) AuthCodeURL(state, challenge string) string {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AuthCodeURL", arg0)
+	ret := m.ctrl.Call(m, "AuthCodeURL", state, challenge)
 	ret0, _ := ret[0].(string)
 	return ret0
 }
 
 // AuthCodeURL indicates an expected call of AuthCodeURL
-func (mr *MockServiceMockRecorder) AuthCodeURL(arg0 interface{}) *gomock.Call {
+func (mr *MockProviderMockRecorder) AuthCodeURL(state, challenge interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthCodeURL", reflect.TypeOf((*MockService)(nil).AuthCodeURL), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthCodeURL", reflect.TypeOf((*MockProvider)(nil).AuthCodeURL), state, challenge)
 }
 
-// GetAccessToken mocks base method
-func (m *MockService) GetAccessToken(code string) (string, error) {
+// Exchange mocks base method
+func (m *MockProvider) Exchange(ctx context.Context, code, verifier string) (model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccessToken", code)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Exchange", ctx, code, verifier)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange
+func (mr *MockProviderMockRecorder) Exchange(ctx, code, verifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockProvider)(nil).Exchange), ctx, code, verifier)
+}
+
+// MockCredentialRepo is a mock of CredentialRepo interface
+type MockCredentialRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockCredentialRepoMockRecorder
+}
+
+// MockCredentialRepoMockRecorder is the mock recorder for MockCredentialRepo
+type MockCredentialRepoMockRecorder struct {
+	mock *MockCredentialRepo
+}
+
+// NewMockCredentialRepo creates a new mock instance
+func NewMockCredentialRepo(ctrl *gomock.Controller) *MockCredentialRepo {
+	mock := &MockCredentialRepo{ctrl: ctrl}
+	mock.recorder = &MockCredentialRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCredentialRepo) EXPECT() *MockCredentialRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockCredentialRepo) Create(arg0 context.Context, arg1 model.PasswordCredential) (model.PasswordCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(model.PasswordCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockCredentialRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCredentialRepo)(nil).Create), arg0, arg1)
+}
+
+// GetByEmail mocks base method
+func (m *MockCredentialRepo) GetByEmail(arg0 context.Context, arg1 string) (model.PasswordCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByEmail", arg0, arg1)
+	ret0, _ := ret[0].(model.PasswordCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByEmail indicates an expected call of GetByEmail
+func (mr *MockCredentialRepoMockRecorder) GetByEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockCredentialRepo)(nil).GetByEmail), arg0, arg1)
+}
+
+// MockPasswords is a mock of Passwords interface
+type MockPasswords struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordsMockRecorder
+}
+
+// MockPasswordsMockRecorder is the mock recorder for MockPasswords
+type MockPasswordsMockRecorder struct {
+	mock *MockPasswords
+}
+
+// NewMockPasswords creates a new mock instance
+func NewMockPasswords(ctrl *gomock.Controller) *MockPasswords {
+	mock := &MockPasswords{ctrl: ctrl}
+	mock.recorder = &MockPasswordsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPasswords) EXPECT() *MockPasswordsMockRecorder {
+	return m.recorder
+}
+
+// SignUp mocks base method
+func (m *MockPasswords) SignUp(arg0 context.Context, arg1 model.PasswordSignUp) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignUp", arg0, arg1)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignUp indicates an expected call of SignUp
+func (mr *MockPasswordsMockRecorder) SignUp(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignUp", reflect.TypeOf((*MockPasswords)(nil).SignUp), arg0, arg1)
+}
+
+// SignIn mocks base method
+func (m *MockPasswords) SignIn(arg0 context.Context, arg1 model.PasswordSignIn) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignIn", arg0, arg1)
+	ret0, _ := ret[0].(model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccessToken indicates an expected call of GetAccessToken
-func (mr *MockServiceMockRecorder) GetAccessToken(code interface{}) *gomock.Call {
+// SignIn indicates an expected call of SignIn
+func (mr *MockPasswordsMockRecorder) SignIn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignIn", reflect.TypeOf((*MockPasswords)(nil).SignIn), arg0, arg1)
+}
+
+// MockUsers is a mock of Users interface
+type MockUsers struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsersMockRecorder
+}
+
+// MockUsersMockRecorder is the mock recorder for MockUsers
+type MockUsersMockRecorder struct {
+	mock *MockUsers
+}
+
+// NewMockUsers creates a new mock instance
+func NewMockUsers(ctrl *gomock.Controller) *MockUsers {
+	mock := &MockUsers{ctrl: ctrl}
+	mock.recorder = &MockUsersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockUsers) EXPECT() *MockUsersMockRecorder {
+	return m.recorder
+}
+
+// SignIn mocks base method
+func (m *MockUsers) SignIn(arg0 context.Context, arg1 model.User) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignIn", arg0, arg1)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignIn indicates an expected call of SignIn
+func (mr *MockUsersMockRecorder) SignIn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignIn", reflect.TypeOf((*MockUsers)(nil).SignIn), arg0, arg1)
+}
+
+// MockSessionRepo is a mock of SessionRepo interface
+type MockSessionRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionRepoMockRecorder
+}
+
+// MockSessionRepoMockRecorder is the mock recorder for MockSessionRepo
+type MockSessionRepoMockRecorder struct {
+	mock *MockSessionRepo
+}
+
+// NewMockSessionRepo creates a new mock instance
+func NewMockSessionRepo(ctrl *gomock.Controller) *MockSessionRepo {
+	mock := &MockSessionRepo{ctrl: ctrl}
+	mock.recorder = &MockSessionRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSessionRepo) EXPECT() *MockSessionRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockSessionRepo) Create(arg0 context.Context, arg1 model.Session) (model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockSessionRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionRepo)(nil).Create), arg0, arg1)
+}
+
+// GetByID mocks base method
+func (m *MockSessionRepo) GetByID(arg0 context.Context, arg1 string) (model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
+	ret0, _ := ret[0].(model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID
+func (mr *MockSessionRepoMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockSessionRepo)(nil).GetByID), arg0, arg1)
+}
+
+// GetByRefreshHash mocks base method
+func (m *MockSessionRepo) GetByRefreshHash(arg0 context.Context, arg1 string) (model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByRefreshHash", arg0, arg1)
+	ret0, _ := ret[0].(model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByRefreshHash indicates an expected call of GetByRefreshHash
+func (mr *MockSessionRepoMockRecorder) GetByRefreshHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByRefreshHash", reflect.TypeOf((*MockSessionRepo)(nil).GetByRefreshHash), arg0, arg1)
+}
+
+// Rotate mocks base method
+func (m *MockSessionRepo) Rotate(arg0 context.Context, arg1 model.Session, arg2 string) (model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rotate indicates an expected call of Rotate
+func (mr *MockSessionRepoMockRecorder) Rotate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockSessionRepo)(nil).Rotate), arg0, arg1, arg2)
+}
+
+// Revoke mocks base method
+func (m *MockSessionRepo) Revoke(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke
+func (mr *MockSessionRepoMockRecorder) Revoke(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockSessionRepo)(nil).Revoke), arg0, arg1)
+}
+
+// PurgeExpired mocks base method
+func (m *MockSessionRepo) PurgeExpired(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired
+func (mr *MockSessionRepoMockRecorder) PurgeExpired(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockSessionRepo)(nil).PurgeExpired), arg0, arg1)
+}
+
+// MockSessions is a mock of Sessions interface
+type MockSessions struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionsMockRecorder
+}
+
+// MockSessionsMockRecorder is the mock recorder for MockSessions
+type MockSessionsMockRecorder struct {
+	mock *MockSessions
+}
+
+// NewMockSessions creates a new mock instance
+func NewMockSessions(ctrl *gomock.Controller) *MockSessions {
+	mock := &MockSessions{ctrl: ctrl}
+	mock.recorder = &MockSessionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSessions) EXPECT() *MockSessionsMockRecorder {
+	return m.recorder
+}
+
+// Issue mocks base method
+func (m *MockSessions) Issue(arg0 context.Context, arg1 model.User) (model.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Issue", arg0, arg1)
+	ret0, _ := ret[0].(model.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Issue indicates an expected call of Issue
+func (mr *MockSessionsMockRecorder) Issue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issue", reflect.TypeOf((*MockSessions)(nil).Issue), arg0, arg1)
+}
+
+// Verify mocks base method
+func (m *MockSessions) Verify(arg0 context.Context, arg1 string) (auth.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", arg0, arg1)
+	ret0, _ := ret[0].(auth.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify
+func (mr *MockSessionsMockRecorder) Verify(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockSessions)(nil).Verify), arg0, arg1)
+}
+
+// Refresh mocks base method
+func (m *MockSessions) Refresh(arg0 context.Context, arg1 string) (model.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", arg0, arg1)
+	ret0, _ := ret[0].(model.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh
+func (mr *MockSessionsMockRecorder) Refresh(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockSessions)(nil).Refresh), arg0, arg1)
+}
+
+// Revoke mocks base method
+func (m *MockSessions) Revoke(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke
+func (mr *MockSessionsMockRecorder) Revoke(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessToken", reflect.TypeOf((*MockService)(nil).GetAccessToken), code)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockSessions)(nil).Revoke), arg0, arg1)
 }
 
-// GetUserInfo mocks base method
-func (m *MockService) GetUserInfo(token string) ([]byte, error) {
+// PurgeExpired mocks base method
+func (m *MockSessions) PurgeExpired(arg0 context.Context) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserInfo", token)
-	ret0, _ := ret[0].([]byte)
+	ret := m.ctrl.Call(m, "PurgeExpired", arg0)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserInfo indicates an expected call of GetUserInfo
-func (mr *MockServiceMockRecorder) GetUserInfo(token interface{}) *gomock.Call {
+// PurgeExpired indicates an expected call of PurgeExpired
+func (mr *MockSessionsMockRecorder) PurgeExpired(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserInfo", reflect.TypeOf((*MockService)(nil).GetUserInfo), token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockSessions)(nil).PurgeExpired), arg0)
 }
@@ -0,0 +1,272 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// discoveryPath is the path of OpenID Connect discovery documents relative
+// to issuers.
+const discoveryPath = "/.well-known/openid-configuration"
+
+// oidcDiscovery is the part of OpenID Connect discovery document providers
+// use.
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// jwks is JSON Web Key Set.
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// idTokenHeader is the header of ID tokens.
+type idTokenHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// audience is the aud claim, a single string or a list of them.
+type audience []string
+
+// UnmarshalJSON unmarshals the audience from a string or a list of strings.
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+
+	var l []string
+	if err := json.Unmarshal(data, &l); err != nil {
+		return err
+	}
+	*a = l
+
+	return nil
+}
+
+// contains returns whether the audience contains the client ID.
+func (a audience) contains(clientID string) bool {
+	for _, aud := range a {
+		if aud == clientID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// idTokenClaims are the claims of ID tokens.
+type idTokenClaims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      audience `json:"aud"`
+	ExpiresAt     int64    `json:"exp"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+	Name          string   `json:"name"`
+	Picture       string   `json:"picture"`
+}
+
+// OIDCProvider is identity provider for any OpenID Connect issuer. Users are
+// taken from ID tokens verified with the issuer's published keys.
+type OIDCProvider struct {
+	name    string
+	issuer  string
+	c       *oauth2.Config
+	jwksURI string
+	now     func() time.Time
+
+	mu   sync.Mutex
+	keys map[string]*rsa.PublicKey
+}
+
+// NewOIDCProvider discovers the issuer's endpoints and creates and returns a
+// new OIDCProvider instance. The name prefixes IDs of the provider's users.
+func NewOIDCProvider(name, issuer, clientID, clientSecret, redirectURL string) (*OIDCProvider, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+	d := oidcDiscovery{}
+	if err := getJSON(context.Background(), issuer+discoveryPath, "", &d); err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(d.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("discovered issuer %s doesn't match %s", d.Issuer, issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery document of %s is incomplete", issuer)
+	}
+
+	return &OIDCProvider{
+		name:   name,
+		issuer: d.Issuer,
+		c: &oauth2.Config{
+			RedirectURL:  redirectURL,
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Scopes:       []string{"openid", "email", "profile"},
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  d.AuthorizationEndpoint,
+				TokenURL: d.TokenEndpoint,
+			},
+		},
+		jwksURI: d.JWKSURI,
+		now:     time.Now,
+		keys:    map[string]*rsa.PublicKey{},
+	}, nil
+}
+
+// AuthCodeURL returns authentication code URL with the state and the S256
+// PKCE code challenge.
+func (p *OIDCProvider) AuthCodeURL(state, challenge string) string {
+	return authCodeURL(p.c, state, challenge)
+}
+
+// Exchange exchanges the code for tokens and returns the user of the ID
+// token.
+func (p *OIDCProvider) Exchange(ctx context.Context, code, verifier string) (model.User, error) {
+	token, err := exchange(ctx, p.c, code, verifier)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	idToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return model.User{}, ErrInvalidToken
+	}
+	cl, err := p.verify(ctx, idToken)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return model.User{
+		ID:            providerUserID(p.name, cl.Subject),
+		Email:         cl.Email,
+		EmailVerified: cl.EmailVerified,
+		Name:          cl.Name,
+		AvatarURL:     cl.Picture,
+	}, nil
+}
+
+// verify verifies the RS256 signature, the issuer, the audience and the
+// expiry of the ID token and returns its claims.
+func (p *OIDCProvider) verify(ctx context.Context, idToken string) (idTokenClaims, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return idTokenClaims{}, ErrInvalidToken
+	}
+
+	h := idTokenHeader{}
+	if err := decodeSegment(parts[0], &h); err != nil || h.Alg != "RS256" {
+		return idTokenClaims{}, ErrInvalidToken
+	}
+	key, err := p.key(ctx, h.Kid)
+	if err != nil {
+		return idTokenClaims{}, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return idTokenClaims{}, ErrInvalidToken
+	}
+	sum := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, sum[:], sig); err != nil {
+		return idTokenClaims{}, ErrInvalidToken
+	}
+
+	cl := idTokenClaims{}
+	if err := decodeSegment(parts[1], &cl); err != nil || cl.Subject == "" {
+		return idTokenClaims{}, ErrInvalidToken
+	}
+	if cl.Issuer != p.issuer || !cl.Audience.contains(p.c.ClientID) {
+		return idTokenClaims{}, ErrInvalidToken
+	}
+	if p.now().Unix() >= cl.ExpiresAt {
+		return idTokenClaims{}, ErrTokenExpired
+	}
+
+	return cl, nil
+}
+
+// key returns the issuer's public key with the key ID. Keys are fetched again
+// when the key ID is unknown, so rotated keys are picked up.
+func (p *OIDCProvider) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	keys, err := fetchKeys(ctx, p.jwksURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+
+	key, ok := p.keys[kid]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	return key, nil
+}
+
+// fetchKeys fetches the JSON Web Key Set and returns its RSA keys by their
+// key IDs.
+func fetchKeys(ctx context.Context, url string) (map[string]*rsa.PublicKey, error) {
+	set := jwks{}
+	if err := getJSON(ctx, url, "", &set); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	return keys, nil
+}
+
+// decodeSegment decodes the base64url JSON segment of a JWT into v.
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
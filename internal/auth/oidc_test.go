@@ -0,0 +1,227 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// fakeIssuer is a local OpenID Connect issuer signing ID tokens with its
+// current key.
+type fakeIssuer struct {
+	*fakeOAuth
+
+	mu     sync.Mutex
+	keys   map[string]*rsa.PrivateKey
+	kid    string
+	claims map[string]interface{}
+}
+
+// newFakeIssuer starts a fake OpenID Connect issuer with the "k1" key.
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	f := &fakeIssuer{fakeOAuth: newFakeOAuth(t), keys: map[string]*rsa.PrivateKey{}}
+	f.rotate(t, "k1")
+	f.claims = map[string]interface{}{
+		"iss":            f.URL,
+		"sub":            "42",
+		"aud":            "client",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"email":          "u@t.com",
+		"email_verified": true,
+		"name":           "User",
+		"picture":        "https://t.com/a.png",
+	}
+
+	f.mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcDiscovery{
+			Issuer:                f.URL,
+			AuthorizationEndpoint: f.URL + "/auth",
+			TokenEndpoint:         f.URL + "/token",
+			JWKSURI:               f.URL + "/jwks",
+		})
+	})
+	f.mux.HandleFunc("/jwks", f.jwks)
+	f.tokens = func() map[string]interface{} {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		return map[string]interface{}{
+			"access_token": "access-token", "token_type": "Bearer", "expires_in": 3600,
+			"id_token": f.sign(f.keys[f.kid], f.kid, f.claims),
+		}
+	}
+
+	return f
+}
+
+// rotate generates a new key and signs ID tokens with it.
+func (f *fakeIssuer) rotate(t *testing.T, kid string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys[kid], f.kid = key, kid
+}
+
+// set sets the claim of issued ID tokens.
+func (f *fakeIssuer) set(claim string, v interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.claims[claim] = v
+}
+
+// jwks serves public keys of the issuer.
+func (f *fakeIssuer) jwks(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys := []map[string]string{}
+	for kid, key := range f.keys {
+		keys = append(keys, map[string]string{
+			"kty": "RSA",
+			"kid": kid,
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+}
+
+// sign returns the RS256 JWT with the claims signed with the key.
+func (f *fakeIssuer) sign(key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	sum := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	if err != nil {
+		panic(err)
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// newTestOIDCProvider discovers the issuer and returns a provider using it.
+func newTestOIDCProvider(t *testing.T, f *fakeIssuer) *OIDCProvider {
+	p, err := NewOIDCProvider("oidc", f.URL+"/", "client", "secret", "http://localhost/auth/oidc/callback")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+// signInWith signs in with the provider and returns the exchanged user.
+func signInWith(t *testing.T, f *fakeIssuer, p *OIDCProvider) (model.User, error) {
+	code, _ := f.authorize(t, p.AuthCodeURL("state", codeChallenge("verifier")))
+
+	return p.Exchange(context.Background(), code, "verifier")
+}
+
+func TestOIDCProvider_Exchange(t *testing.T) {
+	f := newFakeIssuer(t)
+	p := newTestOIDCProvider(t, f)
+
+	u, err := signInWith(t, f, p)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.User{
+		ID: "oidc:42", Email: "u@t.com", EmailVerified: true, Name: "User", AvatarURL: "https://t.com/a.png",
+	}, u)
+}
+
+func TestOIDCProvider_Exchange_Rejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		mock   func(f *fakeIssuer)
+		expErr error
+	}{
+		{
+			name:   "other audience",
+			mock:   func(f *fakeIssuer) { f.set("aud", []string{"other"}) },
+			expErr: ErrInvalidToken,
+		},
+		{
+			name:   "other issuer",
+			mock:   func(f *fakeIssuer) { f.set("iss", "https://other.t.com") },
+			expErr: ErrInvalidToken,
+		},
+		{
+			name:   "expired",
+			mock:   func(f *fakeIssuer) { f.set("exp", time.Now().Add(-time.Minute).Unix()) },
+			expErr: ErrTokenExpired,
+		},
+		{
+			name: "signed with an unpublished key",
+			mock: func(f *fakeIssuer) {
+				key, _ := rsa.GenerateKey(rand.Reader, 2048)
+				f.tokens = func() map[string]interface{} {
+					return map[string]interface{}{
+						"access_token": "access-token", "token_type": "Bearer",
+						"id_token": f.sign(key, "k1", f.claims),
+					}
+				}
+			},
+			expErr: ErrInvalidToken,
+		},
+		{
+			name: "no ID token",
+			mock: func(f *fakeIssuer) {
+				f.tokens = func() map[string]interface{} {
+					return map[string]interface{}{"access_token": "access-token", "token_type": "Bearer"}
+				}
+			},
+			expErr: ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFakeIssuer(t)
+			p := newTestOIDCProvider(t, f)
+			tc.mock(f)
+
+			_, err := signInWith(t, f, p)
+
+			assert.Equal(t, tc.expErr, err)
+		})
+	}
+}
+
+func TestOIDCProvider_Exchange_KeyRotation(t *testing.T) {
+	f := newFakeIssuer(t)
+	p := newTestOIDCProvider(t, f)
+	_, err := signInWith(t, f, p)
+	assert.NoError(t, err)
+
+	// Tokens signed with a new key are verified after fetching the keys again.
+	f.rotate(t, "k2")
+	_, err = signInWith(t, f, p)
+	assert.NoError(t, err)
+	assert.Len(t, p.keys, 2)
+}
+
+func TestNewOIDCProvider_IssuerMismatch(t *testing.T) {
+	f := newFakeIssuer(t)
+
+	_, err := NewOIDCProvider("oidc", f.URL+"/other", "client", "secret", "http://localhost/auth/oidc/callback")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// passwordProvider is the name of the password provider, it prefixes IDs of
+// users signed up with a password.
+const passwordProvider = "password"
+
+// passwords is password service implementation.
+type passwords struct {
+	r    CredentialRepo
+	cost int
+
+	// dummy is compared with passwords of unknown emails, so signing in takes
+	// the same time whether the email exists or not.
+	dummy []byte
+}
+
+// NewPasswords creates and returns a new Passwords instance.
+func NewPasswords(r CredentialRepo) Passwords {
+	return newPasswords(r, bcrypt.DefaultCost)
+}
+
+// newPasswords creates and returns a new passwords instance hashing with the
+// bcrypt cost.
+func newPasswords(r CredentialRepo, cost int) *passwords {
+	dummy, err := bcrypt.GenerateFromPassword([]byte("dummy-password"), cost)
+	if err != nil {
+		panic(err)
+	}
+
+	return &passwords{r: r, cost: cost, dummy: dummy}
+}
+
+// SignUp validates the sign-up, stores the password's hash and returns the
+// new user. Emails of password users aren't verified.
+func (p *passwords) SignUp(ctx context.Context, s model.PasswordSignUp) (model.User, error) {
+	s.Email = normalizeEmail(s.Email)
+	s.Name = strings.TrimSpace(s.Name)
+	if err := s.Validate(); err != nil {
+		return model.User{}, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(s.Password), p.cost)
+	if err != nil {
+		return model.User{}, err
+	}
+	id, err := randomToken(tokenBytes / 2)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	c, err := p.r.Create(ctx, model.PasswordCredential{
+		Email:  s.Email,
+		UserID: providerUserID(passwordProvider, id),
+		Hash:   string(hash),
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return model.User{ID: c.UserID, Email: c.Email, Name: s.Name}, nil
+}
+
+// SignIn returns the user with the email when the password matches.
+func (p *passwords) SignIn(ctx context.Context, s model.PasswordSignIn) (model.User, error) {
+	c, err := p.r.GetByEmail(ctx, normalizeEmail(s.Email))
+	if err == ErrInvalidCredentials {
+		bcrypt.CompareHashAndPassword(p.dummy, []byte(s.Password))
+		return model.User{}, ErrInvalidCredentials
+	} else if err != nil {
+		return model.User{}, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(c.Hash), []byte(s.Password)); err != nil {
+		return model.User{}, ErrInvalidCredentials
+	}
+
+	return model.User{ID: c.UserID, Email: c.Email}, nil
+}
+
+// normalizeEmail trims and lowercases the email.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/store"
+)
+
+// credentialRepo is password credential repository implementation.
+type credentialRepo struct {
+	db *gorm.DB
+}
+
+// NewCredentialRepo creates and returns a new CredentialRepo instance.
+func NewCredentialRepo(db *gorm.DB) CredentialRepo {
+	return &credentialRepo{db}
+}
+
+// Create creates a credential and returns it, it returns ErrEmailTaken when
+// the email already has a credential.
+func (r *credentialRepo) Create(ctx context.Context, c model.PasswordCredential) (model.PasswordCredential, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var n int64
+		if err := tx.Model(&model.PasswordCredential{}).Where("email = ?", c.Email).Count(&n).Error; err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrEmailTaken
+		}
+
+		return tx.Create(&c).Error
+	})
+	if err != nil {
+		return model.PasswordCredential{}, store.Wrap(err)
+	}
+
+	return c, nil
+}
+
+// GetByEmail gets and returns the credential with specific email.
+func (r *credentialRepo) GetByEmail(ctx context.Context, email string) (c model.PasswordCredential, err error) {
+	err = r.db.WithContext(ctx).Where("email = ?", email).First(&c).Error
+	if err == gorm.ErrRecordNotFound {
+		return c, ErrInvalidCredentials
+	}
+
+	return c, store.Wrap(err)
+}
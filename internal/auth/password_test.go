@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/testdb"
+)
+
+// newTestPasswords returns passwords stored in an in-memory SQLite database.
+func newTestPasswords(t *testing.T) *passwords {
+	return newPasswords(NewCredentialRepo(testdb.New(t, &model.PasswordCredential{})), bcrypt.MinCost)
+}
+
+func TestPasswords_SignUpSignIn(t *testing.T) {
+	p := newTestPasswords(t)
+
+	u, err := p.SignUp(context.Background(), model.PasswordSignUp{Email: " U@t.com ", Password: "password", Name: " User "})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(u.ID, "password:"))
+	assert.Equal(t, "u@t.com", u.Email)
+	assert.False(t, u.EmailVerified)
+	assert.Equal(t, "User", u.Name)
+
+	in, err := p.SignIn(context.Background(), model.PasswordSignIn{Email: "u@T.com", Password: "password"})
+	assert.NoError(t, err)
+	assert.Equal(t, u.ID, in.ID)
+
+	_, err = p.SignUp(context.Background(), model.PasswordSignUp{Email: "u@t.com", Password: "other-password"})
+	assert.Equal(t, ErrEmailTaken, err)
+}
+
+func TestPasswords_SignIn_InvalidCredentials(t *testing.T) {
+	p := newTestPasswords(t)
+	if _, err := p.SignUp(context.Background(), model.PasswordSignUp{Email: "u@t.com", Password: "password"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := p.SignIn(context.Background(), model.PasswordSignIn{Email: "u@t.com", Password: "other-password"})
+	assert.Equal(t, ErrInvalidCredentials, err)
+	_, err = p.SignIn(context.Background(), model.PasswordSignIn{Email: "other@t.com", Password: "password"})
+	assert.Equal(t, ErrInvalidCredentials, err)
+}
+
+func TestPasswords_SignUp_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		signUp model.PasswordSignUp
+		field  string
+	}{
+		{name: "invalid email", signUp: model.PasswordSignUp{Email: "u", Password: "password"}, field: "email"},
+		{name: "short password", signUp: model.PasswordSignUp{Email: "u@t.com", Password: "short"}, field: "password"},
+		{
+			name:   "password longer than bcrypt hashes",
+			signUp: model.PasswordSignUp{Email: "u@t.com", Password: strings.Repeat("p", 73)},
+			field:  "password",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := newTestPasswords(t).SignUp(context.Background(), tc.signUp)
+
+			if errs, ok := err.(validation.Errors); assert.True(t, ok) {
+				assert.Contains(t, errs, tc.field)
+			}
+		})
+	}
+}
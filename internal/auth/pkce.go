@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// flowCookie keeps the state and the PKCE code verifier of a sign-in
+	// between the sign-in redirect and the callback.
+	flowCookie = "oauth_flow"
+	// flowTTL is how long users have to complete a sign-in.
+	flowTTL = 10 * time.Minute
+)
+
+// codeChallenge returns the S256 PKCE code challenge of the code verifier.
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// startFlow generates the state and the PKCE code verifier of a sign-in and
+// binds them to the browser with a short-lived cookie sent only to the path
+// of the provider's routes.
+func startFlow(c echo.Context, path string) (state, verifier string, err error) {
+	if state, err = randomToken(tokenBytes); err != nil {
+		return "", "", err
+	}
+	if verifier, err = randomToken(tokenBytes); err != nil {
+		return "", "", err
+	}
+
+	c.SetCookie(&http.Cookie{
+		Name:     flowCookie,
+		Value:    state + "." + verifier,
+		Path:     path,
+		MaxAge:   int(flowTTL / time.Second),
+		HttpOnly: true,
+		Secure:   c.Request().TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return state, verifier, nil
+}
+
+// finishFlow clears the sign-in cookie and returns the PKCE code verifier
+// when the state of the callback request matches the cookie. It returns
+// false for requests that didn't start at the sign-in redirect.
+func finishFlow(c echo.Context, path string) (verifier string, ok bool) {
+	cookie, err := c.Cookie(flowCookie)
+	c.SetCookie(&http.Cookie{Name: flowCookie, Path: path, MaxAge: -1, HttpOnly: true})
+	if err != nil {
+		return "", false
+	}
+
+	parts := strings.Split(cookie.Value, ".")
+	state := c.FormValue("state")
+	if len(parts) != 2 || state == "" || subtle.ConstantTimeCompare([]byte(parts[0]), []byte(state)) != 1 {
+		return "", false
+	}
+
+	return parts[1], true
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"golang.org/x/oauth2"
+)
+
+// maxUserIDLen is the maximum length of user IDs.
+const maxUserIDLen = 64
+
+// Registry keeps identity providers by their names.
+type Registry struct {
+	providers map[string]Provider
+}
+
+// NewRegistry creates and returns a new empty Registry instance.
+func NewRegistry() *Registry {
+	return &Registry{providers: map[string]Provider{}}
+}
+
+// Register registers the provider under the name, the name is the provider's
+// path segment in sign-in and callback routes.
+func (r *Registry) Register(name string, p Provider) {
+	r.providers[name] = p
+}
+
+// Get returns the provider registered under the name.
+func (r *Registry) Get(name string) (Provider, bool) {
+	p, ok := r.providers[name]
+
+	return p, ok
+}
+
+// Names returns sorted names of registered providers.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// providerUserID returns ID of the user with the subject at the provider.
+// Subjects of different providers don't collide, subjects too long for user
+// IDs are hashed.
+func providerUserID(provider, subject string) string {
+	id := provider + ":" + subject
+	if len(id) > maxUserIDLen {
+		sum := sha256.Sum256([]byte(subject))
+		id = provider + ":" + hex.EncodeToString(sum[:])[:maxUserIDLen-len(provider)-1]
+	}
+
+	return id
+}
+
+// authCodeURL returns the auth code URL of the OAuth config with the state
+// and the S256 PKCE code challenge.
+func authCodeURL(c *oauth2.Config, state, challenge string) string {
+	return c.AuthCodeURL(
+		state,
+		oauth2.SetAuthURLParam("code_challenge", challenge),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
+}
+
+// exchange exchanges the code for a token, the code verifier must match the
+// code challenge of the sign-in.
+func exchange(ctx context.Context, c *oauth2.Config, code, verifier string) (*oauth2.Token, error) {
+	token, err := c.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+	if err != nil {
+		return nil, fmt.Errorf("code exchange failed: %s", err.Error())
+	}
+
+	return token, nil
+}
+
+// getJSON gets the URL with the access token and decodes the JSON response
+// into v.
+func getJSON(ctx context.Context, url, token string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed getting %s: %s", url, err.Error())
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed getting %s: %s", url, response.Status)
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed reading response body: %s", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+// fakeOAuth is a fake OAuth server issuing codes for PKCE code challenges.
+// Providers' APIs are served by handlers added to mux.
+type fakeOAuth struct {
+	*httptest.Server
+	mux *http.ServeMux
+
+	// tokens returns the token response of exchanged codes.
+	tokens func() map[string]interface{}
+
+	mu         sync.Mutex
+	challenges map[string]string
+}
+
+// newFakeOAuth starts a fake OAuth server issuing the "access-token" access
+// token.
+func newFakeOAuth(t *testing.T) *fakeOAuth {
+	f := &fakeOAuth{mux: http.NewServeMux(), challenges: map[string]string{}}
+	f.tokens = func() map[string]interface{} {
+		return map[string]interface{}{"access_token": "access-token", "token_type": "Bearer", "expires_in": 3600}
+	}
+	f.mux.HandleFunc("/token", f.token)
+	f.Server = httptest.NewServer(f.mux)
+	t.Cleanup(f.Close)
+
+	return f
+}
+
+// config returns OAuth config of a client of the server.
+func (f *fakeOAuth) config(provider string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/auth/" + provider + "/callback",
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   f.URL + "/auth",
+			TokenURL:  f.URL + "/token",
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+}
+
+// authorize plays the user signing in at the auth code URL and returns the
+// code the server redirects back with.
+func (f *fakeOAuth) authorize(t *testing.T, authCodeURL string) (code, state string) {
+	u, err := url.Parse(authCodeURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		t.Fatalf("auth code URL has no S256 code challenge: %s", authCodeURL)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	code = "code-" + q.Get("state")
+	f.challenges[code] = q.Get("code_challenge")
+
+	return code, q.Get("state")
+}
+
+// token exchanges codes for tokens when the code verifier matches the code
+// challenge.
+func (f *fakeOAuth) token(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	challenge, ok := f.challenges[r.FormValue("code")]
+	delete(f.challenges, r.FormValue("code"))
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if !ok || codeChallenge(r.FormValue("code_verifier")) != challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+
+	json.NewEncoder(w).Encode(f.tokens())
+}
+
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry()
+	g := NewGoogleProvider("client", "secret", "http://localhost/auth/google/callback")
+	reg.Register("google", g)
+	reg.Register("github", NewGitHubProvider("client", "secret", "http://localhost/auth/github/callback"))
+
+	p, ok := reg.Get("google")
+	assert.True(t, ok)
+	assert.Equal(t, g, p)
+	_, ok = reg.Get("other")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"github", "google"}, reg.Names())
+}
+
+func TestProviderUserID(t *testing.T) {
+	assert.Equal(t, "github:1", providerUserID("github", "1"))
+
+	long := providerUserID("oidc", string(make([]byte, 100)))
+	assert.Len(t, long, maxUserIDLen)
+	assert.Equal(t, "oidc:", long[:5])
+	assert.NotEqual(t, long, providerUserID("oidc", string(make([]byte, 101))))
+}
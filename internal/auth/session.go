@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+const (
+	// tokenType is the type of issued access tokens.
+	tokenType = "Bearer"
+	// tokenBytes is the number of random bytes of session IDs and refresh
+	// tokens.
+	tokenBytes = 32
+)
+
+// sessions is session service implementation.
+type sessions struct {
+	r      SessionRepo
+	secret []byte
+
+	// accessTTL and refreshTTL are how long access and refresh tokens are
+	// valid for.
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+
+	now func() time.Time
+}
+
+// NewSessions creates and returns a new Sessions instance, access tokens are
+// signed with the secret.
+func NewSessions(r SessionRepo, secret []byte, accessTTL, refreshTTL time.Duration) Sessions {
+	return &sessions{r: r, secret: secret, accessTTL: accessTTL, refreshTTL: refreshTTL, now: time.Now}
+}
+
+// Issue starts a session of the user and returns its tokens. Access tokens
+// carry the user's email only when it's verified.
+func (s *sessions) Issue(ctx context.Context, u model.User) (model.TokenPair, error) {
+	id, err := randomToken(tokenBytes)
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	refresh, err := randomToken(tokenBytes)
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+
+	email := ""
+	if u.EmailVerified {
+		email = u.Email
+	}
+
+	ss, err := s.r.Create(ctx, model.Session{
+		ID:          id,
+		UserID:      u.ID,
+		Email:       email,
+		Role:        u.Role,
+		RefreshHash: hashToken(refresh),
+		ExpiresAt:   s.now().Add(s.refreshTTL),
+	})
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+
+	return s.tokens(ss, refresh)
+}
+
+// Verify verifies the access token locally and against its session and
+// returns its claims with the session's role.
+func (s *sessions) Verify(ctx context.Context, token string) (Claims, error) {
+	cl, err := parseToken(token, s.secret, s.now())
+	if err != nil {
+		return Claims{}, err
+	}
+
+	ss, err := s.r.GetByID(ctx, cl.SessionID)
+	if err == ErrSessionNotFound {
+		return Claims{}, ErrInvalidToken
+	} else if err != nil {
+		return Claims{}, err
+	}
+	if ss.RevokedAt != nil {
+		return Claims{}, ErrSessionRevoked
+	}
+	cl.Role = ss.Role
+
+	return cl, nil
+}
+
+// Refresh replaces the refresh token of its session and returns new tokens
+// of the session.
+func (s *sessions) Refresh(ctx context.Context, refreshToken string) (model.TokenPair, error) {
+	ss, err := s.r.GetByRefreshHash(ctx, hashToken(refreshToken))
+	if err == ErrSessionNotFound {
+		return model.TokenPair{}, ErrInvalidToken
+	} else if err != nil {
+		return model.TokenPair{}, err
+	}
+	if ss.RevokedAt != nil {
+		return model.TokenPair{}, ErrSessionRevoked
+	}
+	if !s.now().Before(ss.ExpiresAt) {
+		return model.TokenPair{}, ErrTokenExpired
+	}
+
+	refresh, err := randomToken(tokenBytes)
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	ss.ExpiresAt = s.now().Add(s.refreshTTL)
+	ss, err = s.r.Rotate(ctx, ss, hashToken(refresh))
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+
+	return s.tokens(ss, refresh)
+}
+
+// tokens returns the session's tokens with a new access token.
+func (s *sessions) tokens(ss model.Session, refresh string) (model.TokenPair, error) {
+	now := s.now()
+	access, err := signToken(Claims{
+		Subject:   ss.UserID,
+		Email:     ss.Email,
+		SessionID: ss.ID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.accessTTL).Unix(),
+	}, s.secret)
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+
+	return model.TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    tokenType,
+		ExpiresIn:    int64(s.accessTTL / time.Second),
+	}, nil
+}
+
+// Revoke revokes the session with specific ID.
+func (s *sessions) Revoke(ctx context.Context, id string) error {
+	return s.r.Revoke(ctx, id)
+}
+
+// PurgeExpired deletes sessions which refresh tokens have expired.
+func (s *sessions) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.r.PurgeExpired(ctx, s.now())
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/store"
+)
+
+// sessionRepo is session repository implementation.
+type sessionRepo struct {
+	db *gorm.DB
+}
+
+// NewSessionRepo creates and returns a new SessionRepo instance.
+func NewSessionRepo(db *gorm.DB) SessionRepo {
+	return &sessionRepo{db}
+}
+
+// Create creates a session and returns it.
+func (r *sessionRepo) Create(ctx context.Context, s model.Session) (model.Session, error) {
+	err := r.db.WithContext(ctx).Create(&s).Error
+
+	return s, store.Wrap(err)
+}
+
+// GetByID gets and returns the session with specific ID.
+func (r *sessionRepo) GetByID(ctx context.Context, id string) (s model.Session, err error) {
+	err = r.db.WithContext(ctx).Where("id = ?", id).First(&s).Error
+	if err == gorm.ErrRecordNotFound {
+		return s, ErrSessionNotFound
+	}
+
+	return s, store.Wrap(err)
+}
+
+// GetByRefreshHash gets and returns the session with specific refresh token
+// hash.
+func (r *sessionRepo) GetByRefreshHash(ctx context.Context, hash string) (s model.Session, err error) {
+	err = r.db.WithContext(ctx).Where("refresh_hash = ?", hash).First(&s).Error
+	if err == gorm.ErrRecordNotFound {
+		return s, ErrSessionNotFound
+	}
+
+	return s, store.Wrap(err)
+}
+
+// Rotate replaces the refresh token hash of the session with the new one
+// and returns the session. Only one of concurrent rotations of the same
+// refresh token succeeds, the others get ErrInvalidToken.
+func (r *sessionRepo) Rotate(ctx context.Context, s model.Session, newHash string) (model.Session, error) {
+	s.UpdatedAt = r.db.NowFunc()
+	res := r.db.WithContext(ctx).Model(&model.Session{}).
+		Where("id = ? AND refresh_hash = ? AND revoked_at IS NULL", s.ID, s.RefreshHash).
+		Updates(map[string]interface{}{
+			"refresh_hash": newHash,
+			"expires_at":   s.ExpiresAt,
+			"updated_at":   s.UpdatedAt,
+		})
+	if res.Error != nil {
+		return model.Session{}, store.Wrap(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return model.Session{}, ErrInvalidToken
+	}
+	s.RefreshHash = newHash
+
+	return s, nil
+}
+
+// Revoke revokes the session with specific ID.
+func (r *sessionRepo) Revoke(ctx context.Context, id string) error {
+	res := r.db.WithContext(ctx).Model(&model.Session{}).Where("id = ? AND revoked_at IS NULL", id).
+		UpdateColumn("revoked_at", r.db.NowFunc())
+	if res.Error != nil {
+		return store.Wrap(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// PurgeExpired deletes sessions which refresh tokens expired before the time
+// and returns the number of deleted sessions.
+func (r *sessionRepo) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Where("expires_at < ?", before).Delete(&model.Session{})
+
+	return res.RowsAffected, store.Wrap(res.Error)
+}
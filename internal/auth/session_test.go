@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/testdb"
+)
+
+// newTestSessions returns sessions stored in an in-memory SQLite database
+// with the clock set by the returned function.
+func newTestSessions(t *testing.T) (*sessions, func(time.Time)) {
+	db := testdb.New(t, &model.Session{})
+	now := time.Now()
+	s := NewSessions(NewSessionRepo(db), []byte("secret"), time.Minute, time.Hour).(*sessions)
+	s.now = func() time.Time { return now }
+
+	return s, func(t time.Time) { now = t }
+}
+
+func TestSessions_IssueVerify(t *testing.T) {
+	s, setNow := newTestSessions(t)
+	start := s.now()
+
+	tp, err := s.Issue(context.Background(), model.User{ID: "1", Email: "u@t.com", EmailVerified: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer", tp.TokenType)
+	assert.Equal(t, int64(60), tp.ExpiresIn)
+
+	cl, err := s.Verify(context.Background(), tp.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", cl.Subject)
+	assert.Equal(t, "u@t.com", cl.Email)
+
+	setNow(start.Add(time.Minute))
+	_, err = s.Verify(context.Background(), tp.AccessToken)
+	assert.Equal(t, ErrTokenExpired, err)
+}
+
+func TestSessions_Verify_Role(t *testing.T) {
+	s, _ := newTestSessions(t)
+
+	tp, err := s.Issue(context.Background(), model.User{ID: "1", Role: model.RoleModerator})
+	assert.NoError(t, err)
+
+	cl, err := s.Verify(context.Background(), tp.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, model.RoleModerator, cl.Role)
+}
+
+func TestSessions_Verify_Canceled(t *testing.T) {
+	s, _ := newTestSessions(t)
+
+	tp, err := s.Issue(context.Background(), model.User{ID: "1"})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = s.Verify(ctx, tp.AccessToken)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestSessions_Issue_UnverifiedEmail(t *testing.T) {
+	s, _ := newTestSessions(t)
+
+	tp, err := s.Issue(context.Background(), model.User{ID: "1", Email: "u@t.com"})
+	assert.NoError(t, err)
+
+	cl, err := s.Verify(context.Background(), tp.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", cl.Subject)
+	assert.Empty(t, cl.Email)
+}
+
+func TestSessions_Refresh(t *testing.T) {
+	s, setNow := newTestSessions(t)
+	start := s.now()
+
+	tp, err := s.Issue(context.Background(), model.User{ID: "1", Email: "u@t.com"})
+	assert.NoError(t, err)
+
+	setNow(start.Add(30 * time.Minute))
+	refreshed, err := s.Refresh(context.Background(), tp.RefreshToken)
+	assert.NoError(t, err)
+	assert.NotEqual(t, tp.RefreshToken, refreshed.RefreshToken)
+	cl, err := s.Verify(context.Background(), refreshed.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", cl.Subject)
+
+	// Refresh tokens are single use.
+	_, err = s.Refresh(context.Background(), tp.RefreshToken)
+	assert.Equal(t, ErrInvalidToken, err)
+
+	// Refreshing extends the session.
+	setNow(start.Add(80 * time.Minute))
+	_, err = s.Refresh(context.Background(), refreshed.RefreshToken)
+	assert.NoError(t, err)
+
+	setNow(start.Add(200 * time.Minute))
+	n, err := s.PurgeExpired(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
+
+func TestSessions_Refresh_Expired(t *testing.T) {
+	s, setNow := newTestSessions(t)
+
+	tp, err := s.Issue(context.Background(), model.User{ID: "1", Email: "u@t.com"})
+	assert.NoError(t, err)
+
+	setNow(s.now().Add(time.Hour))
+	_, err = s.Refresh(context.Background(), tp.RefreshToken)
+	assert.Equal(t, ErrTokenExpired, err)
+}
+
+func TestSessions_Revoke(t *testing.T) {
+	s, _ := newTestSessions(t)
+
+	tp, err := s.Issue(context.Background(), model.User{ID: "1", Email: "u@t.com"})
+	assert.NoError(t, err)
+	cl, err := s.Verify(context.Background(), tp.AccessToken)
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Revoke(context.Background(), cl.SessionID))
+	assert.Equal(t, ErrSessionNotFound, s.Revoke(context.Background(), cl.SessionID))
+
+	_, err = s.Verify(context.Background(), tp.AccessToken)
+	assert.Equal(t, ErrSessionRevoked, err)
+	_, err = s.Refresh(context.Background(), tp.RefreshToken)
+	assert.Equal(t, ErrSessionRevoked, err)
+}
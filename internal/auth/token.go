@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Claims are the claims of access tokens.
+type Claims struct {
+	Subject   string `json:"sub"`
+	Email     string `json:"email"`
+	SessionID string `json:"sid"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+
+	// Role isn't signed into tokens, it's the current role of the session.
+	Role string `json:"-"`
+}
+
+// tokenHeader is the encoded header of HS256 JWTs.
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// signToken returns the HS256 JWT with the claims signed with the secret.
+func signToken(cl Claims, secret []byte) (string, error) {
+	payload, err := json.Marshal(cl)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + signature(unsigned, secret), nil
+}
+
+// parseToken verifies the signature and the expiry of the HS256 JWT and
+// returns its claims.
+func parseToken(token string, secret []byte, now time.Time) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return Claims{}, ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(signature(parts[0]+"."+parts[1], secret))) {
+		return Claims{}, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	cl := Claims{}
+	if err := json.Unmarshal(payload, &cl); err != nil || cl.Subject == "" || cl.SessionID == "" {
+		return Claims{}, ErrInvalidToken
+	}
+	if now.Unix() >= cl.ExpiresAt {
+		return Claims{}, ErrTokenExpired
+	}
+
+	return cl, nil
+}
+
+// signature returns the encoded HMAC-SHA256 of the data.
+func signature(data string, secret []byte) string {
+	m := hmac.New(sha256.New, secret)
+	m.Write([]byte(data))
+
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil))
+}
+
+// randomToken returns a random URL-safe string of n random bytes.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns hex encoded SHA-256 of the token, refresh tokens are
+// stored hashed.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// BearerToken returns the token of the Authorization header value, the
+// "Bearer" scheme is optional.
+func BearerToken(header string) string {
+	if len(header) > 7 && strings.EqualFold(header[:7], "bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+
+	return strings.TrimSpace(header)
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToken(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Unix(1000, 0)
+	cl := Claims{Subject: "1", Email: "u@t.com", SessionID: "s", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Minute).Unix()}
+
+	token, err := signToken(cl, secret)
+	assert.NoError(t, err)
+
+	got, err := parseToken(token, secret, now)
+	assert.NoError(t, err)
+	assert.Equal(t, cl, got)
+
+	_, err = parseToken(token, secret, now.Add(time.Minute))
+	assert.Equal(t, ErrTokenExpired, err)
+	_, err = parseToken(token, []byte("other"), now)
+	assert.Equal(t, ErrInvalidToken, err)
+
+	// A changed payload doesn't match the signature.
+	other, _ := signToken(Claims{Subject: "2", SessionID: "s", ExpiresAt: cl.ExpiresAt}, secret)
+	forged := strings.Split(other, ".")
+	forged[2] = strings.Split(token, ".")[2]
+	_, err = parseToken(strings.Join(forged, "."), secret, now)
+	assert.Equal(t, ErrInvalidToken, err)
+	_, err = parseToken("token", secret, now)
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestBearerToken(t *testing.T) {
+	assert.Equal(t, "token", BearerToken("Bearer token"))
+	assert.Equal(t, "token", BearerToken("bearer  token"))
+	assert.Equal(t, "token", BearerToken("token"))
+	assert.Equal(t, "", BearerToken(""))
+}
@@ -0,0 +1,10 @@
+package category
+
+import "github.com/imarrche/nix-ed/internal/store"
+
+var (
+	// ErrNotFound is thrown when specified category was not found in database.
+	ErrNotFound = store.New(store.ErrNotFound, "specified category was not found")
+	// ErrNameTaken is thrown when another category has the same name.
+	ErrNameTaken = store.New(store.ErrConflict, "specified category name is taken")
+)
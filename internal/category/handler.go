@@ -0,0 +1,195 @@
+package category
+
+import (
+	"net/http"
+	"strconv"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/labstack/echo/v4"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/render"
+)
+
+// Handler is http handler for category resource.
+type Handler struct {
+	cs Service
+}
+
+// NewHandler creates and returns a new Handler instance.
+func NewHandler(cs Service) *Handler {
+	return &Handler{cs: cs}
+}
+
+// setPageHeaders sets the total count and the next page cursor headers.
+func setPageHeaders(c echo.Context, pi model.PageInfo) {
+	c.Response().Header().Set("X-Total-Count", strconv.FormatInt(pi.Total, 10))
+	if pi.NextCursor != "" {
+		c.Response().Header().Set("X-Next-Cursor", pi.NextCursor)
+	}
+}
+
+// GetAll returns category list.
+// @Summary Show all categories
+// @Descriptions show the page of categories
+// @Tags categories
+// @ID category-list
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack,text/csv
+// @Param limit query int false "page size" default(20) maximum(100)
+// @Param offset query int false "number of categories to skip"
+// @Param cursor query string false "cursor returned by the previous page"
+// @Param sort query string false "sort field" Enums(id, name)
+// @Param order query string false "sort order" Enums(asc, desc)
+// @Success 200 {array} model.Category
+// @Header 200 {integer} X-Total-Count "total number of categories"
+// @Header 200 {string} X-Next-Cursor "cursor of the next page"
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /categories [get]
+func (h *Handler) GetAll(c echo.Context) error {
+	pg := model.Page{Limit: model.DefaultPageLimit}
+	if err := c.Bind(&pg); err != nil {
+		return err
+	}
+
+	cs, pi, err := h.cs.GetAll(c.Request().Context(), pg)
+	if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+
+	setPageHeaders(c, pi)
+	return render.Respond(c, http.StatusOK, cs)
+}
+
+// Create creates a category.
+// @Summary Create a category
+// @Descriptions create a category
+// @Tags categories
+// @ID category-create
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param input body model.Category true "category data"
+// @Success 201 {object} model.Category
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Router /categories [post]
+func (h *Handler) Create(c echo.Context) error {
+	ct := model.Category{}
+	if err := c.Bind(&ct); err != nil {
+		return err
+	}
+	ct.ID = 0
+
+	ct, err := h.cs.Create(c.Request().Context(), ct)
+	if err == ErrNameTaken {
+		return echo.NewHTTPError(http.StatusConflict, err)
+	} else if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusCreated, ct)
+}
+
+// GetByID returns category detail.
+// @Summary Category detail
+// @Descriptions category detail
+// @Tags categories
+// @ID category-detail
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "category id"
+// @Success 200 {object} model.Category
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /categories/{id} [get]
+func (h *Handler) GetByID(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	ct, err := h.cs.GetByID(c.Request().Context(), id)
+	if err == ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusOK, ct)
+}
+
+// Update updates a category.
+// @Summary Category update
+// @Descriptions update category's name and description
+// @Tags categories
+// @ID category-update
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "category id"
+// @Param input body model.Category true "category data"
+// @Success 200 {object} model.Category
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Router /categories/{id} [patch]
+func (h *Handler) Update(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	ct := model.Category{}
+	if err := c.Bind(&ct); err != nil {
+		return err
+	}
+	ct.ID = id
+
+	ct, err = h.cs.Update(c.Request().Context(), ct)
+	if err == ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err == ErrNameTaken {
+		return echo.NewHTTPError(http.StatusConflict, err)
+	} else if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusOK, ct)
+}
+
+// DeleteByID deletes a category.
+// @Summary Category delete
+// @Descriptions delete a category and remove it from posts
+// @Tags categories
+// @ID category-delete
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "category id"
+// @Success 204 ""
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /categories/{id} [delete]
+func (h *Handler) DeleteByID(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	err = h.cs.DeleteByID(c.Request().Context(), id)
+	if err == ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
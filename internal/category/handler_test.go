@@ -0,0 +1,271 @@
+package category
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/auth"
+	mockauth "github.com/imarrche/nix-ed/internal/auth/mock"
+	mockcategory "github.com/imarrche/nix-ed/internal/category/mock"
+	"github.com/imarrche/nix-ed/internal/handlertest"
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+func TestHandler_GetAll(t *testing.T) {
+	testcases := []struct {
+		name          string
+		mock          func(*mockcategory.MockService)
+		query         string
+		expCategories []model.Category
+		expTotal      string
+		expCode       int
+	}{
+		{
+			name: "categories are retrieved",
+			mock: func(s *mockcategory.MockService) {
+				pg := model.Page{Limit: model.DefaultPageLimit, Sort: "name"}
+				s.EXPECT().GetAll(gomock.Any(), pg).Return([]model.Category{{Name: "backend"}}, model.PageInfo{Total: 1}, nil)
+			},
+			query:         "?sort=name",
+			expCategories: []model.Category{{Name: "backend"}},
+			expTotal:      "1",
+			expCode:       http.StatusOK,
+		},
+		{
+			name:    "invalid query",
+			mock:    func(s *mockcategory.MockService) {},
+			query:   "?limit=ten",
+			expCode: http.StatusBadRequest,
+		},
+		{
+			name: "validation errors",
+			mock: func(s *mockcategory.MockService) {
+				pg := model.Page{Limit: model.DefaultPageLimit, Sort: "body"}
+				err := validation.Errors{"sort": errors.New("must be a valid value")}
+				s.EXPECT().GetAll(gomock.Any(), pg).Return(nil, model.PageInfo{}, err)
+			},
+			query:   "?sort=body",
+			expCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		cs := mockcategory.NewMockService(c)
+		tc.mock(cs)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/categories"+tc.query, nil)
+
+		ctx := echo.New().NewContext(r, w)
+
+		handlertest.Serve(ctx, NewHandler(cs).GetAll)
+
+		var categories []model.Category
+		json.NewDecoder(w.Body).Decode(&categories)
+
+		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expTotal, w.Header().Get("X-Total-Count"))
+		assert.Equal(t, tc.expCategories, categories)
+	}
+}
+
+func TestHandler_Create(t *testing.T) {
+	testcases := []struct {
+		name     string
+		mock     func(*mockcategory.MockService, model.Category)
+		category model.Category
+		expCode  int
+	}{
+		{
+			name: "category is created",
+			mock: func(s *mockcategory.MockService, ct model.Category) {
+				s.EXPECT().Create(gomock.Any(), ct).Return(ct, nil)
+			},
+			category: model.Category{Name: "backend"},
+			expCode:  http.StatusCreated,
+		},
+		{
+			name: "name is taken",
+			mock: func(s *mockcategory.MockService, ct model.Category) {
+				s.EXPECT().Create(gomock.Any(), ct).Return(model.Category{}, ErrNameTaken)
+			},
+			category: model.Category{Name: "backend"},
+			expCode:  http.StatusConflict,
+		},
+		{
+			name: "validation errors",
+			mock: func(s *mockcategory.MockService, ct model.Category) {
+				err := validation.Errors{"name": errors.New("cannot be blank")}
+				s.EXPECT().Create(gomock.Any(), ct).Return(model.Category{}, err)
+			},
+			category: model.Category{},
+			expCode:  http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		cs := mockcategory.NewMockService(c)
+		tc.mock(cs, tc.category)
+		w := httptest.NewRecorder()
+
+		b := &bytes.Buffer{}
+		json.NewEncoder(b).Encode(tc.category)
+		r := httptest.NewRequest(http.MethodPost, "/categories", b)
+		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		ctx := echo.New().NewContext(r, w)
+
+		handlertest.Serve(ctx, NewHandler(cs).Create)
+
+		assert.Equal(t, tc.expCode, w.Code)
+	}
+}
+
+func TestHandler_Create_Permission(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mock    func(*mockcategory.MockService)
+		role    string
+		expCode int
+	}{
+		{
+			name: "moderator creates a category",
+			mock: func(s *mockcategory.MockService) {
+				s.EXPECT().Create(gomock.Any(), model.Category{Name: "Go"}).Return(model.Category{ID: 1, Name: "Go"}, nil)
+			},
+			role:    model.RoleModerator,
+			expCode: http.StatusCreated,
+		},
+		{
+			name:    "user can't manage taxonomy",
+			mock:    func(s *mockcategory.MockService) {},
+			role:    model.RoleUser,
+			expCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		cs := mockcategory.NewMockService(c)
+		tc.mock(cs)
+		ss := mockauth.NewMockSessions(c)
+		ss.EXPECT().Verify(gomock.Any(), "token").Return(auth.Claims{Subject: "1", SessionID: "s", Role: tc.role}, nil)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/categories", bytes.NewBufferString(`{"name":"Go"}`))
+		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		r.Header.Set("Authorization", "token")
+
+		hf := auth.Require(model.PermManageTaxonomy)(NewHandler(cs).Create)
+		handlertest.Serve(echo.New().NewContext(r, w), auth.Authenticate(ss)(hf))
+
+		assert.Equal(t, tc.expCode, w.Code, tc.name)
+	}
+}
+
+func TestHandler_Update(t *testing.T) {
+	testcases := []struct {
+		name     string
+		mock     func(*mockcategory.MockService, model.Category)
+		category model.Category
+		expCode  int
+	}{
+		{
+			name: "category is updated",
+			mock: func(s *mockcategory.MockService, ct model.Category) {
+				s.EXPECT().Update(gomock.Any(), ct).Return(ct, nil)
+			},
+			category: model.Category{ID: 1, Name: "backend"},
+			expCode:  http.StatusOK,
+		},
+		{
+			name: "category not found",
+			mock: func(s *mockcategory.MockService, ct model.Category) {
+				s.EXPECT().Update(gomock.Any(), ct).Return(model.Category{}, ErrNotFound)
+			},
+			category: model.Category{ID: 1, Name: "backend"},
+			expCode:  http.StatusNotFound,
+		},
+		{
+			name: "name is taken",
+			mock: func(s *mockcategory.MockService, ct model.Category) {
+				s.EXPECT().Update(gomock.Any(), ct).Return(model.Category{}, ErrNameTaken)
+			},
+			category: model.Category{ID: 1, Name: "backend"},
+			expCode:  http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		cs := mockcategory.NewMockService(c)
+		tc.mock(cs, tc.category)
+		w := httptest.NewRecorder()
+
+		b := &bytes.Buffer{}
+		json.NewEncoder(b).Encode(tc.category)
+		r := httptest.NewRequest(http.MethodPatch, "/categories/1", b)
+		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, NewHandler(cs).Update)
+
+		assert.Equal(t, tc.expCode, w.Code)
+	}
+}
+
+func TestHandler_DeleteByID(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mock    func(*mockcategory.MockService)
+		expCode int
+	}{
+		{
+			name: "category is deleted",
+			mock: func(s *mockcategory.MockService) {
+				s.EXPECT().DeleteByID(gomock.Any(), 1).Return(nil)
+			},
+			expCode: http.StatusNoContent,
+		},
+		{
+			name: "category not found",
+			mock: func(s *mockcategory.MockService) {
+				s.EXPECT().DeleteByID(gomock.Any(), 1).Return(ErrNotFound)
+			},
+			expCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		cs := mockcategory.NewMockService(c)
+		tc.mock(cs)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodDelete, "/categories/1", nil)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, NewHandler(cs).DeleteByID)
+
+		assert.Equal(t, tc.expCode, w.Code)
+	}
+}
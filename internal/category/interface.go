@@ -0,0 +1,28 @@
+// Package category provides all category domain related logic.
+package category
+
+import (
+	"context"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+//go:generate mockgen -source=interface.go -destination=mock/mock.go
+
+// Repo is the interface all category repositories must implement.
+type Repo interface {
+	GetAll(context.Context, model.Page) ([]model.Category, model.PageInfo, error)
+	Create(context.Context, model.Category) (model.Category, error)
+	GetByID(context.Context, int) (model.Category, error)
+	Update(context.Context, model.Category) (model.Category, error)
+	DeleteByID(context.Context, int) error
+}
+
+// Service is the interface all category services must implement.
+type Service interface {
+	GetAll(context.Context, model.Page) ([]model.Category, model.PageInfo, error)
+	Create(context.Context, model.Category) (model.Category, error)
+	GetByID(context.Context, int) (model.Category, error)
+	Update(context.Context, model.Category) (model.Category, error)
+	DeleteByID(context.Context, int) error
+}
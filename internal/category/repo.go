@@ -0,0 +1,124 @@
+package category
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/store"
+)
+
+// repo is category repository implementation.
+type repo struct {
+	db *gorm.DB
+}
+
+// NewRepo creates and returns a new Repo instance.
+func NewRepo(db *gorm.DB) Repo {
+	return &repo{db}
+}
+
+// sortColumns maps sort parameters to category table columns.
+var sortColumns = map[string]string{
+	"":     "id",
+	"id":   "id",
+	"name": "name",
+}
+
+// GetAll gets and returns the page of categories.
+func (r *repo) GetAll(ctx context.Context, pg model.Page) (cs []model.Category, pi model.PageInfo, err error) {
+	if err := r.db.WithContext(ctx).Model(&model.Category{}).Count(&pi.Total).Error; err != nil {
+		return nil, pi, store.Wrap(err)
+	}
+	if err := r.db.WithContext(ctx).Scopes(pg.Paginate(sortColumns[pg.Sort])).Find(&cs).Error; err != nil {
+		return nil, pi, store.Wrap(err)
+	}
+
+	if len(cs) > pg.Limit {
+		cs = cs[:pg.Limit]
+		last := cs[len(cs)-1]
+		if pg.Sort == "name" {
+			pi.NextCursor = pg.NextCursor(last.Name, last.ID)
+		} else {
+			pi.NextCursor = pg.NextCursor(last.ID, last.ID)
+		}
+	}
+
+	return cs, pi, nil
+}
+
+// Create creates a category and returns it.
+func (r *repo) Create(ctx context.Context, c model.Category) (model.Category, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := nameTaken(tx, c); err != nil {
+			return err
+		}
+
+		return tx.Create(&c).Error
+	})
+
+	return c, store.Wrap(err)
+}
+
+// GetByID gets and returns the category with specific ID.
+func (r *repo) GetByID(ctx context.Context, id int) (c model.Category, err error) {
+	err = r.db.WithContext(ctx).First(&c, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c, ErrNotFound
+	}
+
+	return c, store.Wrap(err)
+}
+
+// Update updates the category and returns it.
+func (r *repo) Update(ctx context.Context, c model.Category) (model.Category, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := nameTaken(tx, c); err != nil {
+			return err
+		}
+
+		c.UpdatedAt = tx.NowFunc()
+		return tx.Model(&model.Category{}).Where("id = ?", c.ID).
+			Updates(map[string]interface{}{
+				"name":        c.Name,
+				"description": c.Description,
+				"updated_at":  c.UpdatedAt,
+			}).Error
+	})
+	if err != nil {
+		return model.Category{}, store.Wrap(err)
+	}
+
+	return c, nil
+}
+
+// nameTaken returns ErrNameTaken when another category has the same name as c.
+func nameTaken(tx *gorm.DB, c model.Category) error {
+	var n int64
+	err := tx.Model(&model.Category{}).Where("name = ? AND id <> ?", c.Name, c.ID).Count(&n).Error
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return ErrNameTaken
+	}
+
+	return nil
+}
+
+// DeleteByID deletes the category with specific ID and removes it from posts.
+func (r *repo) DeleteByID(ctx context.Context, id int) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return store.Wrap(err)
+	}
+
+	return store.Wrap(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM post_categories WHERE category_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&model.Category{}, id).Error
+	}))
+}
@@ -0,0 +1,67 @@
+package category
+
+import (
+	"context"
+	"strings"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// sorts are category list sort parameters.
+var sorts = []string{"id", "name"}
+
+// service is category service implementation.
+type service struct {
+	r Repo
+}
+
+// NewService creates and returns a new Service instance.
+func NewService(r Repo) Service {
+	return &service{r: r}
+}
+
+// GetAll gets and returns the page of categories.
+func (s *service) GetAll(ctx context.Context, pg model.Page) ([]model.Category, model.PageInfo, error) {
+	if err := pg.Validate(sorts...); err != nil {
+		return nil, model.PageInfo{}, err
+	}
+
+	return s.r.GetAll(ctx, pg)
+}
+
+// Create creates a category and returns it.
+func (s *service) Create(ctx context.Context, c model.Category) (model.Category, error) {
+	c.Name = strings.TrimSpace(c.Name)
+	if err := c.Validate(); err != nil {
+		return model.Category{}, err
+	}
+
+	return s.r.Create(ctx, c)
+}
+
+// GetByID gets and returns the category with specific ID.
+func (s *service) GetByID(ctx context.Context, id int) (model.Category, error) {
+	return s.r.GetByID(ctx, id)
+}
+
+// Update updates the category and returns it.
+func (s *service) Update(ctx context.Context, c model.Category) (model.Category, error) {
+	uc, err := s.r.GetByID(ctx, c.ID)
+	if err != nil {
+		return model.Category{}, err
+	}
+
+	uc.Name = strings.TrimSpace(c.Name)
+	uc.Description = c.Description
+	if err := uc.Validate(); err != nil {
+		return model.Category{}, err
+	}
+
+	return s.r.Update(ctx, uc)
+}
+
+// DeleteByID deletes the category with specific ID, posts lose the
+// category.
+func (s *service) DeleteByID(ctx context.Context, id int) error {
+	return s.r.DeleteByID(ctx, id)
+}
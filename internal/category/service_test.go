@@ -0,0 +1,133 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	mockcategory "github.com/imarrche/nix-ed/internal/category/mock"
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+func TestCategoryService_Create(t *testing.T) {
+	testcases := []struct {
+		name        string
+		mock        func(*mockcategory.MockRepo)
+		category    model.Category
+		expCategory model.Category
+		expError    error
+	}{
+		{
+			name: "category is created",
+			mock: func(r *mockcategory.MockRepo) {
+				r.EXPECT().Create(gomock.Any(), model.Category{Name: "backend"}).Return(model.Category{ID: 1, Name: "backend"}, nil)
+			},
+			category:    model.Category{Name: " backend "},
+			expCategory: model.Category{ID: 1, Name: "backend"},
+			expError:    nil,
+		},
+		{
+			name: "name is taken",
+			mock: func(r *mockcategory.MockRepo) {
+				r.EXPECT().Create(gomock.Any(), model.Category{Name: "backend"}).Return(model.Category{}, ErrNameTaken)
+			},
+			category: model.Category{Name: "backend"},
+			expError: ErrNameTaken,
+		},
+		{
+			name:     "validation errors",
+			mock:     func(_ *mockcategory.MockRepo) {},
+			category: model.Category{Name: " "},
+			expError: validation.Errors{"name": errors.New("cannot be blank")},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mockcategory.NewMockRepo(c)
+			tc.mock(repo)
+			s := NewService(repo)
+
+			ct, err := s.Create(context.Background(), tc.category)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expCategory, ct)
+		})
+	}
+}
+
+func TestCategoryService_Update(t *testing.T) {
+	testcases := []struct {
+		name        string
+		mock        func(*mockcategory.MockRepo)
+		category    model.Category
+		expCategory model.Category
+		expError    error
+	}{
+		{
+			name: "category is updated",
+			mock: func(r *mockcategory.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Category{ID: 1, Name: "backend"}, nil)
+				uc := model.Category{ID: 1, Name: "frontend", Description: "Client side."}
+				r.EXPECT().Update(gomock.Any(), uc).Return(uc, nil)
+			},
+			category:    model.Category{ID: 1, Name: "frontend", Description: "Client side."},
+			expCategory: model.Category{ID: 1, Name: "frontend", Description: "Client side."},
+			expError:    nil,
+		},
+		{
+			name: "category not found",
+			mock: func(r *mockcategory.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Category{}, ErrNotFound)
+			},
+			category: model.Category{ID: 1, Name: "frontend"},
+			expError: ErrNotFound,
+		},
+		{
+			name: "validation errors",
+			mock: func(r *mockcategory.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Category{ID: 1, Name: "backend"}, nil)
+			},
+			category: model.Category{ID: 1},
+			expError: validation.Errors{"name": errors.New("cannot be blank")},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mockcategory.NewMockRepo(c)
+			tc.mock(repo)
+			s := NewService(repo)
+
+			ct, err := s.Update(context.Background(), tc.category)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expCategory, ct)
+		})
+	}
+}
+
+func TestCategoryService_GetAll(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	repo := mockcategory.NewMockRepo(c)
+	pg := model.Page{Limit: 20, Sort: "name"}
+	repo.EXPECT().GetAll(gomock.Any(), pg).Return([]model.Category{{Name: "backend"}}, model.PageInfo{Total: 1}, nil)
+	s := NewService(repo)
+
+	cs, pi, err := s.GetAll(context.Background(), pg)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Category{{Name: "backend"}}, cs)
+	assert.Equal(t, model.PageInfo{Total: 1}, pi)
+
+	_, _, err = s.GetAll(context.Background(), model.Page{Limit: 20, Sort: "created"})
+	assert.Equal(t, validation.Errors{"sort": errors.New("must be a valid value")}, err)
+}
@@ -1,8 +1,20 @@
 package comment
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/imarrche/nix-ed/internal/store"
+)
 
 var (
 	// ErrNotFound is thrown when specified comment was not found in database.
-	ErrNotFound = errors.New("specified comment was not found")
+	ErrNotFound = store.New(store.ErrNotFound, "specified comment was not found")
+	// ErrVersionMismatch is thrown when specified comment was updated since
+	// the version being updated was read.
+	ErrVersionMismatch = store.New(store.ErrConflict, "specified comment version is outdated")
+	// ErrParentNotFound is thrown when the comment replied to was not found
+	// among the post's comments.
+	ErrParentNotFound = errors.New("specified parent comment was not found")
+	// ErrMaxDepth is thrown when a reply would be nested deeper than allowed.
+	ErrMaxDepth = errors.New("specified parent comment is nested too deep")
 )
@@ -2,95 +2,81 @@ package comment
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"io/ioutil"
 	"net/http"
 	"strconv"
 
+	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/labstack/echo/v4"
 
 	"github.com/imarrche/nix-ed/internal/auth"
+	"github.com/imarrche/nix-ed/internal/etag"
 	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/patch"
+	"github.com/imarrche/nix-ed/internal/post"
+	"github.com/imarrche/nix-ed/internal/render"
 )
 
-type key int
-
-const (
-	uEmailKey key = iota
-)
-
-type errResponse struct {
-	Name   string `json:"name" xml:"name"`
-	Email  string `json:"email" xml:"email"`
-	Body   string `json:"body" xml:"body"`
-	UserID string `json:"userId" xml:"userId"`
-}
+var errInvalidView = errors.New("must be a valid value")
 
 // Handler is http handler for comment resource.
 type Handler struct {
 	cs Service
-	as auth.Service
+	rc post.ReactionCounter
 }
 
-// NewHandler creates and returns a new Handler instacne.
-func NewHandler(cs Service, as auth.Service) *Handler {
-	return &Handler{cs: cs, as: as}
+// NewHandler creates and returns a new Handler instacne, comments get reaction
+// counts from rc when it isn't nil.
+func NewHandler(cs Service, rc post.ReactionCounter) *Handler {
+	return &Handler{cs: cs, rc: rc}
 }
 
-// respond responds to request with XML or JSON.
-func respond(c echo.Context, code int, data interface{}) error {
-	if c.Request().Header.Get("Accept-Encoding") == "text/xml" {
-		return c.XML(code, data)
+// setPageHeaders sets the total count and the next page cursor headers.
+func setPageHeaders(c echo.Context, pi model.PageInfo) {
+	c.Response().Header().Set("X-Total-Count", strconv.FormatInt(pi.Total, 10))
+	if pi.NextCursor != "" {
+		c.Response().Header().Set("X-Next-Cursor", pi.NextCursor)
 	}
-
-	return c.JSON(code, data)
 }
 
-type authResponse struct {
-	Error map[string]interface{} `json:"error"`
-	ID    string                 `json:"id"`
-	Email string                 `json:"email"`
+// CommentAuthor is middleware that ensures that comment's author or a user
+// who moderates comments made a request.
+func (h *Handler) CommentAuthor(next echo.HandlerFunc) echo.HandlerFunc {
+	return h.author(func(ctx context.Context, id int) (model.Comment, error) {
+		return h.cs.GetByID(ctx, id, auth.ViewerFrom(ctx))
+	}, next)
 }
 
-// Auth is middleware for user authentication.
-func (h *Handler) Auth(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		data, err := h.as.GetUserInfo(c.Request().Header.Get("Authorization"))
-		if err != nil {
-			return c.Redirect(http.StatusTemporaryRedirect, "/auth/google/sign-in")
-		}
-		udata := &authResponse{}
-		if err := json.Unmarshal(data, &udata); err != nil || udata.Error != nil {
-			return c.NoContent(http.StatusUnauthorized)
-		}
-
-		r := c.Request()
-		r = r.WithContext(context.WithValue(r.Context(), uEmailKey, udata.Email))
-		c.SetRequest(r)
-		return next(c)
-	}
+// DeletedCommentAuthor is middleware that ensures that deleted comment's
+// author or a user who moderates comments made a request.
+func (h *Handler) DeletedCommentAuthor(next echo.HandlerFunc) echo.HandlerFunc {
+	return h.author(h.cs.GetDeletedByID, next)
 }
 
-// CommentAuthor is middleware that ensures that comment's author made a request.
-func (h *Handler) CommentAuthor(next echo.HandlerFunc) echo.HandlerFunc {
+// author returns middleware that ensures that the author of the comment got
+// by get made a request. Comments are owned by their authors' user IDs.
+func (h *Handler) author(get func(context.Context, int) (model.Comment, error), next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		uEmail, ok := c.Request().Context().Value(uEmailKey).(string)
+		pr, ok := auth.PrincipalFrom(c)
 		if !ok {
-			return c.NoContent(http.StatusInternalServerError)
+			return echo.NewHTTPError(http.StatusInternalServerError)
 		}
 		cID, err := strconv.Atoi(c.Param("id"))
 		if err != nil {
-			return respond(c, http.StatusBadRequest, err)
+			return echo.NewHTTPError(http.StatusBadRequest, err)
 		}
 
-		cm, err := h.cs.GetByID(cID)
+		cm, err := get(c.Request().Context(), cID)
 		if err == ErrNotFound {
-			return c.NoContent(http.StatusNotFound)
+			return echo.NewHTTPError(http.StatusNotFound, err)
 		} else if err != nil {
-			return c.NoContent(http.StatusInternalServerError)
+			return err
 		}
 
-		if cm.Email != uEmail {
-			return c.NoContent(http.StatusForbidden)
+		owner := cm.UserID != nil && *cm.UserID == pr.ID
+		if !owner && !pr.Can(model.PermModerateComments) {
+			return echo.NewHTTPError(http.StatusForbidden)
 		}
 
 		return next(c)
@@ -99,22 +85,46 @@ func (h *Handler) CommentAuthor(next echo.HandlerFunc) echo.HandlerFunc {
 
 // GetAll returns comment list.
 // @Summary Show all comments
-// @Descriptions show all comments
+// @Descriptions show the page of comments
 // @Tags comments
 // @ID comment-list
-// @Accept json
-// @Produce json,xml
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack,text/csv
+// @Param limit query int false "page size" default(20) maximum(100)
+// @Param offset query int false "number of comments to skip"
+// @Param cursor query string false "cursor returned by the previous page"
+// @Param sort query string false "sort field, thread sorts replies after the comments they reply to" Enums(id, name, created, updated, thread)
+// @Param order query string false "sort order" Enums(asc, desc)
+// @Param updated_since query string false "only comments updated at or after the time" format(date-time)
 // @Success 200 {array} model.Comment
-// @Failure 500 ""
+// @Header 200 {integer} X-Total-Count "total number of comments"
+// @Header 200 {string} X-Next-Cursor "cursor of the next page"
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
 // @Router /comments [get]
 func (h *Handler) GetAll(c echo.Context) error {
-	cs, err := h.cs.GetAll()
-	if err != nil {
-		return c.NoContent(http.StatusInternalServerError)
+	f := model.CommentFilter{}
+	if err := c.Bind(&f); err != nil {
+		return err
+	}
+	f.Viewer = auth.Viewer(c)
+	pg := model.Page{Limit: model.DefaultPageLimit}
+	if err := c.Bind(&pg); err != nil {
+		return err
 	}
 
-	return respond(c, http.StatusOK, cs)
+	cs, pi, err := h.cs.GetAll(c.Request().Context(), f, pg)
+	if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+	if err := h.countReactions(c, cs); err != nil {
+		return err
+	}
 
+	setPageHeaders(c, pi)
+	return render.Respond(c, http.StatusOK, cs)
 }
 
 // Create creates a comment.
@@ -122,30 +132,135 @@ func (h *Handler) GetAll(c echo.Context) error {
 // @Descriptions create a comment
 // @Tags comments
 // @ID comment-create
-// @Accept json
-// @Produce json,xml
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
 // @Param input body model.Comment true "comment data"
 // @Success 201 {object} model.Comment
-// @Failure 400 {object} errResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
 // @Router /comments [post]
 func (h *Handler) Create(c echo.Context) error {
-	email, ok := c.Request().Context().Value(uEmailKey).(string)
+	pr, ok := auth.PrincipalFrom(c)
 	if !ok {
-		return c.NoContent(http.StatusInternalServerError)
+		return echo.NewHTTPError(http.StatusInternalServerError)
 	}
 
 	cm := model.Comment{}
 	if err := c.Bind(&cm); err != nil {
-		return respond(c, http.StatusBadRequest, err)
+		return err
 	}
-	cm.Email = email
+	cm.Email = pr.Email
+	cm.UserID = &pr.ID
 
-	cm, err := h.cs.Create(cm)
+	cm, err := h.cs.Create(c.Request().Context(), cm)
+	if err == post.ErrNotFound || err == ErrParentNotFound || err == ErrMaxDepth {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusCreated, cm)
+}
+
+// GetAllByPostID returns post's comment list.
+// @Summary Show post's comments
+// @Descriptions show the page of post's comments, deleted comments with replies are shown as placeholders
+// @Tags comments
+// @ID post-comment-list
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack,text/csv
+// @Param id path int true "post id"
+// @Param limit query int false "page size" default(20) maximum(100)
+// @Param offset query int false "number of comments to skip"
+// @Param cursor query string false "cursor returned by the previous page"
+// @Param sort query string false "sort field, thread sorts replies after the comments they reply to" Enums(id, name, created, updated, thread)
+// @Param order query string false "sort order" Enums(asc, desc)
+// @Param view query string false "flat list or tree of top-level comments with their replies, pages of trees are pages of top-level comments" Enums(flat, tree) default(flat)
+// @Success 200 {array} model.Comment
+// @Header 200 {integer} X-Total-Count "total number of post's comments or top-level comments"
+// @Header 200 {string} X-Next-Cursor "cursor of the next page"
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /posts/{id}/comments [get]
+func (h *Handler) GetAllByPostID(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return respond(c, http.StatusInternalServerError, err)
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
-	return respond(c, http.StatusCreated, cm)
+	pg := model.Page{Limit: model.DefaultPageLimit}
+	if err := c.Bind(&pg); err != nil {
+		return err
+	}
+
+	list := h.cs.GetAllByPostID
+	switch c.QueryParam("view") {
+	case "", "flat":
+	case "tree":
+		list = h.cs.GetTreeByPostID
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, validation.Errors{"view": errInvalidView})
+	}
+
+	cs, pi, err := list(c.Request().Context(), id, auth.Viewer(c), pg)
+	if err == post.ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+	if err := h.countReactions(c, cs); err != nil {
+		return err
+	}
+
+	setPageHeaders(c, pi)
+	return render.Respond(c, http.StatusOK, cs)
+}
+
+// CreateByPostID creates a post's comment.
+// @Summary Create a post's comment
+// @Descriptions create a post's comment
+// @Tags comments
+// @ID post-comment-create
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "post id"
+// @Param input body model.Comment true "comment data"
+// @Success 201 {object} model.Comment
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /posts/{id}/comments [post]
+func (h *Handler) CreateByPostID(c echo.Context) error {
+	pr, ok := auth.PrincipalFrom(c)
+	if !ok {
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	cm := model.Comment{}
+	if err := c.Bind(&cm); err != nil {
+		return err
+	}
+	cm.Email = pr.Email
+	cm.UserID = &pr.ID
+	cm.PostID = id
+
+	cm, err = h.cs.Create(c.Request().Context(), cm)
+	if err == post.ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err == ErrParentNotFound || err == ErrMaxDepth {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusCreated, cm)
 }
 
 // GetByID returns comment detail.
@@ -153,88 +268,256 @@ func (h *Handler) Create(c echo.Context) error {
 // @Descriptions comment detail
 // @Tags comments
 // @ID comment-detail
-// @Accept json
-// @Produce json,xml
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
 // @Param id path int true "comment id"
+// @Param If-None-Match header string false "ETag of the cached comment"
 // @Success 200 {object} model.Comment
-// @Failure 400 {object} errResponse
-// @Failure 404 ""
+// @Header 200 {string} ETag "comment version"
+// @Success 304 ""
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
 // @Router /comments/{id} [get]
 func (h *Handler) GetByID(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return respond(c, http.StatusBadRequest, err)
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
-	cm, err := h.cs.GetByID(id)
+	cm, err := h.cs.GetByID(c.Request().Context(), id, auth.Viewer(c))
 	if err == ErrNotFound {
-		return c.NoContent(http.StatusNotFound)
+		return echo.NewHTTPError(http.StatusNotFound, err)
 	} else if err != nil {
-		return respond(c, http.StatusInternalServerError, err)
+		return err
 	}
 
-	return respond(c, http.StatusOK, cm)
+	tag := etag.New(cm.Version)
+	c.Response().Header().Set("ETag", tag)
+	if etag.Match(c.Request().Header.Get("If-None-Match"), tag, true) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	cs := []model.Comment{cm}
+	if err := h.countReactions(c, cs); err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusOK, cs[0])
+}
+
+// countReactions sets reaction counts of the comments and their replies,
+// reactions of the user who made a request are flagged. Deleted comments
+// shown as placeholders get no counts.
+func (h *Handler) countReactions(c echo.Context, cs []model.Comment) error {
+	if h.rc == nil {
+		return nil
+	}
+
+	var ids []int
+	var collect func([]model.Comment)
+	collect = func(cs []model.Comment) {
+		for _, cm := range cs {
+			if !cm.Deleted {
+				ids = append(ids, cm.ID)
+			}
+			collect(cm.Replies)
+		}
+	}
+	collect(cs)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	counts, err := h.rc.Count(c.Request().Context(), model.ReactionComment, ids, auth.Viewer(c))
+	if err != nil {
+		return err
+	}
+	var set func([]model.Comment)
+	set = func(cs []model.Comment) {
+		for i := range cs {
+			cs[i].Reactions = counts[cs[i].ID]
+			set(cs[i].Replies)
+		}
+	}
+	set(cs)
+
+	return nil
 }
 
 // Update updates a comment.
 // @Summary Comment update
-// @Descriptions comment update
+// @Descriptions comment update, JSON Merge Patch and JSON Patch bodies change only the fields they contain
 // @Tags comments
 // @ID comment-update
-// @Accept json
-// @Produce xml
+// @Accept json,xml,application/yaml,application/msgpack,application/merge-patch+json,application/json-patch+json
+// @Produce json,xml,application/yaml,application/msgpack
 // @Param id path int true "comment id"
-// @Param input body model.Comment true "comment data"
+// @Param input body model.Comment true "comment data or patch"
+// @Param If-Match header string false "ETag of the comment version being updated"
 // @Success 200 {object} model.Comment
-// @Failure 400 {object} errResponse
-// @Failure 404 ""
+// @Header 200 {string} ETag "comment version"
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 412 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
 // @Router /comments/{id} [patch]
 func (h *Handler) Update(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return respond(c, http.StatusBadRequest, err)
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
 	cm := model.Comment{}
-	if err := c.Bind(&cm); err != nil {
-		return respond(c, http.StatusBadRequest, err)
+	mt := patch.MediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if mt == "" {
+		if err := c.Bind(&cm); err != nil {
+			return err
+		}
 	}
 	cm.ID = id
+	cm.Version = 0
+
+	im := c.Request().Header.Get("If-Match")
+	if im != "" || mt != "" {
+		cur, err := h.cs.GetByID(c.Request().Context(), id, auth.Viewer(c))
+		if err == ErrNotFound {
+			return echo.NewHTTPError(http.StatusNotFound, err)
+		} else if err != nil {
+			return err
+		}
+		if im != "" && !etag.Match(im, etag.New(cur.Version), false) {
+			return echo.NewHTTPError(http.StatusPreconditionFailed)
+		}
+
+		// Patches are applied to the current comment, so fields missing in the
+		// patch keep their values.
+		if mt != "" {
+			doc, err := ioutil.ReadAll(c.Request().Body)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err)
+			}
+			cm = cur
+			if err := patch.Apply(mt, doc, &cm); err != nil {
+				return echo.NewHTTPError(patch.StatusCode(err), err)
+			}
+			cm.ID = id
+		}
+		cm.Version = cur.Version
+	}
 
-	cm, err = h.cs.Update(cm)
+	cm, err = h.cs.Update(c.Request().Context(), cm)
 	if err == ErrNotFound {
-		return c.NoContent(http.StatusNotFound)
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err == ErrVersionMismatch && im == "" {
+		return echo.NewHTTPError(http.StatusConflict, err)
+	} else if err == ErrVersionMismatch {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, err)
+	} else if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	} else if err != nil {
-		return respond(c, http.StatusInternalServerError, err)
+		return err
 	}
 
-	return respond(c, http.StatusOK, cm)
+	c.Response().Header().Set("ETag", etag.New(cm.Version))
+	return render.Respond(c, http.StatusOK, cm)
 }
 
 // DeleteByID deletes a comment.
 // @Summary Comment delete
-// @Descriptions comment delete
+// @Descriptions move a comment to trash
 // @Tags comments
 // @ID comment-delete
-// @Accept json
-// @Produce json,xml
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
 // @Param id path int true "comment id"
 // @Success 204 ""
-// @Failure 400 {object} errResponse
-// @Failure 404 ""
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
 // @Router /comments/{id} [delete]
 func (h *Handler) DeleteByID(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return respond(c, http.StatusBadRequest, err)
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
-	err = h.cs.DeleteByID(id)
+	err = h.cs.DeleteByID(c.Request().Context(), id)
 	if err == ErrNotFound {
-		return c.NoContent(http.StatusNotFound)
+		return echo.NewHTTPError(http.StatusNotFound, err)
 	} else if err != nil {
-		return respond(c, http.StatusInternalServerError, err)
+		return err
 	}
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// GetTrash returns user's deleted comment list.
+// @Summary Show user's deleted comments
+// @Descriptions show the page of comments user moved to trash
+// @Tags comments
+// @ID comment-trash
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack,text/csv
+// @Param limit query int false "page size" default(20) maximum(100)
+// @Param offset query int false "number of comments to skip"
+// @Param cursor query string false "cursor returned by the previous page"
+// @Param sort query string false "sort field, thread sorts replies after the comments they reply to" Enums(id, name, created, updated, thread)
+// @Param order query string false "sort order" Enums(asc, desc)
+// @Success 200 {array} model.Comment
+// @Header 200 {integer} X-Total-Count "total number of deleted comments"
+// @Header 200 {string} X-Next-Cursor "cursor of the next page"
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /comments/trash [get]
+func (h *Handler) GetTrash(c echo.Context) error {
+	pr, ok := auth.PrincipalFrom(c)
+	if !ok {
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	pg := model.Page{Limit: model.DefaultPageLimit}
+	if err := c.Bind(&pg); err != nil {
+		return err
+	}
+
+	cs, pi, err := h.cs.GetTrash(c.Request().Context(), pr.ID, pg)
+	if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+
+	setPageHeaders(c, pi)
+	return render.Respond(c, http.StatusOK, cs)
+}
+
+// Restore restores a deleted comment.
+// @Summary Comment restore
+// @Descriptions restore a comment from trash, its post must not be deleted
+// @Tags comments
+// @ID comment-restore
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "comment id"
+// @Success 200 {object} model.Comment
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /comments/{id}/restore [post]
+func (h *Handler) Restore(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	cm, err := h.cs.Restore(c.Request().Context(), id)
+	if err == ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err == post.ErrNotFound {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusOK, cm)
+}
@@ -2,166 +2,333 @@ package comment
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/golang/mock/gomock"
+	"github.com/imarrche/nix-ed/internal/handlertest"
 	"github.com/imarrche/nix-ed/internal/model"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 
-	mockauth "github.com/imarrche/nix-ed/internal/auth/mock"
+	"github.com/imarrche/nix-ed/internal/auth"
 	mockcomment "github.com/imarrche/nix-ed/internal/comment/mock"
+	"github.com/imarrche/nix-ed/internal/patch"
+	"github.com/imarrche/nix-ed/internal/post"
+	mockpost "github.com/imarrche/nix-ed/internal/post/mock"
 )
 
-func TestHandler_Auth(t *testing.T) {
+func TestHandler_CommentAuthor(t *testing.T) {
 	next := func(c echo.Context) error {
 		return c.NoContent(http.StatusOK)
 	}
 
 	testcases := []struct {
 		name    string
-		mock    func(*mockauth.MockService)
+		mock    func(*mockcomment.MockService, model.Comment)
+		comment model.Comment
+		role    string
 		expCode int
 	}{
 		{
-			name: "user is authenticated",
-			mock: func(s *mockauth.MockService) {
-				data := []byte(`{"id":"1","email":"u@t.com"}`)
-				s.EXPECT().GetUserInfo("token").Return(data, nil)
+			name: "user is comment author",
+			mock: func(s *mockcomment.MockService, cm model.Comment) {
+				s.EXPECT().GetByID(gomock.Any(), cm.ID, "1").Return(cm, nil)
 			},
+			comment: model.Comment{ID: 1, Body: "Comment", Email: "u@t.com", UserID: strPtr("1")},
 			expCode: http.StatusOK,
 		},
 		{
-			name: "get user info error",
-			mock: func(s *mockauth.MockService) {
-				s.EXPECT().GetUserInfo("token").Return(nil, errors.New("internal error"))
+			name: "comment not found",
+			mock: func(s *mockcomment.MockService, cm model.Comment) {
+				s.EXPECT().GetByID(gomock.Any(), cm.ID, "1").Return(model.Comment{}, ErrNotFound)
 			},
-			expCode: http.StatusTemporaryRedirect,
+			comment: model.Comment{ID: 1, Body: "Comment", Email: "u@t.com", UserID: strPtr("1")},
+			expCode: http.StatusNotFound,
 		},
 		{
-			name: "invalid auth data",
-			mock: func(s *mockauth.MockService) {
-				data := []byte(`{"id":1,"email":"u@t.com"}`)
-				s.EXPECT().GetUserInfo("token").Return(data, nil)
+			name: "internal error",
+			mock: func(s *mockcomment.MockService, cm model.Comment) {
+				s.EXPECT().GetByID(gomock.Any(), cm.ID, "1").Return(model.Comment{}, errors.New("internal error"))
+			},
+			comment: model.Comment{ID: 1, Body: "Comment", Email: "u@t.com", UserID: strPtr("1")},
+			expCode: http.StatusInternalServerError,
+		},
+		{
+			name: "user is not a comment author",
+			mock: func(s *mockcomment.MockService, cm model.Comment) {
+				s.EXPECT().GetByID(gomock.Any(), cm.ID, "1").Return(cm, nil)
 			},
-			expCode: http.StatusUnauthorized,
+			comment: model.Comment{ID: 1, Body: "Comment", Email: "u@t.com", UserID: strPtr("2")},
+			expCode: http.StatusForbidden,
+		},
+		{
+			name: "comment has no user",
+			mock: func(s *mockcomment.MockService, cm model.Comment) {
+				s.EXPECT().GetByID(gomock.Any(), cm.ID, "1").Return(cm, nil)
+			},
+			comment: model.Comment{ID: 1, Body: "Comment", Email: "u@t.com"},
+			expCode: http.StatusForbidden,
+		},
+		{
+			name: "moderator is not a comment author",
+			mock: func(s *mockcomment.MockService, cm model.Comment) {
+				s.EXPECT().GetByID(gomock.Any(), cm.ID, "1").Return(cm, nil)
+			},
+			comment: model.Comment{ID: 1, Body: "Comment", Email: "u@t.com", UserID: strPtr("2")},
+			role:    model.RoleModerator,
+			expCode: http.StatusOK,
+		},
+		{
+			name: "admin is not a comment author",
+			mock: func(s *mockcomment.MockService, cm model.Comment) {
+				s.EXPECT().GetByID(gomock.Any(), cm.ID, "1").Return(cm, nil)
+			},
+			comment: model.Comment{ID: 1, Body: "Comment", Email: "u@t.com", UserID: strPtr("2")},
+			role:    model.RoleAdmin,
+			expCode: http.StatusOK,
 		},
 	}
 
 	for _, tc := range testcases {
 		c := gomock.NewController(t)
 		defer c.Finish()
-		as := mockauth.NewMockService(c)
-		tc.mock(as)
+		cs := mockcomment.NewMockService(c)
+		tc.mock(cs, tc.comment)
 		w := httptest.NewRecorder()
-		r := httptest.NewRequest(http.MethodGet, "/comments", nil)
-		r.Header.Add("Authorization", "token")
+		r := httptest.NewRequest(http.MethodPatch, "/comments/1", nil)
+		pr := auth.Principal{ID: "1", Email: "u@t.com"}
+		if tc.role != "" {
+			pr.Roles = []string{tc.role}
+		}
+		r = r.WithContext(auth.WithPrincipal(r.Context(), pr))
 
 		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
 
-		hf := NewHandler(nil, as).Auth(next)
-		hf(ctx)
+		hf := NewHandler(cs, nil).CommentAuthor(next)
+		handlertest.Serve(ctx, hf)
 
 		assert.Equal(t, tc.expCode, w.Code)
 
 	}
 }
 
-func TestHandler_CommentAuthor(t *testing.T) {
-	next := func(c echo.Context) error {
-		return c.NoContent(http.StatusOK)
-	}
-
+func TestHandler_GetAll(t *testing.T) {
 	testcases := []struct {
-		name    string
-		mock    func(*mockcomment.MockService, model.Comment)
-		comment model.Comment
-		expCode int
+		name          string
+		mock          func(*mockcomment.MockService, []model.Comment)
+		query         string
+		comments      []model.Comment
+		expComments   []model.Comment
+		expTotal      string
+		expNextCursor string
+		expCode       int
 	}{
 		{
-			name: "user is comment author",
-			mock: func(s *mockcomment.MockService, cm model.Comment) {
-				s.EXPECT().GetByID(cm.ID).Return(cm, nil)
+			name: "comment are retrieved",
+			mock: func(s *mockcomment.MockService, cs []model.Comment) {
+				pg := model.Page{Limit: 2, Sort: "created", Order: "desc"}
+				s.EXPECT().GetAll(gomock.Any(), model.CommentFilter{}, pg).Return(cs, model.PageInfo{Total: 3, NextCursor: "c"}, nil)
 			},
-			comment: model.Comment{ID: 1, Body: "Comment", Email: "u@t.com"},
-			expCode: http.StatusOK,
+			query:         "?limit=2&sort=created&order=desc",
+			comments:      []model.Comment{{Body: "Comment 1"}, {Body: "Comment 2"}},
+			expComments:   []model.Comment{{Body: "Comment 1"}, {Body: "Comment 2"}},
+			expTotal:      "3",
+			expNextCursor: "c",
+			expCode:       http.StatusOK,
 		},
 		{
-			name: "comment not found",
-			mock: func(s *mockcomment.MockService, cm model.Comment) {
-				s.EXPECT().GetByID(cm.ID).Return(model.Comment{}, ErrNotFound)
+			name: "updated comments are retrieved",
+			mock: func(s *mockcomment.MockService, cs []model.Comment) {
+				f := model.CommentFilter{UpdatedSince: time.Date(2021, 2, 1, 10, 0, 0, 0, time.UTC)}
+				pg := model.Page{Limit: model.DefaultPageLimit}
+				s.EXPECT().GetAll(gomock.Any(), f, pg).Return(cs, model.PageInfo{Total: 1}, nil)
 			},
-			comment: model.Comment{ID: 1, Body: "Comment", Email: "u@t.com"},
-			expCode: http.StatusNotFound,
+			query:       "?updated_since=2021-02-01T10:00:00Z",
+			comments:    []model.Comment{{Body: "Comment 2"}},
+			expComments: []model.Comment{{Body: "Comment 2"}},
+			expTotal:    "1",
+			expCode:     http.StatusOK,
+		},
+		{
+			name:    "invalid query",
+			mock:    func(s *mockcomment.MockService, cs []model.Comment) {},
+			query:   "?limit=ten",
+			expCode: http.StatusBadRequest,
+		},
+		{
+			name: "validation errors",
+			mock: func(s *mockcomment.MockService, cs []model.Comment) {
+				pg := model.Page{Limit: model.DefaultPageLimit, Sort: "body"}
+				err := validation.Errors{"sort": errors.New("must be a valid value")}
+				s.EXPECT().GetAll(gomock.Any(), model.CommentFilter{}, pg).Return(nil, model.PageInfo{}, err)
+			},
+			query:   "?sort=body",
+			expCode: http.StatusBadRequest,
 		},
 		{
 			name: "internal error",
+			mock: func(s *mockcomment.MockService, cs []model.Comment) {
+				pg := model.Page{Limit: model.DefaultPageLimit}
+				s.EXPECT().GetAll(gomock.Any(), model.CommentFilter{}, pg).Return(nil, model.PageInfo{}, errors.New("internal error"))
+			},
+			comments: []model.Comment{{Body: "Comment 1"}, {Body: "Comment 2"}},
+			expCode:  http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		cs := mockcomment.NewMockService(c)
+		tc.mock(cs, tc.comments)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/comments"+tc.query, nil)
+
+		ctx := echo.New().NewContext(r, w)
+
+		handlertest.Serve(ctx, NewHandler(cs, nil).GetAll)
+
+		var comments []model.Comment
+		json.NewDecoder(w.Body).Decode(&comments)
+
+		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expTotal, w.Header().Get("X-Total-Count"))
+		assert.Equal(t, tc.expNextCursor, w.Header().Get("X-Next-Cursor"))
+		assert.Equal(t, tc.expComments, comments)
+	}
+}
+
+func TestHandler_Create(t *testing.T) {
+	testcases := []struct {
+		name       string
+		mock       func(*mockcomment.MockService, model.Comment)
+		comment    model.Comment
+		expComment model.Comment
+		expCode    int
+	}{
+		{
+			name: "comment is created",
 			mock: func(s *mockcomment.MockService, cm model.Comment) {
-				s.EXPECT().GetByID(cm.ID).Return(model.Comment{}, errors.New("internal error"))
+				s.EXPECT().Create(gomock.Any(), cm).Return(cm, nil)
 			},
-			comment: model.Comment{ID: 1, Body: "Comment", Email: "u@t.com"},
-			expCode: http.StatusInternalServerError,
+			comment:    model.Comment{Email: "u@t.com", UserID: strPtr("1"), Body: "Comment 1", PostID: 1},
+			expComment: model.Comment{Email: "u@t.com", UserID: strPtr("1"), Body: "Comment 1", PostID: 1},
+			expCode:    http.StatusCreated,
 		},
 		{
-			name: "user is not a comment author",
+			name: "post is not found",
 			mock: func(s *mockcomment.MockService, cm model.Comment) {
-				s.EXPECT().GetByID(cm.ID).Return(cm, nil)
+				s.EXPECT().Create(gomock.Any(), cm).Return(model.Comment{}, post.ErrNotFound)
 			},
-			comment: model.Comment{ID: 1, Body: "Comment", Email: "u2@t.com"},
-			expCode: http.StatusForbidden,
+			comment: model.Comment{Email: "u@t.com", UserID: strPtr("1"), Body: "Comment 1", PostID: 2},
+			expCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "comment creating error",
+			mock: func(s *mockcomment.MockService, cm model.Comment) {
+				s.EXPECT().Create(gomock.Any(), cm).Return(model.Comment{}, errors.New("internal error"))
+			},
+			comment: model.Comment{Email: "u@t.com", UserID: strPtr("1"), Body: "Comment 1", PostID: 1},
+			expCode: http.StatusInternalServerError,
 		},
 	}
 
 	for _, tc := range testcases {
 		c := gomock.NewController(t)
 		defer c.Finish()
-		cs := mockcomment.NewMockService(c)
-		tc.mock(cs, tc.comment)
+		ps := mockcomment.NewMockService(c)
+		tc.mock(ps, tc.comment)
 		w := httptest.NewRecorder()
-		r := httptest.NewRequest(http.MethodPatch, "/comments/1", nil)
-		r = r.WithContext(context.WithValue(r.Context(), uEmailKey, "u@t.com"))
+
+		b := &bytes.Buffer{}
+		json.NewEncoder(b).Encode(tc.comment)
+		r := httptest.NewRequest(http.MethodPost, "/comments", b)
+		r = r.WithContext(auth.WithPrincipal(r.Context(), auth.Principal{ID: "1", Email: tc.comment.Email}))
+		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
 		ctx := echo.New().NewContext(r, w)
-		ctx.SetParamNames("id")
-		ctx.SetParamValues("1")
 
-		hf := NewHandler(cs, nil).CommentAuthor(next)
-		hf(ctx)
+		handlertest.Serve(ctx, NewHandler(ps, nil).Create)
 
-		assert.Equal(t, tc.expCode, w.Code)
+		var cm model.Comment
+		json.NewDecoder(w.Body).Decode(&cm)
 
+		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expComment, cm)
 	}
 }
 
-func TestHandler_GetAll(t *testing.T) {
+func TestHandler_GetAllByPostID(t *testing.T) {
 	testcases := []struct {
 		name        string
 		mock        func(*mockcomment.MockService, []model.Comment)
+		query       string
 		comments    []model.Comment
 		expComments []model.Comment
+		expTotal    string
 		expCode     int
 	}{
 		{
-			name: "comment are retrieved",
+			name: "post's comments are retrieved",
 			mock: func(s *mockcomment.MockService, cs []model.Comment) {
-				s.EXPECT().GetAll().Return(cs, nil)
+				pg := model.Page{Limit: model.DefaultPageLimit}
+				s.EXPECT().GetAllByPostID(gomock.Any(), 1, "", pg).Return(cs, model.PageInfo{Total: 2}, nil)
 			},
-			comments:    []model.Comment{{Body: "Comment 1"}, {Body: "Comment 2"}},
-			expComments: []model.Comment{{Body: "Comment 1"}, {Body: "Comment 2"}},
+			comments:    []model.Comment{{Body: "Comment 1", PostID: 1}, {Body: "Comment 2", PostID: 1}},
+			expComments: []model.Comment{{Body: "Comment 1", PostID: 1}, {Body: "Comment 2", PostID: 1}},
+			expTotal:    "2",
 			expCode:     http.StatusOK,
 		},
+		{
+			name: "post's comment tree is retrieved",
+			mock: func(s *mockcomment.MockService, cs []model.Comment) {
+				pg := model.Page{Limit: model.DefaultPageLimit}
+				s.EXPECT().GetTreeByPostID(gomock.Any(), 1, "", pg).Return(cs, model.PageInfo{Total: 1}, nil)
+			},
+			query: "?view=tree",
+			comments: []model.Comment{
+				{ID: 1, Body: model.DeletedCommentBody, PostID: 1, Deleted: true, Replies: []model.Comment{
+					{ID: 2, Body: "Comment 2", PostID: 1, ParentID: intPtr(1), Depth: 1},
+				}},
+			},
+			expComments: []model.Comment{
+				{ID: 1, Body: model.DeletedCommentBody, PostID: 1, Deleted: true, Replies: []model.Comment{
+					{ID: 2, Body: "Comment 2", PostID: 1, ParentID: intPtr(1), Depth: 1},
+				}},
+			},
+			expTotal: "1",
+			expCode:  http.StatusOK,
+		},
+		{
+			name:    "invalid view",
+			mock:    func(s *mockcomment.MockService, cs []model.Comment) {},
+			query:   "?view=graph",
+			expCode: http.StatusBadRequest,
+		},
+		{
+			name: "post is not found",
+			mock: func(s *mockcomment.MockService, cs []model.Comment) {
+				pg := model.Page{Limit: model.DefaultPageLimit}
+				s.EXPECT().GetAllByPostID(gomock.Any(), 1, "", pg).Return(nil, model.PageInfo{}, post.ErrNotFound)
+			},
+			expCode: http.StatusNotFound,
+		},
 		{
 			name: "internal error",
 			mock: func(s *mockcomment.MockService, cs []model.Comment) {
-				s.EXPECT().GetAll().Return(nil, errors.New("internal error"))
+				pg := model.Page{Limit: model.DefaultPageLimit}
+				s.EXPECT().GetAllByPostID(gomock.Any(), 1, "", pg).Return(nil, model.PageInfo{}, errors.New("internal error"))
 			},
-			comments: []model.Comment{{Body: "Comment 1"}, {Body: "Comment 2"}},
-			expCode:  http.StatusInternalServerError,
+			expCode: http.StatusInternalServerError,
 		},
 	}
 
@@ -169,24 +336,59 @@ func TestHandler_GetAll(t *testing.T) {
 		c := gomock.NewController(t)
 		defer c.Finish()
 		cs := mockcomment.NewMockService(c)
-		as := mockauth.NewMockService(c)
 		tc.mock(cs, tc.comments)
 		w := httptest.NewRecorder()
-		r := httptest.NewRequest(http.MethodGet, "/posts", nil)
+		r := httptest.NewRequest(http.MethodGet, "/posts/1/comments"+tc.query, nil)
 
 		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
 
-		NewHandler(cs, as).GetAll(ctx)
+		handlertest.Serve(ctx, NewHandler(cs, nil).GetAllByPostID)
 
 		var comments []model.Comment
 		json.NewDecoder(w.Body).Decode(&comments)
 
 		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expTotal, w.Header().Get("X-Total-Count"))
 		assert.Equal(t, tc.expComments, comments)
 	}
 }
 
-func TestHandler_Create(t *testing.T) {
+func TestHandler_GetAllByPostID_Reactions(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	tree := []model.Comment{
+		{ID: 1, Body: model.DeletedCommentBody, PostID: 1, Deleted: true, Replies: []model.Comment{
+			{ID: 2, Body: "Comment 2", PostID: 1, ParentID: intPtr(1), Depth: 1, Replies: []model.Comment{
+				{ID: 3, Body: "Comment 3", PostID: 1, ParentID: intPtr(2), Depth: 2},
+			}},
+		}},
+	}
+	cs := mockcomment.NewMockService(c)
+	cs.EXPECT().GetTreeByPostID(gomock.Any(), 1, "", model.Page{Limit: model.DefaultPageLimit}).Return(tree, model.PageInfo{Total: 1}, nil)
+	rc := mockpost.NewMockReactionCounter(c)
+	counts := map[int][]model.ReactionCount{3: {{Kind: "heart", Count: 1}}}
+	rc.EXPECT().Count(gomock.Any(), model.ReactionComment, []int{2, 3}, "").Return(counts, nil)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/posts/1/comments?view=tree", nil)
+
+	ctx := echo.New().NewContext(r, w)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("1")
+
+	handlertest.Serve(ctx, NewHandler(cs, rc).GetAllByPostID)
+
+	var comments []model.Comment
+	json.NewDecoder(w.Body).Decode(&comments)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, comments[0].Reactions)
+	assert.Nil(t, comments[0].Replies[0].Reactions)
+	assert.Equal(t, []model.ReactionCount{{Kind: "heart", Count: 1}}, comments[0].Replies[0].Replies[0].Reactions)
+}
+
+func TestHandler_CreateByPostID(t *testing.T) {
 	testcases := []struct {
 		name       string
 		mock       func(*mockcomment.MockService, model.Comment)
@@ -195,41 +397,50 @@ func TestHandler_Create(t *testing.T) {
 		expCode    int
 	}{
 		{
-			name: "comment is created",
+			name: "post's comment is created",
 			mock: func(s *mockcomment.MockService, cm model.Comment) {
-				s.EXPECT().Create(cm).Return(cm, nil)
+				s.EXPECT().Create(gomock.Any(), cm).Return(cm, nil)
 			},
-			comment:    model.Comment{Email: "u@t.com", Body: "Comment 1", PostID: 1},
-			expComment: model.Comment{Email: "u@t.com", Body: "Comment 1", PostID: 1},
+			comment:    model.Comment{Email: "u@t.com", UserID: strPtr("1"), Body: "Comment 1", PostID: 1},
+			expComment: model.Comment{Email: "u@t.com", UserID: strPtr("1"), Body: "Comment 1", PostID: 1},
 			expCode:    http.StatusCreated,
 		},
 		{
-			name: "comment creating error",
+			name: "post is not found",
 			mock: func(s *mockcomment.MockService, cm model.Comment) {
-				s.EXPECT().Create(cm).Return(model.Comment{}, errors.New("internal error"))
+				s.EXPECT().Create(gomock.Any(), cm).Return(model.Comment{}, post.ErrNotFound)
 			},
-			comment: model.Comment{Email: "u@t.com", Body: "Comment 1", PostID: 1},
-			expCode: http.StatusInternalServerError,
+			comment: model.Comment{Email: "u@t.com", UserID: strPtr("1"), Body: "Comment 1", PostID: 1},
+			expCode: http.StatusNotFound,
+		},
+		{
+			name: "reply is nested too deep",
+			mock: func(s *mockcomment.MockService, cm model.Comment) {
+				s.EXPECT().Create(gomock.Any(), cm).Return(model.Comment{}, ErrMaxDepth)
+			},
+			comment: model.Comment{Email: "u@t.com", UserID: strPtr("1"), Body: "Comment 1", PostID: 1, ParentID: intPtr(2)},
+			expCode: http.StatusUnprocessableEntity,
 		},
 	}
 
 	for _, tc := range testcases {
 		c := gomock.NewController(t)
 		defer c.Finish()
-		ps := mockcomment.NewMockService(c)
-		as := mockauth.NewMockService(c)
-		tc.mock(ps, tc.comment)
+		cs := mockcomment.NewMockService(c)
+		tc.mock(cs, tc.comment)
 		w := httptest.NewRecorder()
 
 		b := &bytes.Buffer{}
-		json.NewEncoder(b).Encode(tc.comment)
-		r := httptest.NewRequest(http.MethodPost, "/comments", b)
-		r = r.WithContext(context.WithValue(r.Context(), uEmailKey, tc.comment.Email))
+		json.NewEncoder(b).Encode(model.Comment{Body: tc.comment.Body, ParentID: tc.comment.ParentID})
+		r := httptest.NewRequest(http.MethodPost, "/posts/1/comments", b)
+		r = r.WithContext(auth.WithPrincipal(r.Context(), auth.Principal{ID: "1", Email: tc.comment.Email}))
 		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
 		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
 
-		NewHandler(ps, as).Create(ctx)
+		handlertest.Serve(ctx, NewHandler(cs, nil).CreateByPostID)
 
 		var cm model.Comment
 		json.NewDecoder(w.Body).Decode(&cm)
@@ -245,12 +456,13 @@ func TestHandler_GetByID(t *testing.T) {
 		mock       func(*mockcomment.MockService, model.Comment)
 		comment    model.Comment
 		expComment model.Comment
+		header     string
 		expCode    int
 	}{
 		{
 			name: "comment is retrieved",
 			mock: func(s *mockcomment.MockService, cm model.Comment) {
-				s.EXPECT().GetByID(cm.ID).Return(cm, nil)
+				s.EXPECT().GetByID(gomock.Any(), cm.ID, "").Return(cm, nil)
 			},
 			comment:    model.Comment{ID: 1, Body: "Comment 1"},
 			expComment: model.Comment{ID: 1, Body: "Comment 1"},
@@ -259,7 +471,7 @@ func TestHandler_GetByID(t *testing.T) {
 		{
 			name: "comment is not found",
 			mock: func(s *mockcomment.MockService, cm model.Comment) {
-				s.EXPECT().GetByID(cm.ID).Return(model.Comment{}, ErrNotFound)
+				s.EXPECT().GetByID(gomock.Any(), cm.ID, "").Return(model.Comment{}, ErrNotFound)
 			},
 			comment: model.Comment{ID: 1, Body: "Comment 1"},
 			expCode: http.StatusNotFound,
@@ -267,27 +479,38 @@ func TestHandler_GetByID(t *testing.T) {
 		{
 			name: "internal error",
 			mock: func(s *mockcomment.MockService, cm model.Comment) {
-				s.EXPECT().GetByID(cm.ID).Return(model.Comment{}, errors.New("internal error"))
+				s.EXPECT().GetByID(gomock.Any(), cm.ID, "").Return(model.Comment{}, errors.New("internal error"))
 			},
 			comment: model.Comment{ID: 1, Body: "Comment 1"},
 			expCode: http.StatusInternalServerError,
 		},
+		{
+			name: "comment is not modified",
+			mock: func(s *mockcomment.MockService, cm model.Comment) {
+				s.EXPECT().GetByID(gomock.Any(), cm.ID, "").Return(cm, nil)
+			},
+			comment: model.Comment{ID: 1, Body: "Comment 1", Version: 2},
+			header:  `W/"2"`,
+			expCode: http.StatusNotModified,
+		},
 	}
 
 	for _, tc := range testcases {
 		c := gomock.NewController(t)
 		defer c.Finish()
 		ps := mockcomment.NewMockService(c)
-		as := mockauth.NewMockService(c)
 		tc.mock(ps, tc.comment)
 		w := httptest.NewRecorder()
 		r := httptest.NewRequest(http.MethodGet, "/comments/1", nil)
+		if tc.header != "" {
+			r.Header.Set("If-None-Match", tc.header)
+		}
 
 		ctx := echo.New().NewContext(r, w)
 		ctx.SetParamNames("id")
 		ctx.SetParamValues("1")
 
-		NewHandler(ps, as).GetByID(ctx)
+		handlertest.Serve(ctx, NewHandler(ps, nil).GetByID)
 
 		var cm model.Comment
 		json.NewDecoder(w.Body).Decode(&cm)
@@ -303,12 +526,13 @@ func TestHandler_Update(t *testing.T) {
 		mock       func(*mockcomment.MockService, model.Comment)
 		comment    model.Comment
 		expComment model.Comment
+		header     string
 		expCode    int
 	}{
 		{
 			name: "comment is updated",
 			mock: func(s *mockcomment.MockService, cm model.Comment) {
-				s.EXPECT().Update(cm).Return(cm, nil)
+				s.EXPECT().Update(gomock.Any(), cm).Return(cm, nil)
 			},
 			comment:    model.Comment{ID: 1, Body: "Comment 1"},
 			expComment: model.Comment{ID: 1, Body: "Comment 1"},
@@ -317,7 +541,7 @@ func TestHandler_Update(t *testing.T) {
 		{
 			name: "comment is not found",
 			mock: func(s *mockcomment.MockService, cm model.Comment) {
-				s.EXPECT().Update(cm).Return(model.Comment{}, ErrNotFound)
+				s.EXPECT().Update(gomock.Any(), cm).Return(model.Comment{}, ErrNotFound)
 			},
 			comment: model.Comment{ID: 1, Body: "Comment 1"},
 			expCode: http.StatusNotFound,
@@ -325,30 +549,53 @@ func TestHandler_Update(t *testing.T) {
 		{
 			name: "internal error",
 			mock: func(s *mockcomment.MockService, cm model.Comment) {
-				s.EXPECT().Update(cm).Return(model.Comment{}, errors.New("internal error"))
+				s.EXPECT().Update(gomock.Any(), cm).Return(model.Comment{}, errors.New("internal error"))
 			},
 			comment: model.Comment{ID: 1, Body: "Comment 1"},
 			expCode: http.StatusInternalServerError,
 		},
+		{
+			name: "comment version matches",
+			mock: func(s *mockcomment.MockService, cm model.Comment) {
+				cm.Version = 2
+				s.EXPECT().GetByID(gomock.Any(), cm.ID, "").Return(cm, nil)
+				s.EXPECT().Update(gomock.Any(), cm).Return(cm, nil)
+			},
+			comment:    model.Comment{ID: 1, Body: "Comment 1"},
+			header:     `"2"`,
+			expComment: model.Comment{ID: 1, Body: "Comment 1", Version: 2},
+			expCode:    http.StatusOK,
+		},
+		{
+			name: "comment version doesn't match",
+			mock: func(s *mockcomment.MockService, cm model.Comment) {
+				s.EXPECT().GetByID(gomock.Any(), cm.ID, "").Return(model.Comment{ID: 1, Version: 3}, nil)
+			},
+			comment: model.Comment{ID: 1, Body: "Comment 1"},
+			header:  `"2"`,
+			expCode: http.StatusPreconditionFailed,
+		},
 	}
 
 	for _, tc := range testcases {
 		c := gomock.NewController(t)
 		defer c.Finish()
 		ps := mockcomment.NewMockService(c)
-		as := mockauth.NewMockService(c)
 		tc.mock(ps, tc.comment)
 		w := httptest.NewRecorder()
 		b := &bytes.Buffer{}
 		json.NewEncoder(b).Encode(tc.comment)
 		r := httptest.NewRequest(http.MethodPatch, "/comment/1", b)
 		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if tc.header != "" {
+			r.Header.Set("If-Match", tc.header)
+		}
 
 		ctx := echo.New().NewContext(r, w)
 		ctx.SetParamNames("id")
 		ctx.SetParamValues("1")
 
-		NewHandler(ps, as).Update(ctx)
+		handlertest.Serve(ctx, NewHandler(ps, nil).Update)
 
 		var cm model.Comment
 		json.NewDecoder(w.Body).Decode(&cm)
@@ -358,6 +605,78 @@ func TestHandler_Update(t *testing.T) {
 	}
 }
 
+func TestHandler_Update_Patch(t *testing.T) {
+	cur := model.Comment{ID: 1, Name: "Name", Email: "u@t.com", Body: "Body.", PostID: 1, Version: 2}
+	testcases := []struct {
+		name        string
+		mock        func(*mockcomment.MockService)
+		contentType string
+		patch       string
+		expComment  model.Comment
+		expCode     int
+	}{
+		{
+			name: "merge patch keeps missing fields",
+			mock: func(s *mockcomment.MockService) {
+				cm := cur
+				cm.Body = "New body."
+				s.EXPECT().GetByID(gomock.Any(), 1, "").Return(cur, nil)
+				s.EXPECT().Update(gomock.Any(), cm).Return(cm, nil)
+			},
+			contentType: patch.MIMEMergePatch,
+			patch:       `{"body":"New body."}`,
+			expComment:  model.Comment{ID: 1, Name: "Name", Email: "u@t.com", Body: "New body.", PostID: 1, Version: 2},
+			expCode:     http.StatusOK,
+		},
+		{
+			name: "json patch is applied",
+			mock: func(s *mockcomment.MockService) {
+				cm := cur
+				cm.Name = "New name"
+				s.EXPECT().GetByID(gomock.Any(), 1, "").Return(cur, nil)
+				s.EXPECT().Update(gomock.Any(), cm).Return(cm, nil)
+			},
+			contentType: patch.MIMEJSONPatch,
+			patch:       `[{"op":"test","path":"/name","value":"Name"},{"op":"replace","path":"/name","value":"New name"}]`,
+			expComment:  model.Comment{ID: 1, Name: "New name", Email: "u@t.com", Body: "Body.", PostID: 1, Version: 2},
+			expCode:     http.StatusOK,
+		},
+		{
+			name: "patched comment doesn't fit",
+			mock: func(s *mockcomment.MockService) {
+				s.EXPECT().GetByID(gomock.Any(), 1, "").Return(cur, nil)
+			},
+			contentType: patch.MIMEMergePatch,
+			patch:       `{"body":1}`,
+			expCode:     http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		cs := mockcomment.NewMockService(c)
+		tc.mock(cs)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPatch, "/comments/1", strings.NewReader(tc.patch))
+		r.Header.Set(echo.HeaderContentType, tc.contentType)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, NewHandler(cs, nil).Update)
+
+		var cm model.Comment
+		json.NewDecoder(w.Body).Decode(&cm)
+
+		assert.Equal(t, tc.expCode, w.Code, tc.name)
+		if tc.expCode == http.StatusOK {
+			assert.Equal(t, tc.expComment, cm, tc.name)
+		}
+	}
+}
+
 func TestHandler_DeleteyByID(t *testing.T) {
 	testcases := []struct {
 		name    string
@@ -368,7 +687,7 @@ func TestHandler_DeleteyByID(t *testing.T) {
 		{
 			name: "comment is deleted",
 			mock: func(s *mockcomment.MockService, cm model.Comment) {
-				s.EXPECT().DeleteByID(cm.ID).Return(nil)
+				s.EXPECT().DeleteByID(gomock.Any(), cm.ID).Return(nil)
 			},
 			comment: model.Comment{ID: 1, Body: "Comment 1"},
 			expCode: http.StatusNoContent,
@@ -376,7 +695,7 @@ func TestHandler_DeleteyByID(t *testing.T) {
 		{
 			name: "comment is not found",
 			mock: func(s *mockcomment.MockService, cm model.Comment) {
-				s.EXPECT().DeleteByID(cm.ID).Return(ErrNotFound)
+				s.EXPECT().DeleteByID(gomock.Any(), cm.ID).Return(ErrNotFound)
 			},
 			comment: model.Comment{ID: 1, Body: "Comment 1"},
 			expCode: http.StatusNotFound,
@@ -384,7 +703,7 @@ func TestHandler_DeleteyByID(t *testing.T) {
 		{
 			name: "internal error",
 			mock: func(s *mockcomment.MockService, cm model.Comment) {
-				s.EXPECT().DeleteByID(cm.ID).Return(errors.New("internal error"))
+				s.EXPECT().DeleteByID(gomock.Any(), cm.ID).Return(errors.New("internal error"))
 			},
 			comment: model.Comment{ID: 1, Body: "Comment 1"},
 			expCode: http.StatusInternalServerError,
@@ -395,7 +714,6 @@ func TestHandler_DeleteyByID(t *testing.T) {
 		c := gomock.NewController(t)
 		defer c.Finish()
 		ps := mockcomment.NewMockService(c)
-		as := mockauth.NewMockService(c)
 		tc.mock(ps, tc.comment)
 		w := httptest.NewRecorder()
 		r := httptest.NewRequest(http.MethodDelete, "/comment/1", nil)
@@ -404,8 +722,65 @@ func TestHandler_DeleteyByID(t *testing.T) {
 		ctx.SetParamNames("id")
 		ctx.SetParamValues("1")
 
-		NewHandler(ps, as).DeleteByID(ctx)
+		handlertest.Serve(ctx, NewHandler(ps, nil).DeleteByID)
+
+		assert.Equal(t, tc.expCode, w.Code)
+	}
+}
+
+func TestHandler_Restore(t *testing.T) {
+	testcases := []struct {
+		name       string
+		mock       func(*mockcomment.MockService, model.Comment)
+		comment    model.Comment
+		expComment model.Comment
+		expCode    int
+	}{
+		{
+			name: "comment is restored",
+			mock: func(s *mockcomment.MockService, cm model.Comment) {
+				s.EXPECT().Restore(gomock.Any(), cm.ID).Return(cm, nil)
+			},
+			comment:    model.Comment{ID: 1, Body: "Comment1", PostID: 1},
+			expComment: model.Comment{ID: 1, Body: "Comment1", PostID: 1},
+			expCode:    http.StatusOK,
+		},
+		{
+			name: "comment is not found",
+			mock: func(s *mockcomment.MockService, cm model.Comment) {
+				s.EXPECT().Restore(gomock.Any(), cm.ID).Return(model.Comment{}, ErrNotFound)
+			},
+			comment: model.Comment{ID: 1, Body: "Comment1", PostID: 1},
+			expCode: http.StatusNotFound,
+		},
+		{
+			name: "post is deleted",
+			mock: func(s *mockcomment.MockService, cm model.Comment) {
+				s.EXPECT().Restore(gomock.Any(), cm.ID).Return(model.Comment{}, post.ErrNotFound)
+			},
+			comment: model.Comment{ID: 1, Body: "Comment1", PostID: 1},
+			expCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		cs := mockcomment.NewMockService(c)
+		tc.mock(cs, tc.comment)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/comments/1/restore", nil)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, NewHandler(cs, nil).Restore)
+
+		var cm model.Comment
+		json.NewDecoder(w.Body).Decode(&cm)
 
 		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expComment, cm)
 	}
 }
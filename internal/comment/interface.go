@@ -1,24 +1,41 @@
 // Package comment provides all comment domain related logic.
 package comment
 
-import "github.com/imarrche/nix-ed/internal/model"
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
 
 //go:generate mockgen -source=interface.go -destination=mock/mock.go
 
 // Repo is the interface all comment repositories must implement.
 type Repo interface {
-	GetAll() ([]model.Comment, error)
-	Create(model.Comment) (model.Comment, error)
-	GetByID(int) (model.Comment, error)
-	Update(model.Comment) (model.Comment, error)
-	DeleteByID(int) error
+	GetAll(context.Context, model.CommentFilter, model.Page) ([]model.Comment, model.PageInfo, error)
+	GetAllByPostID(context.Context, int, model.Page) ([]model.Comment, model.PageInfo, error)
+	GetTreeByPostID(context.Context, int, model.Page) ([]model.Comment, model.PageInfo, error)
+	Create(context.Context, model.Comment) (model.Comment, error)
+	GetByID(context.Context, int) (model.Comment, error)
+	Update(context.Context, model.Comment) (model.Comment, error)
+	DeleteByID(context.Context, int) error
+	GetTrash(context.Context, string, model.Page) ([]model.Comment, model.PageInfo, error)
+	GetDeletedByID(context.Context, int) (model.Comment, error)
+	Restore(context.Context, int) (model.Comment, error)
+	Purge(context.Context, time.Time) (int64, error)
 }
 
 // Service is the interface all comment services must implement.
 type Service interface {
-	GetAll() ([]model.Comment, error)
-	Create(model.Comment) (model.Comment, error)
-	GetByID(int) (model.Comment, error)
-	Update(model.Comment) (model.Comment, error)
-	DeleteByID(int) error
+	GetAll(context.Context, model.CommentFilter, model.Page) ([]model.Comment, model.PageInfo, error)
+	GetAllByPostID(context.Context, int, string, model.Page) ([]model.Comment, model.PageInfo, error)
+	GetTreeByPostID(context.Context, int, string, model.Page) ([]model.Comment, model.PageInfo, error)
+	Create(context.Context, model.Comment) (model.Comment, error)
+	GetByID(context.Context, int, string) (model.Comment, error)
+	Update(context.Context, model.Comment) (model.Comment, error)
+	DeleteByID(context.Context, int) error
+	GetTrash(context.Context, string, model.Page) ([]model.Comment, model.PageInfo, error)
+	GetDeletedByID(context.Context, int) (model.Comment, error)
+	Restore(context.Context, int) (model.Comment, error)
+	Purge(context.Context, time.Time) (int64, error)
 }
@@ -5,9 +5,11 @@
 package mock_comment
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/imarrche/nix-ed/internal/model"
 	reflect "reflect"
+	time "time"
 )
 
 // MockRepo is a mock of Repo interface
@@ -34,77 +36,171 @@ func (m *MockRepo) EXPECT() *MockRepoMockRecorder {
 }
 
 // GetAll mocks base method
-func (m *MockRepo) GetAll() ([]model.Comment, error) {
+func (m *MockRepo) GetAll(arg0 context.Context, arg1 model.CommentFilter, arg2 model.Page) ([]model.Comment, model.PageInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]model.Comment)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(model.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetAll indicates an expected call of GetAll
-func (mr *MockRepoMockRecorder) GetAll() *gomock.Call {
+func (mr *MockRepoMockRecorder) GetAll(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockRepo)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockRepo)(nil).GetAll), arg0, arg1, arg2)
+}
+
+// GetAllByPostID mocks base method
+func (m *MockRepo) GetAllByPostID(arg0 context.Context, arg1 int, arg2 model.Page) ([]model.Comment, model.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByPostID", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.Comment)
+	ret1, _ := ret[1].(model.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllByPostID indicates an expected call of GetAllByPostID
+func (mr *MockRepoMockRecorder) GetAllByPostID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByPostID", reflect.TypeOf((*MockRepo)(nil).GetAllByPostID), arg0, arg1, arg2)
+}
+
+// GetTreeByPostID mocks base method
+func (m *MockRepo) GetTreeByPostID(arg0 context.Context, arg1 int, arg2 model.Page) ([]model.Comment, model.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTreeByPostID", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.Comment)
+	ret1, _ := ret[1].(model.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTreeByPostID indicates an expected call of GetTreeByPostID
+func (mr *MockRepoMockRecorder) GetTreeByPostID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreeByPostID", reflect.TypeOf((*MockRepo)(nil).GetTreeByPostID), arg0, arg1, arg2)
 }
 
 // Create mocks base method
-func (m *MockRepo) Create(arg0 model.Comment) (model.Comment, error) {
+func (m *MockRepo) Create(arg0 context.Context, arg1 model.Comment) (model.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create
-func (mr *MockRepoMockRecorder) Create(arg0 interface{}) *gomock.Call {
+func (mr *MockRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepo)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepo)(nil).Create), arg0, arg1)
 }
 
 // GetByID mocks base method
-func (m *MockRepo) GetByID(arg0 int) (model.Comment, error) {
+func (m *MockRepo) GetByID(arg0 context.Context, arg1 int) (model.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", arg0)
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
 	ret0, _ := ret[0].(model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID
-func (mr *MockRepoMockRecorder) GetByID(arg0 interface{}) *gomock.Call {
+func (mr *MockRepoMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRepo)(nil).GetByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRepo)(nil).GetByID), arg0, arg1)
 }
 
 // Update mocks base method
-func (m *MockRepo) Update(arg0 model.Comment) (model.Comment, error) {
+func (m *MockRepo) Update(arg0 context.Context, arg1 model.Comment) (model.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
 	ret0, _ := ret[0].(model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update
-func (mr *MockRepoMockRecorder) Update(arg0 interface{}) *gomock.Call {
+func (mr *MockRepoMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepo)(nil).Update), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepo)(nil).Update), arg0, arg1)
 }
 
 // DeleteByID mocks base method
-func (m *MockRepo) DeleteByID(arg0 int) error {
+func (m *MockRepo) DeleteByID(arg0 context.Context, arg1 int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByID", arg0)
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteByID indicates an expected call of DeleteByID
-func (mr *MockRepoMockRecorder) DeleteByID(arg0 interface{}) *gomock.Call {
+func (mr *MockRepoMockRecorder) DeleteByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockRepo)(nil).DeleteByID), arg0, arg1)
+}
+
+// GetTrash mocks base method
+func (m *MockRepo) GetTrash(arg0 context.Context, arg1 string, arg2 model.Page) ([]model.Comment, model.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.Comment)
+	ret1, _ := ret[1].(model.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTrash indicates an expected call of GetTrash
+func (mr *MockRepoMockRecorder) GetTrash(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockRepo)(nil).GetTrash), arg0, arg1, arg2)
+}
+
+// GetDeletedByID mocks base method
+func (m *MockRepo) GetDeletedByID(arg0 context.Context, arg1 int) (model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedByID", arg0, arg1)
+	ret0, _ := ret[0].(model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedByID indicates an expected call of GetDeletedByID
+func (mr *MockRepoMockRecorder) GetDeletedByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedByID", reflect.TypeOf((*MockRepo)(nil).GetDeletedByID), arg0, arg1)
+}
+
+// Restore mocks base method
+func (m *MockRepo) Restore(arg0 context.Context, arg1 int) (model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore
+func (mr *MockRepoMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRepo)(nil).Restore), arg0, arg1)
+}
+
+// Purge mocks base method
+func (m *MockRepo) Purge(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge
+func (mr *MockRepoMockRecorder) Purge(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockRepo)(nil).DeleteByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockRepo)(nil).Purge), arg0, arg1)
 }
 
 // MockService is a mock of Service interface
@@ -131,75 +227,169 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 }
 
 // GetAll mocks base method
-func (m *MockService) GetAll() ([]model.Comment, error) {
+func (m *MockService) GetAll(arg0 context.Context, arg1 model.CommentFilter, arg2 model.Page) ([]model.Comment, model.PageInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]model.Comment)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(model.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetAll indicates an expected call of GetAll
-func (mr *MockServiceMockRecorder) GetAll() *gomock.Call {
+func (mr *MockServiceMockRecorder) GetAll(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockService)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockService)(nil).GetAll), arg0, arg1, arg2)
+}
+
+// GetAllByPostID mocks base method
+func (m *MockService) GetAllByPostID(arg0 context.Context, arg1 int, arg2 string, arg3 model.Page) ([]model.Comment, model.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByPostID", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model.Comment)
+	ret1, _ := ret[1].(model.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllByPostID indicates an expected call of GetAllByPostID
+func (mr *MockServiceMockRecorder) GetAllByPostID(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByPostID", reflect.TypeOf((*MockService)(nil).GetAllByPostID), arg0, arg1, arg2, arg3)
+}
+
+// GetTreeByPostID mocks base method
+func (m *MockService) GetTreeByPostID(arg0 context.Context, arg1 int, arg2 string, arg3 model.Page) ([]model.Comment, model.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTreeByPostID", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model.Comment)
+	ret1, _ := ret[1].(model.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTreeByPostID indicates an expected call of GetTreeByPostID
+func (mr *MockServiceMockRecorder) GetTreeByPostID(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreeByPostID", reflect.TypeOf((*MockService)(nil).GetTreeByPostID), arg0, arg1, arg2, arg3)
 }
 
 // Create mocks base method
-func (m *MockService) Create(arg0 model.Comment) (model.Comment, error) {
+func (m *MockService) Create(arg0 context.Context, arg1 model.Comment) (model.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create
-func (mr *MockServiceMockRecorder) Create(arg0 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), arg0, arg1)
 }
 
 // GetByID mocks base method
-func (m *MockService) GetByID(arg0 int) (model.Comment, error) {
+func (m *MockService) GetByID(arg0 context.Context, arg1 int, arg2 string) (model.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", arg0)
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1, arg2)
 	ret0, _ := ret[0].(model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID
-func (mr *MockServiceMockRecorder) GetByID(arg0 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetByID(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockService)(nil).GetByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockService)(nil).GetByID), arg0, arg1, arg2)
 }
 
 // Update mocks base method
-func (m *MockService) Update(arg0 model.Comment) (model.Comment, error) {
+func (m *MockService) Update(arg0 context.Context, arg1 model.Comment) (model.Comment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
 	ret0, _ := ret[0].(model.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update
-func (mr *MockServiceMockRecorder) Update(arg0 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), arg0, arg1)
 }
 
 // DeleteByID mocks base method
-func (m *MockService) DeleteByID(arg0 int) error {
+func (m *MockService) DeleteByID(arg0 context.Context, arg1 int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByID", arg0)
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteByID indicates an expected call of DeleteByID
-func (mr *MockServiceMockRecorder) DeleteByID(arg0 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) DeleteByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockService)(nil).DeleteByID), arg0, arg1)
+}
+
+// GetTrash mocks base method
+func (m *MockService) GetTrash(arg0 context.Context, arg1 string, arg2 model.Page) ([]model.Comment, model.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.Comment)
+	ret1, _ := ret[1].(model.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTrash indicates an expected call of GetTrash
+func (mr *MockServiceMockRecorder) GetTrash(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockService)(nil).GetTrash), arg0, arg1, arg2)
+}
+
+// GetDeletedByID mocks base method
+func (m *MockService) GetDeletedByID(arg0 context.Context, arg1 int) (model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedByID", arg0, arg1)
+	ret0, _ := ret[0].(model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedByID indicates an expected call of GetDeletedByID
+func (mr *MockServiceMockRecorder) GetDeletedByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedByID", reflect.TypeOf((*MockService)(nil).GetDeletedByID), arg0, arg1)
+}
+
+// Restore mocks base method
+func (m *MockService) Restore(arg0 context.Context, arg1 int) (model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore
+func (mr *MockServiceMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockService)(nil).Restore), arg0, arg1)
+}
+
+// Purge mocks base method
+func (m *MockService) Purge(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge
+func (mr *MockServiceMockRecorder) Purge(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockService)(nil).DeleteByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockService)(nil).Purge), arg0, arg1)
 }
@@ -1,9 +1,17 @@
 package comment
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"gorm.io/gorm"
 
 	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/store"
 )
 
 // repo is comment repository implementation.
@@ -16,43 +24,316 @@ func NewRepo(db *gorm.DB) Repo {
 	return &repo{db}
 }
 
-// GetAll gets and returns all comments.
-func (r *repo) GetAll() (cs []model.Comment, err error) {
-	r.db.Find(&cs)
+// sortColumns maps sort parameters to comment table columns.
+var sortColumns = map[string]string{
+	"":        "id",
+	"id":      "id",
+	"name":    "name",
+	"created": "created_at",
+	"updated": "updated_at",
+	"thread":  "path",
+}
+
+// GetAll gets and returns the page of comments matching the filter.
+func (r *repo) GetAll(ctx context.Context, f model.CommentFilter, pg model.Page) ([]model.Comment, model.PageInfo, error) {
+	return r.page(ctx, pg, func(db *gorm.DB) *gorm.DB {
+		if !f.UpdatedSince.IsZero() {
+			db = db.Where("updated_at >= ?", f.UpdatedSince)
+		}
+		if f.IncludeHidden {
+			return db.Where("post_id IN (?)", r.db.Model(&model.Post{}).Select("id"))
+		}
+
+		return db.Where("post_id IN (?)", r.visiblePosts(f.Viewer))
+	})
+}
+
+// visiblePosts returns the subquery of IDs of posts visible to the user with
+// specific ID, drafts and scheduled posts are visible only to their authors.
+func (r *repo) visiblePosts(userID string) *gorm.DB {
+	q := r.db.Model(&model.Post{}).Select("id")
+	if userID == "" {
+		return q.Where("status IN ?", model.PublicPostStatuses)
+	}
+
+	return q.Where("status IN ? OR user_id = ?", model.PublicPostStatuses, userID)
+}
+
+// GetAllByPostID gets and returns the page of comments of the post with
+// specific ID. Deleted comments with replies are kept as placeholders.
+func (r *repo) GetAllByPostID(ctx context.Context, postID int, pg model.Page) ([]model.Comment, model.PageInfo, error) {
+	thread, err := r.thread(ctx, postID)
+	if err != nil {
+		return nil, model.PageInfo{}, store.Wrap(err)
+	}
+
+	return r.page(ctx, pg, thread)
+}
+
+// GetTreeByPostID gets and returns the page of top-level comments of the post
+// with specific ID with all their replies. Deleted comments with replies are
+// kept as placeholders.
+func (r *repo) GetTreeByPostID(ctx context.Context, postID int, pg model.Page) ([]model.Comment, model.PageInfo, error) {
+	thread, err := r.thread(ctx, postID)
+	if err != nil {
+		return nil, model.PageInfo{}, store.Wrap(err)
+	}
+	roots, pi, err := r.page(ctx, pg, thread, func(db *gorm.DB) *gorm.DB {
+		return db.Where("parent_id IS NULL")
+	})
+	if err != nil || len(roots) == 0 {
+		return roots, pi, store.Wrap(err)
+	}
+
+	paths := make([]string, len(roots))
+	for i, c := range roots {
+		paths[i] = c.Path
+	}
+	var replies []model.Comment
+	err = r.db.WithContext(ctx).Scopes(thread).Where("parent_id IS NOT NULL AND SUBSTR(path, 1, ?) IN ?", pathSegmentLen, paths).
+		Order("path").Find(&replies).Error
+	if err != nil {
+		return nil, pi, store.Wrap(err)
+	}
+
+	children := map[int][]model.Comment{}
+	for _, c := range replies {
+		children[*c.ParentID] = append(children[*c.ParentID], placeholder(c))
+	}
+	for i := range roots {
+		attachReplies(&roots[i], children)
+	}
+
+	return roots, pi, nil
+}
+
+// attachReplies sets replies of the comment and all its replies.
+func attachReplies(c *model.Comment, children map[int][]model.Comment) {
+	c.Replies = children[c.ID]
+	for i := range c.Replies {
+		attachReplies(&c.Replies[i], children)
+	}
+}
+
+// thread returns a GORM scope that selects comments of the post with specific
+// ID together with deleted comments that have replies.
+func (r *repo) thread(ctx context.Context, postID int) (func(*gorm.DB) *gorm.DB, error) {
+	var paths []string
+	err := r.db.WithContext(ctx).Model(&model.Comment{}).Where("post_id = ? AND parent_id IS NOT NULL", postID).
+		Pluck("path", &paths).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var ancestors []int
+	seen := map[int]bool{}
+	for _, p := range paths {
+		ids := strings.Split(p, "/")
+		for _, s := range ids[:len(ids)-1] {
+			id, _ := strconv.Atoi(s)
+			if !seen[id] {
+				seen[id] = true
+				ancestors = append(ancestors, id)
+			}
+		}
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Unscoped().Where("post_id = ?", postID)
+		if len(ancestors) == 0 {
+			return db.Where("deleted_at IS NULL")
+		}
+
+		return db.Where("deleted_at IS NULL OR id IN ?", ancestors)
+	}, nil
+}
+
+// placeholder returns the deleted comment without its content.
+func placeholder(c model.Comment) model.Comment {
+	if !c.DeletedAt.Valid {
+		return c
+	}
+
+	return model.Comment{
+		ID:        c.ID,
+		Body:      model.DeletedCommentBody,
+		PostID:    c.PostID,
+		ParentID:  c.ParentID,
+		Depth:     c.Depth,
+		Path:      c.Path,
+		Deleted:   true,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+// GetTrash gets and returns the page of user's deleted comments.
+func (r *repo) GetTrash(ctx context.Context, userID string, pg model.Page) ([]model.Comment, model.PageInfo, error) {
+	return r.page(ctx, pg, func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", userID)
+	})
+}
+
+// page gets and returns the page of comments matching all scopes.
+func (r *repo) page(ctx context.Context, pg model.Page, scopes ...func(*gorm.DB) *gorm.DB) (cs []model.Comment, pi model.PageInfo, err error) {
+	if err := r.db.WithContext(ctx).Model(&model.Comment{}).Scopes(scopes...).Count(&pi.Total).Error; err != nil {
+		return nil, pi, store.Wrap(err)
+	}
+	scopes = append(scopes, pg.Paginate(sortColumns[pg.Sort]))
+	if err := r.db.WithContext(ctx).Scopes(scopes...).Find(&cs).Error; err != nil {
+		return nil, pi, store.Wrap(err)
+	}
+
+	if len(cs) > pg.Limit {
+		cs = cs[:pg.Limit]
+		last := cs[len(cs)-1]
+		pi.NextCursor = pg.NextCursor(sortValue(last, pg.Sort), last.ID)
+	}
+	for i := range cs {
+		cs[i] = placeholder(cs[i])
+	}
 
-	return
+	return cs, pi, nil
 }
 
-// Create creates a comment and returns it.
-func (r *repo) Create(c model.Comment) (model.Comment, error) {
-	res := r.db.Create(&c)
+// sortValue returns the value comment is sorted by.
+func sortValue(c model.Comment, sort string) interface{} {
+	switch sort {
+	case "name":
+		return c.Name
+	case "created":
+		return c.CreatedAt
+	case "updated":
+		return c.UpdatedAt
+	case "thread":
+		return c.Path
+	}
 
-	return c, res.Error
+	return c.ID
+}
+
+// pathSegmentLen is the length of a comment ID in comment paths. IDs are
+// padded with zeros, so paths are sorted in thread order.
+const pathSegmentLen = 10
+
+// Create creates a comment and returns it. Replies are created at the end of
+// their parent's thread.
+func (r *repo) Create(ctx context.Context, c model.Comment) (model.Comment, error) {
+	c.Version = 1
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&c).Error; err != nil {
+			return err
+		}
+
+		c.Path = fmt.Sprintf("%0*d", pathSegmentLen, c.ID)
+		if c.ParentID != nil {
+			var parent model.Comment
+			if err := tx.Unscoped().Select("id", "path").First(&parent, *c.ParentID).Error; err != nil {
+				return err
+			}
+			c.Path = parent.Path + "/" + c.Path
+		}
+
+		return tx.Model(&model.Comment{}).Where("id = ?", c.ID).UpdateColumn("path", c.Path).Error
+	})
+
+	return c, store.Wrap(err)
 }
 
 // GetByID gets and returns the comment with specifid ID.
-func (r *repo) GetByID(id int) (c model.Comment, err error) {
-	r.db.First(&c, id)
-	if c.ID != id {
+func (r *repo) GetByID(ctx context.Context, id int) (c model.Comment, err error) {
+	err = r.db.WithContext(ctx).First(&c, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return c, ErrNotFound
 	}
 
+	return c, store.Wrap(err)
+}
+
+// Update updates the comment if its version wasn't changed since it was read
+// and returns it.
+func (r *repo) Update(ctx context.Context, c model.Comment) (model.Comment, error) {
+	c.UpdatedAt = r.db.NowFunc()
+	res := r.db.WithContext(ctx).Model(&model.Comment{}).Where("id = ? AND version = ?", c.ID, c.Version).
+		Updates(map[string]interface{}{
+			"name":       c.Name,
+			"body":       c.Body,
+			"updated_at": c.UpdatedAt,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if res.Error != nil {
+		return model.Comment{}, store.Wrap(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return model.Comment{}, ErrVersionMismatch
+	}
+	c.Version++
+
 	return c, nil
 }
 
-// Update updates the comment and returns it.
-func (r *repo) Update(c model.Comment) (model.Comment, error) {
-	r.db.Save(&c)
+// DeleteByID moves the comment with specific ID to trash.
+func (r *repo) DeleteByID(ctx context.Context, id int) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return store.Wrap(err)
+	}
+
+	return store.Wrap(r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.Comment{}).Error)
+}
+
+// GetDeletedByID gets and returns the deleted comment with specific ID.
+func (r *repo) GetDeletedByID(ctx context.Context, id int) (c model.Comment, err error) {
+	err = r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(&c, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c, ErrNotFound
+	}
+
+	return c, store.Wrap(err)
+}
+
+// Restore restores the deleted comment with specific ID and returns it.
+func (r *repo) Restore(ctx context.Context, id int) (model.Comment, error) {
+	c, err := r.GetDeletedByID(ctx, id)
+	if err != nil {
+		return model.Comment{}, store.Wrap(err)
+	}
+
+	err = r.db.WithContext(ctx).Unscoped().Model(&model.Comment{}).Where("id = ?", id).UpdateColumn("deleted_at", nil).Error
+	if err != nil {
+		return model.Comment{}, store.Wrap(err)
+	}
+	c.DeletedAt = gorm.DeletedAt{}
 
 	return c, nil
 }
 
-// DeleteByID deletes the comment with specific ID.
-func (r *repo) DeleteByID(id int) error {
-	if _, err := r.GetByID(id); err != nil {
+// Purge permanently deletes comments moved to trash before specific time and
+// returns their number. Comments with replies are kept as placeholders until
+// their replies are purged.
+func (r *repo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at < ?", before).
+		Where("id NOT IN (SELECT parent_id FROM (SELECT parent_id FROM comments WHERE parent_id IS NOT NULL) AS replies)").
+		Delete(&model.Comment{})
+
+	return res.RowsAffected, store.Wrap(res.Error)
+}
+
+// BackfillPaths gives thread paths to comments created before replies were
+// added, they're all top-level comments.
+func BackfillPaths(db *gorm.DB) error {
+	var ids []int
+	if err := db.Unscoped().Model(&model.Comment{}).Where("path = ''").Pluck("id", &ids).Error; err != nil {
 		return err
 	}
-	r.db.Where("id = ?", id).Delete(model.Comment{})
+
+	for _, id := range ids {
+		err := db.Unscoped().Model(&model.Comment{}).Where("id = ?", id).
+			UpdateColumn("path", fmt.Sprintf("%0*d", pathSegmentLen, id)).Error
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -0,0 +1,210 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/store"
+	"github.com/imarrche/nix-ed/internal/testdb"
+)
+
+// newTestDB returns a test database with the post comments are created on.
+func newTestDB(t *testing.T) *gorm.DB {
+	db := testdb.New(t, &model.Tag{}, &model.Category{}, &model.Post{}, &model.Comment{})
+	if err := db.Create(&model.Post{ID: 1, Title: "Title", Body: "Body.", UserID: "1"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+// seedThread creates the thread of comments:
+//
+//	1
+//	├── 2
+//	│   └── 4
+//	└── 3
+//	5
+func seedThread(t *testing.T, r Repo) {
+	parents := []int{0, 1, 1, 2, 0}
+	for i, parent := range parents {
+		c := model.Comment{Name: "Name", Email: "u@t.com", Body: "Body.", PostID: 1}
+		if parent != 0 {
+			p := parent
+			c.ParentID = &p
+			c.Depth = 1
+			if parent == 2 {
+				c.Depth = 2
+			}
+		}
+		if c, err := r.Create(context.Background(), c); err != nil || c.ID != i+1 {
+			t.Fatal(err)
+		}
+	}
+}
+
+// ids returns IDs of comments.
+func ids(cs []model.Comment) []int {
+	ids := make([]int, len(cs))
+	for i, c := range cs {
+		ids[i] = c.ID
+	}
+
+	return ids
+}
+
+func TestCommentRepo_GetAll_Visibility(t *testing.T) {
+	db := newTestDB(t)
+	r := NewRepo(db)
+	if err := db.Create(&model.Post{ID: 2, Title: "Draft", Body: "Body.", Status: model.PostDraft, UserID: "2"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, postID := range []int{1, 2} {
+		if _, err := r.Create(context.Background(), model.Comment{Name: "Name", Body: "Body.", PostID: postID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cs, pi, err := r.GetAll(context.Background(), model.CommentFilter{}, model.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), pi.Total)
+	assert.Equal(t, []int{1}, ids(cs))
+
+	cs, _, err = r.GetAll(context.Background(), model.CommentFilter{Viewer: "3"}, model.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, ids(cs))
+
+	cs, pi, err = r.GetAll(context.Background(), model.CommentFilter{Viewer: "2"}, model.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), pi.Total)
+	assert.Equal(t, []int{1, 2}, ids(cs))
+
+	cs, _, err = r.GetAll(context.Background(), model.CommentFilter{IncludeHidden: true}, model.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ids(cs))
+}
+
+func TestCommentRepo_GetAllByPostID_Thread(t *testing.T) {
+	db := newTestDB(t)
+	r := NewRepo(db)
+	seedThread(t, r)
+
+	pg := model.Page{Limit: 3, Sort: "thread"}
+	cs, pi, err := r.GetAllByPostID(context.Background(), 1, pg)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), pi.Total)
+	assert.Equal(t, []int{1, 2, 4}, ids(cs))
+
+	pg.Cursor = pi.NextCursor
+	cs, _, err = r.GetAllByPostID(context.Background(), 1, pg)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 5}, ids(cs))
+}
+
+func TestCommentRepo_GetTreeByPostID(t *testing.T) {
+	db := newTestDB(t)
+	r := NewRepo(db)
+	seedThread(t, r)
+
+	cs, pi, err := r.GetTreeByPostID(context.Background(), 1, model.Page{Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), pi.Total)
+	assert.NotEmpty(t, pi.NextCursor)
+	assert.Equal(t, []int{1}, ids(cs))
+	assert.Equal(t, []int{2, 3}, ids(cs[0].Replies))
+	assert.Equal(t, []int{4}, ids(cs[0].Replies[0].Replies))
+	assert.Empty(t, cs[0].Replies[1].Replies)
+}
+
+func TestCommentRepo_DeletedParent(t *testing.T) {
+	db := newTestDB(t)
+	r := NewRepo(db)
+	seedThread(t, r)
+
+	// Comments 1 and 2 have replies, comment 5 doesn't.
+	for _, id := range []int{1, 2, 5} {
+		assert.NoError(t, r.DeleteByID(context.Background(), id))
+	}
+
+	cs, pi, err := r.GetAllByPostID(context.Background(), 1, model.Page{Limit: 10, Sort: "thread"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), pi.Total)
+	assert.Equal(t, []int{1, 2, 4, 3}, ids(cs))
+	assert.True(t, cs[0].Deleted)
+	assert.Equal(t, model.DeletedCommentBody, cs[0].Body)
+	assert.Empty(t, cs[0].Email)
+	assert.False(t, cs[2].Deleted)
+
+	cs, _, err = r.GetTreeByPostID(context.Background(), 1, model.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, ids(cs))
+	assert.True(t, cs[0].Replies[0].Deleted)
+	assert.Equal(t, []int{4}, ids(cs[0].Replies[0].Replies))
+
+	// Placeholders are purged only after their replies.
+	n, err := r.Purge(context.Background(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	assert.NoError(t, r.DeleteByID(context.Background(), 4))
+	for _, exp := range []int64{1, 1, 0} {
+		n, err = r.Purge(context.Background(), time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, exp, n)
+	}
+
+	var left []int
+	db.Unscoped().Model(&model.Comment{}).Order("id").Pluck("id", &left)
+	assert.Equal(t, []int{1, 3}, left)
+}
+
+func TestCommentRepo_GetTrash(t *testing.T) {
+	r := NewRepo(newTestDB(t))
+	owners := []*string{strPtr("1"), strPtr("2"), nil}
+	for _, uID := range owners {
+		c, err := r.Create(context.Background(), model.Comment{Name: "Name", Email: "u@t.com", Body: "Body.", PostID: 1, UserID: uID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.NoError(t, r.DeleteByID(context.Background(), c.ID))
+	}
+
+	// Trash is listed by user ID, comments with the same email aren't.
+	cs, pi, err := r.GetTrash(context.Background(), "1", model.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), pi.Total)
+	assert.Equal(t, []int{1}, ids(cs))
+}
+
+func TestBackfillPaths(t *testing.T) {
+	db := newTestDB(t)
+	c := model.Comment{Name: "Name", Email: "u@t.com", Body: "Body.", PostID: 1}
+	assert.NoError(t, db.Create(&c).Error)
+
+	assert.NoError(t, BackfillPaths(db))
+
+	assert.NoError(t, db.First(&c, c.ID).Error)
+	assert.Equal(t, "0000000001", c.Path)
+}
+
+func TestCommentRepo_Errors(t *testing.T) {
+	db := newTestDB(t)
+	r := NewRepo(db)
+
+	_, err := r.Create(context.Background(), model.Comment{Name: "Name", Email: "u@t.com", Body: "Body.", PostID: 2})
+	assert.True(t, errors.Is(err, store.ErrConstraint))
+
+	_, err = r.GetByID(context.Background(), 1)
+	assert.Equal(t, ErrNotFound, err)
+	assert.True(t, errors.Is(err, store.ErrNotFound))
+
+	sqlDB, _ := db.DB()
+	sqlDB.Close()
+	_, _, err = r.GetAll(context.Background(), model.CommentFilter{}, model.Page{Limit: 10})
+	assert.True(t, errors.Is(err, store.ErrUnavailable))
+}
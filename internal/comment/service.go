@@ -1,42 +1,138 @@
 package comment
 
-import "github.com/imarrche/nix-ed/internal/model"
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/post"
+)
+
+// sorts are comment list sort parameters.
+var sorts = []string{"id", "name", "created", "updated", "thread"}
 
 // service is comment service implementation.
 type service struct {
-	r Repo
+	r  Repo
+	pr post.Repo
+
+	// maxDepth is the maximum depth of replies.
+	maxDepth int
+}
+
+// NewService creates and returns a new Service instance, replies can be
+// nested up to maxDepth levels.
+func NewService(r Repo, pr post.Repo, maxDepth int) Service {
+	return &service{r: r, pr: pr, maxDepth: maxDepth}
+}
+
+// visiblePost returns post.ErrNotFound when the post with specific ID doesn't
+// exist or isn't visible to the user with specific ID.
+func (s *service) visiblePost(ctx context.Context, id int, viewer string) error {
+	p, err := s.pr.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !p.VisibleTo(viewer) {
+		return post.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetAll gets and returns the page of comments matching the filter.
+func (s *service) GetAll(ctx context.Context, f model.CommentFilter, pg model.Page) ([]model.Comment, model.PageInfo, error) {
+	if err := pg.Validate(sorts...); err != nil {
+		return nil, model.PageInfo{}, err
+	}
+
+	return s.r.GetAll(ctx, f, pg)
 }
 
-// NewService creates and returns a new Service instance.
-func NewService(r Repo) Service {
-	return &service{r}
+// GetAllByPostID gets and returns the page of comments of the post with
+// specific ID, the post must be visible to the viewer.
+func (s *service) GetAllByPostID(ctx context.Context, postID int, viewer string, pg model.Page) (
+	[]model.Comment, model.PageInfo, error,
+) {
+	if err := s.visiblePost(ctx, postID, viewer); err != nil {
+		return nil, model.PageInfo{}, err
+	}
+	if err := pg.Validate(sorts...); err != nil {
+		return nil, model.PageInfo{}, err
+	}
+
+	return s.r.GetAllByPostID(ctx, postID, pg)
 }
 
-// GetAll gets and returns all comment.
-func (s *service) GetAll() (cs []model.Comment, err error) {
-	return s.r.GetAll()
+// GetTreeByPostID gets and returns the page of top-level comments of the post
+// with specific ID with all their replies, the post must be visible to the
+// viewer.
+func (s *service) GetTreeByPostID(ctx context.Context, postID int, viewer string, pg model.Page) (
+	[]model.Comment, model.PageInfo, error,
+) {
+	if err := s.visiblePost(ctx, postID, viewer); err != nil {
+		return nil, model.PageInfo{}, err
+	}
+	if err := pg.Validate(sorts...); err != nil {
+		return nil, model.PageInfo{}, err
+	}
+
+	return s.r.GetTreeByPostID(ctx, postID, pg)
 }
 
-// Create creates a comment and returns it.
-func (s *service) Create(c model.Comment) (model.Comment, error) {
+// Create creates a comment and returns it. The post must be visible to the
+// comment's author and replies must reply to a comment of the same post.
+func (s *service) Create(ctx context.Context, c model.Comment) (model.Comment, error) {
 	if err := c.Validate(); err != nil {
 		return model.Comment{}, err
 	}
+	if err := s.visiblePost(ctx, c.PostID, author(c)); err != nil {
+		return model.Comment{}, err
+	}
 
-	return s.r.Create(c)
+	c.Depth = 0
+	if c.ParentID != nil {
+		parent, err := s.r.GetByID(ctx, *c.ParentID)
+		if err == ErrNotFound || err == nil && parent.PostID != c.PostID {
+			return model.Comment{}, ErrParentNotFound
+		} else if err != nil {
+			return model.Comment{}, err
+		}
+		if parent.Depth >= s.maxDepth {
+			return model.Comment{}, ErrMaxDepth
+		}
+		c.Depth = parent.Depth + 1
+	}
+
+	return s.r.Create(ctx, c)
 }
 
-// GetByID gets and returns the comment with specific ID.
-func (s *service) GetByID(id int) (c model.Comment, err error) {
-	return s.r.GetByID(id)
+// GetByID gets and returns the comment with specific ID, comments of posts
+// that aren't visible to the viewer aren't found.
+func (s *service) GetByID(ctx context.Context, id int, viewer string) (model.Comment, error) {
+	c, err := s.r.GetByID(ctx, id)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	if err := s.visiblePost(ctx, c.PostID, viewer); err == post.ErrNotFound {
+		return model.Comment{}, ErrNotFound
+	} else if err != nil {
+		return model.Comment{}, err
+	}
+
+	return c, nil
 }
 
-// Update updates the comment and returns it.
-func (s *service) Update(c model.Comment) (model.Comment, error) {
-	uc, err := s.r.GetByID(c.ID)
+// Update updates the comment and returns it. When comment's version is set,
+// it must be the current one.
+func (s *service) Update(ctx context.Context, c model.Comment) (model.Comment, error) {
+	uc, err := s.r.GetByID(ctx, c.ID)
 	if err != nil {
 		return model.Comment{}, err
 	}
+	if c.Version != 0 && c.Version != uc.Version {
+		return model.Comment{}, ErrVersionMismatch
+	}
 
 	uc.Name = c.Name
 	uc.Body = c.Body
@@ -44,10 +140,54 @@ func (s *service) Update(c model.Comment) (model.Comment, error) {
 		return model.Comment{}, err
 	}
 
-	return s.r.Update(uc)
+	return s.r.Update(ctx, uc)
 }
 
 // DeleteByID deletes the comment with specific ID.
-func (s *service) DeleteByID(id int) error {
-	return s.r.DeleteByID(id)
+func (s *service) DeleteByID(ctx context.Context, id int) error {
+	return s.r.DeleteByID(ctx, id)
+}
+
+// GetTrash gets and returns the page of user's deleted comments.
+func (s *service) GetTrash(ctx context.Context, userID string, pg model.Page) ([]model.Comment, model.PageInfo, error) {
+	if err := pg.Validate(sorts...); err != nil {
+		return nil, model.PageInfo{}, err
+	}
+
+	return s.r.GetTrash(ctx, userID, pg)
+}
+
+// GetDeletedByID gets and returns the deleted comment with specific ID.
+func (s *service) GetDeletedByID(ctx context.Context, id int) (model.Comment, error) {
+	return s.r.GetDeletedByID(ctx, id)
+}
+
+// Restore restores the deleted comment with specific ID and returns it. The
+// comment's post must not be deleted and must be visible to its author.
+func (s *service) Restore(ctx context.Context, id int) (model.Comment, error) {
+	c, err := s.r.GetDeletedByID(ctx, id)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	if err := s.visiblePost(ctx, c.PostID, author(c)); err != nil {
+		return model.Comment{}, err
+	}
+
+	return s.r.Restore(ctx, id)
+}
+
+// Purge permanently deletes comments moved to trash before specific time and
+// returns their number.
+func (s *service) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return s.r.Purge(ctx, before)
+}
+
+// author returns ID of the comment's author, it's empty for comments written
+// before users were stored.
+func author(c model.Comment) string {
+	if c.UserID == nil {
+		return ""
+	}
+
+	return *c.UserID
 }
@@ -1,6 +1,7 @@
 package comment
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -10,26 +11,98 @@ import (
 
 	mockcomment "github.com/imarrche/nix-ed/internal/comment/mock"
 	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/post"
+	mockpost "github.com/imarrche/nix-ed/internal/post/mock"
 )
 
 func TestCommentService_GetAll(t *testing.T) {
 	testcases := []struct {
 		name        string
-		mock        func(*mockcomment.MockRepo, []model.Comment)
+		mock        func(*mockcomment.MockRepo, model.Page, []model.Comment)
+		page        model.Page
 		comments    []model.Comment
 		expComments []model.Comment
+		expPageInfo model.PageInfo
 		expError    error
 	}{
 		{
 			name: "comments are retrieved",
-			mock: func(r *mockcomment.MockRepo, cs []model.Comment) {
-				r.EXPECT().GetAll().Return(cs, nil)
+			mock: func(r *mockcomment.MockRepo, pg model.Page, cs []model.Comment) {
+				r.EXPECT().GetAll(gomock.Any(), model.CommentFilter{}, pg).Return(cs, model.PageInfo{Total: 3, NextCursor: "c"}, nil)
 
 			},
+			page:        model.Page{Limit: 2},
 			comments:    []model.Comment{{Name: "Comment 1"}, {Name: "Comment 1."}},
 			expComments: []model.Comment{{Name: "Comment 1"}, {Name: "Comment 1."}},
+			expPageInfo: model.PageInfo{Total: 3, NextCursor: "c"},
 			expError:    nil,
 		},
+		{
+			name: "validation errors",
+			mock: func(_ *mockcomment.MockRepo, _ model.Page, _ []model.Comment) {},
+			page: model.Page{Limit: 200, Offset: 1, Cursor: "c"},
+			expError: validation.Errors{
+				"limit":  errors.New("must be no greater than 100"),
+				"cursor": errors.New("can't be combined with offset"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mockcomment.NewMockRepo(c)
+			tc.mock(repo, tc.page, tc.comments)
+			s := NewService(repo, nil, 5)
+
+			cs, pi, err := s.GetAll(context.Background(), model.CommentFilter{}, tc.page)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expComments, cs)
+			assert.Equal(t, tc.expPageInfo, pi)
+		})
+	}
+}
+
+func TestCommentService_GetAllByPostID(t *testing.T) {
+	testcases := []struct {
+		name        string
+		mock        func(*mockcomment.MockRepo, *mockpost.MockRepo, model.Page, []model.Comment)
+		page        model.Page
+		comments    []model.Comment
+		expComments []model.Comment
+		expPageInfo model.PageInfo
+		expError    error
+	}{
+		{
+			name: "post's comments are retrieved",
+			mock: func(r *mockcomment.MockRepo, pr *mockpost.MockRepo, pg model.Page, cs []model.Comment) {
+				pr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostPublished}, nil)
+				r.EXPECT().GetAllByPostID(gomock.Any(), 1, pg).Return(cs, model.PageInfo{Total: 2}, nil)
+			},
+			page:        model.Page{Limit: 20},
+			comments:    []model.Comment{{Name: "Comment 1", PostID: 1}, {Name: "Comment 2", PostID: 1}},
+			expComments: []model.Comment{{Name: "Comment 1", PostID: 1}, {Name: "Comment 2", PostID: 1}},
+			expPageInfo: model.PageInfo{Total: 2},
+			expError:    nil,
+		},
+		{
+			name: "post not found",
+			mock: func(_ *mockcomment.MockRepo, pr *mockpost.MockRepo, _ model.Page, _ []model.Comment) {
+				pr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{}, post.ErrNotFound)
+			},
+			page:     model.Page{Limit: 20},
+			expError: post.ErrNotFound,
+		},
+		{
+			name: "validation errors",
+			mock: func(_ *mockcomment.MockRepo, pr *mockpost.MockRepo, _ model.Page, _ []model.Comment) {
+				pr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostPublished}, nil)
+			},
+			page:     model.Page{Limit: 20, Sort: "title"},
+			expError: validation.Errors{"sort": errors.New("must be a valid value")},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -37,29 +110,42 @@ func TestCommentService_GetAll(t *testing.T) {
 			c := gomock.NewController(t)
 			defer c.Finish()
 			repo := mockcomment.NewMockRepo(c)
-			tc.mock(repo, tc.comments)
-			s := NewService(repo)
+			postRepo := mockpost.NewMockRepo(c)
+			tc.mock(repo, postRepo, tc.page, tc.comments)
+			s := NewService(repo, postRepo, 5)
 
-			cs, err := s.GetAll()
+			cs, pi, err := s.GetAllByPostID(context.Background(), 1, "", tc.page)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expComments, cs)
+			assert.Equal(t, tc.expPageInfo, pi)
 		})
 	}
 }
 
+// intPtr returns a pointer to i.
+func intPtr(i int) *int {
+	return &i
+}
+
+// strPtr returns a pointer to s.
+func strPtr(s string) *string {
+	return &s
+}
+
 func TestCommentService_Create(t *testing.T) {
 	testcases := []struct {
 		name       string
-		mock       func(*mockcomment.MockRepo, model.Comment)
+		mock       func(*mockcomment.MockRepo, *mockpost.MockRepo, model.Comment)
 		comment    model.Comment
 		expComment model.Comment
 		expError   error
 	}{
 		{
 			name: "comment is created",
-			mock: func(r *mockcomment.MockRepo, cm model.Comment) {
-				r.EXPECT().Create(cm).Return(cm, nil)
+			mock: func(r *mockcomment.MockRepo, pr *mockpost.MockRepo, cm model.Comment) {
+				pr.EXPECT().GetByID(gomock.Any(), cm.PostID).Return(model.Post{ID: cm.PostID, Status: model.PostPublished}, nil)
+				r.EXPECT().Create(gomock.Any(), cm).Return(cm, nil)
 			},
 			comment:    model.Comment{Name: "Comment 1", Email: "u@t.com", Body: "Body.", PostID: 1},
 			expComment: model.Comment{Name: "Comment 1", Email: "u@t.com", Body: "Body.", PostID: 1},
@@ -67,10 +153,74 @@ func TestCommentService_Create(t *testing.T) {
 		},
 		{
 			name:     "validation errors",
-			mock:     func(_ *mockcomment.MockRepo, _ model.Comment) {},
+			mock:     func(_ *mockcomment.MockRepo, _ *mockpost.MockRepo, _ model.Comment) {},
 			comment:  model.Comment{Name: "Title 1", Email: "u@t.com", PostID: 1},
 			expError: validation.Errors{"body": errors.New("cannot be blank")},
 		},
+		{
+			name: "post not found",
+			mock: func(_ *mockcomment.MockRepo, pr *mockpost.MockRepo, cm model.Comment) {
+				pr.EXPECT().GetByID(gomock.Any(), cm.PostID).Return(model.Post{}, post.ErrNotFound)
+			},
+			comment:  model.Comment{Name: "Comment 1", Email: "u@t.com", Body: "Body.", PostID: 2},
+			expError: post.ErrNotFound,
+		},
+		{
+			name: "post is a draft of another user",
+			mock: func(_ *mockcomment.MockRepo, pr *mockpost.MockRepo, cm model.Comment) {
+				pr.EXPECT().GetByID(gomock.Any(), cm.PostID).Return(model.Post{ID: cm.PostID, Status: model.PostDraft, UserID: "2"}, nil)
+			},
+			comment:  model.Comment{Name: "Comment 1", Email: "u@t.com", Body: "Body.", PostID: 2},
+			expError: post.ErrNotFound,
+		},
+		{
+			name: "post is a draft of the comment's author",
+			mock: func(r *mockcomment.MockRepo, pr *mockpost.MockRepo, cm model.Comment) {
+				pr.EXPECT().GetByID(gomock.Any(), cm.PostID).Return(model.Post{ID: cm.PostID, Status: model.PostDraft, UserID: "2"}, nil)
+				r.EXPECT().Create(gomock.Any(), cm).Return(cm, nil)
+			},
+			comment:    model.Comment{Name: "Comment 1", Email: "u@t.com", Body: "Body.", PostID: 2, UserID: strPtr("2")},
+			expComment: model.Comment{Name: "Comment 1", Email: "u@t.com", Body: "Body.", PostID: 2, UserID: strPtr("2")},
+		},
+		{
+			name: "reply is created",
+			mock: func(r *mockcomment.MockRepo, pr *mockpost.MockRepo, cm model.Comment) {
+				pr.EXPECT().GetByID(gomock.Any(), cm.PostID).Return(model.Post{ID: cm.PostID, Status: model.PostPublished}, nil)
+				r.EXPECT().GetByID(gomock.Any(), *cm.ParentID).Return(model.Comment{ID: *cm.ParentID, PostID: 1, Depth: 4}, nil)
+				cm.Depth = 5
+				r.EXPECT().Create(gomock.Any(), cm).Return(cm, nil)
+			},
+			comment:    model.Comment{Name: "Comment 1", Email: "u@t.com", Body: "Body.", PostID: 1, ParentID: intPtr(3)},
+			expComment: model.Comment{Name: "Comment 1", Email: "u@t.com", Body: "Body.", PostID: 1, ParentID: intPtr(3), Depth: 5},
+			expError:   nil,
+		},
+		{
+			name: "reply is nested too deep",
+			mock: func(r *mockcomment.MockRepo, pr *mockpost.MockRepo, cm model.Comment) {
+				pr.EXPECT().GetByID(gomock.Any(), cm.PostID).Return(model.Post{ID: cm.PostID, Status: model.PostPublished}, nil)
+				r.EXPECT().GetByID(gomock.Any(), *cm.ParentID).Return(model.Comment{ID: *cm.ParentID, PostID: 1, Depth: 5}, nil)
+			},
+			comment:  model.Comment{Name: "Comment 1", Email: "u@t.com", Body: "Body.", PostID: 1, ParentID: intPtr(3)},
+			expError: ErrMaxDepth,
+		},
+		{
+			name: "parent is a comment of another post",
+			mock: func(r *mockcomment.MockRepo, pr *mockpost.MockRepo, cm model.Comment) {
+				pr.EXPECT().GetByID(gomock.Any(), cm.PostID).Return(model.Post{ID: cm.PostID, Status: model.PostPublished}, nil)
+				r.EXPECT().GetByID(gomock.Any(), *cm.ParentID).Return(model.Comment{ID: *cm.ParentID, PostID: 2}, nil)
+			},
+			comment:  model.Comment{Name: "Comment 1", Email: "u@t.com", Body: "Body.", PostID: 1, ParentID: intPtr(3)},
+			expError: ErrParentNotFound,
+		},
+		{
+			name: "parent not found",
+			mock: func(r *mockcomment.MockRepo, pr *mockpost.MockRepo, cm model.Comment) {
+				pr.EXPECT().GetByID(gomock.Any(), cm.PostID).Return(model.Post{ID: cm.PostID, Status: model.PostPublished}, nil)
+				r.EXPECT().GetByID(gomock.Any(), *cm.ParentID).Return(model.Comment{}, ErrNotFound)
+			},
+			comment:  model.Comment{Name: "Comment 1", Email: "u@t.com", Body: "Body.", PostID: 1, ParentID: intPtr(3)},
+			expError: ErrParentNotFound,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -78,10 +228,11 @@ func TestCommentService_Create(t *testing.T) {
 			c := gomock.NewController(t)
 			defer c.Finish()
 			repo := mockcomment.NewMockRepo(c)
-			tc.mock(repo, tc.comment)
-			s := NewService(repo)
+			postRepo := mockpost.NewMockRepo(c)
+			tc.mock(repo, postRepo, tc.comment)
+			s := NewService(repo, postRepo, 5)
 
-			cm, err := s.Create(tc.comment)
+			cm, err := s.Create(context.Background(), tc.comment)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expComment, cm)
@@ -92,19 +243,45 @@ func TestCommentService_Create(t *testing.T) {
 func TestCommentService_GetByID(t *testing.T) {
 	testcases := []struct {
 		name       string
-		mock       func(*mockcomment.MockRepo, model.Comment)
+		mock       func(*mockcomment.MockRepo, *mockpost.MockRepo, model.Comment)
 		comment    model.Comment
 		expComment model.Comment
 		expError   error
 	}{
 		{
 			name: "comment is retrieved by ID",
-			mock: func(r *mockcomment.MockRepo, cm model.Comment) {
-				r.EXPECT().GetByID(cm.ID).Return(cm, nil)
+			mock: func(r *mockcomment.MockRepo, pr *mockpost.MockRepo, cm model.Comment) {
+				r.EXPECT().GetByID(gomock.Any(), cm.ID).Return(cm, nil)
+				pr.EXPECT().GetByID(gomock.Any(), cm.PostID).Return(model.Post{ID: cm.PostID, Status: model.PostPublished}, nil)
 			},
-			comment:    model.Comment{Name: "Comment 1"},
-			expComment: model.Comment{Name: "Comment 1"},
-			expError:   nil,
+			comment:    model.Comment{ID: 1, Name: "Comment 1", PostID: 1},
+			expComment: model.Comment{ID: 1, Name: "Comment 1", PostID: 1},
+		},
+		{
+			name: "comment of the viewer's draft is retrieved",
+			mock: func(r *mockcomment.MockRepo, pr *mockpost.MockRepo, cm model.Comment) {
+				r.EXPECT().GetByID(gomock.Any(), cm.ID).Return(cm, nil)
+				pr.EXPECT().GetByID(gomock.Any(), cm.PostID).Return(model.Post{ID: cm.PostID, Status: model.PostDraft, UserID: "1"}, nil)
+			},
+			comment:    model.Comment{ID: 1, Name: "Comment 1", PostID: 1},
+			expComment: model.Comment{ID: 1, Name: "Comment 1", PostID: 1},
+		},
+		{
+			name: "comment of another user's draft is not found",
+			mock: func(r *mockcomment.MockRepo, pr *mockpost.MockRepo, cm model.Comment) {
+				r.EXPECT().GetByID(gomock.Any(), cm.ID).Return(cm, nil)
+				pr.EXPECT().GetByID(gomock.Any(), cm.PostID).Return(model.Post{ID: cm.PostID, Status: model.PostDraft, UserID: "2"}, nil)
+			},
+			comment:  model.Comment{ID: 1, Name: "Comment 1", PostID: 1},
+			expError: ErrNotFound,
+		},
+		{
+			name: "comment not found",
+			mock: func(r *mockcomment.MockRepo, _ *mockpost.MockRepo, cm model.Comment) {
+				r.EXPECT().GetByID(gomock.Any(), cm.ID).Return(model.Comment{}, ErrNotFound)
+			},
+			comment:  model.Comment{ID: 1},
+			expError: ErrNotFound,
 		},
 	}
 
@@ -113,10 +290,11 @@ func TestCommentService_GetByID(t *testing.T) {
 			c := gomock.NewController(t)
 			defer c.Finish()
 			repo := mockcomment.NewMockRepo(c)
-			tc.mock(repo, tc.comment)
-			s := NewService(repo)
+			postRepo := mockpost.NewMockRepo(c)
+			tc.mock(repo, postRepo, tc.comment)
+			s := NewService(repo, postRepo, 5)
 
-			cm, err := s.GetByID(tc.comment.ID)
+			cm, err := s.GetByID(context.Background(), tc.comment.ID, "1")
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expComment, cm)
@@ -135,8 +313,8 @@ func TestCommentService_Update(t *testing.T) {
 		{
 			name: "comment is updated",
 			mock: func(r *mockcomment.MockRepo, cm model.Comment) {
-				r.EXPECT().GetByID(cm.ID).Return(cm, nil)
-				r.EXPECT().Update(cm).Return(cm, nil)
+				r.EXPECT().GetByID(gomock.Any(), cm.ID).Return(cm, nil)
+				r.EXPECT().Update(gomock.Any(), cm).Return(cm, nil)
 			},
 			comment:    model.Comment{Name: "Comment 1", Email: "u@t.com", Body: "Body.", PostID: 1},
 			expComment: model.Comment{Name: "Comment 1", Email: "u@t.com", Body: "Body.", PostID: 1},
@@ -145,7 +323,7 @@ func TestCommentService_Update(t *testing.T) {
 		{
 			name: "validation errors",
 			mock: func(r *mockcomment.MockRepo, cm model.Comment) {
-				r.EXPECT().GetByID(cm.ID).Return(cm, nil)
+				r.EXPECT().GetByID(gomock.Any(), cm.ID).Return(cm, nil)
 			},
 			comment:  model.Comment{Name: "Comment 1", Email: "u@t.com", PostID: 1},
 			expError: validation.Errors{"body": errors.New("cannot be blank")},
@@ -153,7 +331,7 @@ func TestCommentService_Update(t *testing.T) {
 		{
 			name: "comment not found",
 			mock: func(r *mockcomment.MockRepo, cm model.Comment) {
-				r.EXPECT().GetByID(cm.ID).Return(model.Comment{}, errors.New("not found"))
+				r.EXPECT().GetByID(gomock.Any(), cm.ID).Return(model.Comment{}, errors.New("not found"))
 			},
 			comment:  model.Comment{Name: "Comment 1"},
 			expError: errors.New("not found"),
@@ -166,9 +344,9 @@ func TestCommentService_Update(t *testing.T) {
 			defer c.Finish()
 			repo := mockcomment.NewMockRepo(c)
 			tc.mock(repo, tc.comment)
-			s := NewService(repo)
+			s := NewService(repo, nil, 5)
 
-			cm, err := s.Update(tc.comment)
+			cm, err := s.Update(context.Background(), tc.comment)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expComment, cm)
@@ -186,7 +364,7 @@ func TestCommentService_DeleteByID(t *testing.T) {
 		{
 			name: "comment is deleted by ID",
 			mock: func(r *mockcomment.MockRepo, cm model.Comment) {
-				r.EXPECT().DeleteByID(cm.ID).Return(nil)
+				r.EXPECT().DeleteByID(gomock.Any(), cm.ID).Return(nil)
 			},
 			comment:  model.Comment{Name: "Comment 1"},
 			expError: nil,
@@ -199,11 +377,66 @@ func TestCommentService_DeleteByID(t *testing.T) {
 			defer c.Finish()
 			repo := mockcomment.NewMockRepo(c)
 			tc.mock(repo, tc.comment)
-			s := NewService(repo)
+			s := NewService(repo, nil, 5)
 
-			err := s.DeleteByID(tc.comment.ID)
+			err := s.DeleteByID(context.Background(), tc.comment.ID)
 
 			assert.Equal(t, tc.expError, err)
 		})
 	}
 }
+
+func TestCommentService_Restore(t *testing.T) {
+	testcases := []struct {
+		name       string
+		mock       func(*mockcomment.MockRepo, *mockpost.MockRepo, model.Comment)
+		comment    model.Comment
+		expComment model.Comment
+		expError   error
+	}{
+		{
+			name: "comment is restored",
+			mock: func(r *mockcomment.MockRepo, pr *mockpost.MockRepo, cm model.Comment) {
+				r.EXPECT().GetDeletedByID(gomock.Any(), cm.ID).Return(cm, nil)
+				pr.EXPECT().GetByID(gomock.Any(), cm.PostID).Return(model.Post{ID: cm.PostID, Status: model.PostPublished}, nil)
+				r.EXPECT().Restore(gomock.Any(), cm.ID).Return(cm, nil)
+			},
+			comment:    model.Comment{ID: 1, Body: "Body.", PostID: 1},
+			expComment: model.Comment{ID: 1, Body: "Body.", PostID: 1},
+			expError:   nil,
+		},
+		{
+			name: "comment not found",
+			mock: func(r *mockcomment.MockRepo, _ *mockpost.MockRepo, cm model.Comment) {
+				r.EXPECT().GetDeletedByID(gomock.Any(), cm.ID).Return(model.Comment{}, ErrNotFound)
+			},
+			comment:  model.Comment{ID: 1, Body: "Body.", PostID: 1},
+			expError: ErrNotFound,
+		},
+		{
+			name: "post is deleted",
+			mock: func(r *mockcomment.MockRepo, pr *mockpost.MockRepo, cm model.Comment) {
+				r.EXPECT().GetDeletedByID(gomock.Any(), cm.ID).Return(cm, nil)
+				pr.EXPECT().GetByID(gomock.Any(), cm.PostID).Return(model.Post{}, post.ErrNotFound)
+			},
+			comment:  model.Comment{ID: 1, Body: "Body.", PostID: 1},
+			expError: post.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mockcomment.NewMockRepo(c)
+			postRepo := mockpost.NewMockRepo(c)
+			tc.mock(repo, postRepo, tc.comment)
+			s := NewService(repo, postRepo, 5)
+
+			cm, err := s.Restore(context.Background(), tc.comment.ID)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expComment, cm)
+		})
+	}
+}
@@ -4,6 +4,7 @@ package config
 import (
 	"log"
 	"sync"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
@@ -15,9 +16,46 @@ var (
 
 // Config is configuration for all project components.
 type Config struct {
+	// BaseURL is the URL the API is served at, providers redirect back to
+	// callbacks under it.
+	BaseURL string `envconfig:"BASE_URL" default:"http://localhost:8080"`
+
+	// ClientID and ClientSecret are credentials of the Google OAuth client.
 	ClientID     string `envconfig:"CLIENT_ID"`
 	ClientSecret string `envconfig:"CLIENT_SECRET"`
-	AuthCodeURL  string `envconfig:"AUTH_CODE_URL"`
+	// GitHubClientID and GitHubClientSecret are credentials of the GitHub
+	// OAuth app, GitHub sign-in is enabled when they're set.
+	GitHubClientID     string `envconfig:"GITHUB_CLIENT_ID"`
+	GitHubClientSecret string `envconfig:"GITHUB_CLIENT_SECRET"`
+	// OIDCIssuer, OIDCClientID and OIDCClientSecret configure an OpenID
+	// Connect provider, it's enabled under OIDCName when the issuer is set.
+	OIDCName         string `envconfig:"OIDC_NAME" default:"oidc"`
+	OIDCIssuer       string `envconfig:"OIDC_ISSUER"`
+	OIDCClientID     string `envconfig:"OIDC_CLIENT_ID"`
+	OIDCClientSecret string `envconfig:"OIDC_CLIENT_SECRET"`
+
+	// SessionSecret signs access tokens, a random one is used when it's empty
+	// so tokens don't outlive the process.
+	SessionSecret string `envconfig:"SESSION_SECRET"`
+	// AccessTokenTTL and RefreshTokenTTL are how long issued access and
+	// refresh tokens are valid for.
+	AccessTokenTTL  time.Duration `envconfig:"ACCESS_TOKEN_TTL" default:"15m"`
+	RefreshTokenTTL time.Duration `envconfig:"REFRESH_TOKEN_TTL" default:"720h"`
+
+	// ReadTimeout, WriteTimeout and AuthTimeout are how long reading, writing
+	// and sign-in requests can take before their work is canceled.
+	ReadTimeout  time.Duration `envconfig:"READ_TIMEOUT" default:"5s"`
+	WriteTimeout time.Duration `envconfig:"WRITE_TIMEOUT" default:"10s"`
+	AuthTimeout  time.Duration `envconfig:"AUTH_TIMEOUT" default:"15s"`
+
+	// TrashRetention is how long deleted posts and comments can be restored.
+	TrashRetention time.Duration `envconfig:"TRASH_RETENTION" default:"720h"`
+	// PublishInterval is how often scheduled posts are checked for publishing.
+	PublishInterval time.Duration `envconfig:"PUBLISH_INTERVAL" default:"1m"`
+	// MaxCommentDepth is how many levels of replies comments can have.
+	MaxCommentDepth int `envconfig:"MAX_COMMENT_DEPTH" default:"5"`
+	// SearchEngine is the search service implementation, mysql or memory.
+	SearchEngine string `envconfig:"SEARCH_ENGINE" default:"mysql"`
 }
 
 // Get reads configuration once and returns it.
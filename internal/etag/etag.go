@@ -0,0 +1,35 @@
+// Package etag provides entity tags for conditional requests.
+package etag
+
+import (
+	"strconv"
+	"strings"
+)
+
+// New returns the strong entity tag of specific version of a resource.
+func New(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// Match reports whether the If-Match or If-None-Match header matches the
+// entity tag. If-Match uses strong comparison, so weak tags in the header
+// match nothing, If-None-Match uses weak comparison.
+func Match(header, tag string, weak bool) bool {
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" {
+			return true
+		}
+		if strings.HasPrefix(t, "W/") {
+			if !weak {
+				continue
+			}
+			t = t[2:]
+		}
+		if t == strings.TrimPrefix(tag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,30 @@
+package etag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	testcases := []struct {
+		name     string
+		header   string
+		weak     bool
+		expMatch bool
+	}{
+		{name: "same tag", header: `"2"`, expMatch: true},
+		{name: "other tag", header: `"1"`, expMatch: false},
+		{name: "any tag", header: `*`, expMatch: true},
+		{name: "tag in list", header: `"1", "2"`, expMatch: true},
+		{name: "weak tag in strong comparison", header: `W/"2"`, expMatch: false},
+		{name: "weak tag in weak comparison", header: `W/"2"`, weak: true, expMatch: true},
+		{name: "empty header", header: ``, expMatch: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expMatch, Match(tc.header, New(2), tc.weak))
+		})
+	}
+}
@@ -0,0 +1,19 @@
+// Package handlertest provides helpers for HTTP handler tests.
+package handlertest
+
+import (
+	"io/ioutil"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/imarrche/nix-ed/internal/problem"
+)
+
+// Serve calls the handler and responds with the problem of its error like
+// the router does. Server errors aren't logged, they're expected by tests.
+func Serve(c echo.Context, h echo.HandlerFunc) {
+	c.Echo().Logger.SetOutput(ioutil.Discard)
+	if err := h(c); err != nil {
+		problem.Handler(err, c)
+	}
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// Category model represents a post category.
+type Category struct {
+	ID          int    `json:"id" xml:"id" gorm:"primaryKey"`
+	Name        string `json:"name" xml:"name" gorm:"type:varchar(64);not null;uniqueIndex"`
+	Description string `json:"description" xml:"description"`
+
+	CreatedAt time.Time `json:"createdAt" xml:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" xml:"updatedAt"`
+}
+
+// Validate validates category's fields.
+func (c *Category) Validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.Name, validation.Required, validation.Length(1, 64)),
+		validation.Field(&c.Description, validation.Length(0, 1000)),
+	)
+}
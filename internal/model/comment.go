@@ -1,10 +1,17 @@
 package model
 
 import (
+	"time"
+
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
+	"gorm.io/gorm"
 )
 
+// DeletedCommentBody is the body of deleted comments shown in place of them
+// to keep their replies in the thread.
+const DeletedCommentBody = "[deleted]"
+
 // Comment model represents a post's comment.
 type Comment struct {
 	ID     int    `json:"id" xml:"id" gorm:"primaryKey"`
@@ -12,14 +19,62 @@ type Comment struct {
 	Email  string `json:"email" xml:"email"`
 	Body   string `json:"body" xml:"body"`
 	PostID int    `json:"postId" xml:"postId"`
+
+	// UserID is ID of the user who wrote and owns the comment, it's empty for
+	// comments written before users were stored. Email is the author's email
+	// when it was known, it doesn't grant ownership.
+	UserID *string `json:"userId,omitempty" xml:"userId,omitempty" gorm:"type:varchar(64);index"`
+
+	// ParentID is ID of the comment this one replies to, Depth is the number
+	// of its ancestors. Path is IDs of the ancestors and the comment itself,
+	// so sorting by it orders comments by thread.
+	ParentID *int   `json:"parentId,omitempty" xml:"parentId,omitempty" gorm:"index"`
+	Depth    int    `json:"depth" xml:"depth" gorm:"not null;default:0"`
+	Path     string `json:"-" xml:"-" gorm:"type:varchar(512);not null;default:'';index"`
+
+	// Replies are loaded only for comment trees, the relation makes replies be
+	// purged together with the comment.
+	Replies []Comment `json:"replies,omitempty" xml:"replies,omitempty" gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE"`
+	// Deleted is set for deleted comments shown in place of them.
+	Deleted bool `json:"deleted,omitempty" xml:"deleted,omitempty" gorm:"-"`
+
+	// Reactions are counts of comment's reactions, they're set only for
+	// comments returned by GET requests.
+	Reactions []ReactionCount `json:"reactions,omitempty" xml:"reactions>reaction,omitempty" gorm:"-"`
+
+	// Version is incremented on every update, it's the comment's ETag.
+	Version int `json:"version" xml:"version" gorm:"not null;default:1"`
+
+	CreatedAt time.Time      `json:"createdAt" xml:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt" xml:"updatedAt" gorm:"index"`
+	DeletedAt gorm.DeletedAt `json:"-" xml:"-" gorm:"index"`
+}
+
+// CommentFilter keeps conditions comments are listed by.
+type CommentFilter struct {
+	UpdatedSince time.Time `query:"updated_since"`
+
+	// Viewer is ID of the user listing comments, comments of drafts and
+	// scheduled posts of other users are hidden from them.
+	Viewer string `query:"-"`
+	// IncludeHidden lists comments of drafts and scheduled posts of all users
+	// whoever the viewer is, it's for internal listings such as search
+	// indexing.
+	IncludeHidden bool `query:"-"`
 }
 
-// Validate validates comment's fields.
+// Validate validates comment's fields. Comments of users may have no email,
+// users' emails may be unverified.
 func (c *Comment) Validate() error {
+	email := []validation.Rule{is.Email}
+	if c.UserID == nil {
+		email = append([]validation.Rule{validation.Required}, email...)
+	}
+
 	return validation.ValidateStruct(
 		c,
 		validation.Field(&c.Name, validation.Required),
-		validation.Field(&c.Email, validation.Required, is.Email),
+		validation.Field(&c.Email, email...),
 		validation.Field(&c.Body, validation.Required),
 		validation.Field(&c.PostID, validation.Required),
 	)
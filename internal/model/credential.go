@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+// PasswordCredential model represents the email and the password of a user
+// signing in with a password. Hash is the bcrypt hash of the password.
+type PasswordCredential struct {
+	Email  string `gorm:"type:varchar(255);primaryKey"`
+	UserID string `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Hash   string `gorm:"type:varchar(72);not null"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// PasswordSignUp is the request of signing up with a password.
+type PasswordSignUp struct {
+	Email    string `json:"email" xml:"email"`
+	Password string `json:"password" xml:"password"`
+	Name     string `json:"name" xml:"name"`
+}
+
+// Validate validates the sign-up. Passwords are limited to 72 bytes, bcrypt
+// ignores the rest.
+func (s *PasswordSignUp) Validate() error {
+	return validation.ValidateStruct(
+		s,
+		validation.Field(&s.Email, validation.Required, validation.Length(0, 255), is.Email),
+		validation.Field(&s.Password, validation.Required, validation.Length(8, 72)),
+		validation.Field(&s.Name, validation.Length(0, 128)),
+	)
+}
+
+// PasswordSignIn is the request of signing in with a password.
+type PasswordSignIn struct {
+	Email    string `json:"email" xml:"email"`
+	Password string `json:"password" xml:"password"`
+}
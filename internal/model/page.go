@@ -0,0 +1,149 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultPageLimit is the number of items returned when no limit is given.
+	DefaultPageLimit = 20
+	// MaxPageLimit is the maximum number of items that can be requested at once.
+	MaxPageLimit = 100
+)
+
+var (
+	errInvalidCursor = errors.New("must be a cursor returned by the previous page")
+	errCursorOffset  = errors.New("can't be combined with offset")
+)
+
+// Page keeps pagination and sorting options of a list request.
+type Page struct {
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+	Cursor string `json:"cursor" query:"cursor"`
+	Sort   string `json:"sort" query:"sort"`
+	Order  string `json:"order" query:"order"`
+}
+
+// PageInfo describes the page returned for a list request.
+type PageInfo struct {
+	Total      int64
+	NextCursor string
+}
+
+// cursor is the decoded form of a page cursor: the sort value and the ID of
+// the last item of the previous page. Time sort values are kept apart so they
+// are decoded with their type.
+type cursor struct {
+	Value interface{} `json:"v,omitempty"`
+	Time  *time.Time  `json:"t,omitempty"`
+	ID    int         `json:"id"`
+}
+
+// value returns the sort value of the cursor.
+func (c cursor) value() interface{} {
+	if c.Time != nil {
+		return *c.Time
+	}
+
+	return c.Value
+}
+
+// Validate validates page's fields, sorts are the allowed sort parameters.
+func (p *Page) Validate(sorts ...string) error {
+	allowed := make([]interface{}, len(sorts))
+	for i, s := range sorts {
+		allowed[i] = s
+	}
+
+	return validation.ValidateStruct(
+		p,
+		validation.Field(&p.Limit, validation.Required, validation.Min(1), validation.Max(MaxPageLimit)),
+		validation.Field(&p.Offset, validation.Min(0)),
+		validation.Field(&p.Cursor, validation.By(p.validateCursor)),
+		validation.Field(&p.Sort, validation.In(allowed...)),
+		validation.Field(&p.Order, validation.In("asc", "desc")),
+	)
+}
+
+// validateCursor ensures that the cursor can be decoded and isn't combined
+// with offset.
+func (p *Page) validateCursor(interface{}) error {
+	if p.Cursor == "" {
+		return nil
+	}
+	if p.Offset != 0 {
+		return errCursorOffset
+	}
+	if _, err := decodeCursor(p.Cursor); err != nil {
+		return errInvalidCursor
+	}
+
+	return nil
+}
+
+// Paginate returns a GORM scope that sorts by column and selects the page.
+// One extra row is requested so callers can tell whether more items exist.
+func (p Page) Paginate(column string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		op, dir := ">", "ASC"
+		if p.Order == "desc" {
+			op, dir = "<", "DESC"
+		}
+
+		if p.Cursor != "" {
+			c, err := decodeCursor(p.Cursor)
+			if err != nil {
+				db.AddError(err)
+				return db
+			}
+			if column == "id" {
+				db = db.Where(fmt.Sprintf("id %s ?", op), c.ID)
+			} else {
+				db = db.Where(
+					fmt.Sprintf("%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?)", column, op),
+					c.value(), c.value(), c.ID,
+				)
+			}
+		}
+		if column != "id" {
+			db = db.Order(fmt.Sprintf("%s %s", column, dir))
+		}
+
+		return db.Order("id " + dir).Offset(p.Offset).Limit(p.Limit + 1)
+	}
+}
+
+// NextCursor returns the cursor of the page that follows the item with given
+// sort value and ID.
+func (p Page) NextCursor(value interface{}, id int) string {
+	c := cursor{Value: value, ID: id}
+	if t, ok := value.(time.Time); ok {
+		c.Value, c.Time = nil, &t
+	}
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor decodes the page cursor, an empty cursor is valid.
+func decodeCursor(s string) (c cursor, err error) {
+	if s == "" {
+		return c, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(data, &c)
+
+	return c, err
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/testdb"
+)
+
+// item is a model sorted by its creation time.
+type item struct {
+	ID        int
+	CreatedAt time.Time
+}
+
+func TestPage_Paginate_TimeCursor(t *testing.T) {
+	db := testdb.New(t, &item{})
+	at := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+	// Times with and without fractional seconds and in other zones compare
+	// wrong as strings, items 2 and 3 share the time and are told apart by ID.
+	created := []time.Time{
+		at.Add(1500 * time.Millisecond),
+		at,
+		at.Add(time.Second),
+		at.Add(time.Second),
+		at.Add(2 * time.Second).In(time.FixedZone("UTC+2", 2*60*60)),
+	}
+	for i, c := range created {
+		if err := db.Create(&item{ID: i + 1, CreatedAt: c}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pg := Page{Limit: 2, Sort: "created_at"}
+	var got []int
+	for {
+		var is []item
+		assert.NoError(t, db.Scopes(pg.Paginate("created_at")).Find(&is).Error)
+		more := len(is) > pg.Limit
+		if more {
+			is = is[:pg.Limit]
+		}
+		for _, it := range is {
+			got = append(got, it.ID)
+		}
+		if !more {
+			break
+		}
+
+		last := is[len(is)-1]
+		pg.Cursor = pg.NextCursor(last.CreatedAt, last.ID)
+		c, err := decodeCursor(pg.Cursor)
+		assert.NoError(t, err)
+		assert.IsType(t, time.Time{}, c.value())
+	}
+
+	assert.Equal(t, []int{2, 3, 4, 1, 5}, got)
+}
@@ -1,14 +1,96 @@
 // Package model keep all project related business models.
 package model
 
-import validation "github.com/go-ozzo/ozzo-validation"
+import (
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"gorm.io/gorm"
+)
+
+// Post filter matches.
+const (
+	// MatchAll matches posts having all the tags and categories.
+	MatchAll = "all"
+	// MatchAny matches posts having any of the tags and categories.
+	MatchAny = "any"
+)
+
+// Post statuses.
+const (
+	// PostDraft is the status of posts visible only to their authors.
+	PostDraft = "draft"
+	// PostScheduled is the status of drafts published at PublishAt.
+	PostScheduled = "scheduled"
+	// PostPublished is the status of posts visible to everyone.
+	PostPublished = "published"
+	// PostArchived is the status of published posts that are no longer
+	// maintained, they stay visible to everyone.
+	PostArchived = "archived"
+)
+
+// MaxPostBodyLength is the maximum number of characters in post bodies.
+const MaxPostBodyLength = 100000
+
+// PublicPostStatuses are statuses of posts visible to everyone.
+var PublicPostStatuses = []string{PostPublished, PostArchived}
+
+var errPublishAtRequired = errors.New("is required for scheduled posts")
 
 // Post model represents a post.
 type Post struct {
 	ID     int    `json:"id" xml:"id" gorm:"primaryKey"`
 	Title  string `json:"title" xml:"title"`
 	Body   string `json:"body" xml:"body"`
-	UserID string `json:"userId" xml:"userId"`
+	UserID string `json:"userId" xml:"userId" gorm:"type:varchar(64);index"`
+
+	// Slug is generated from the title, it changes together with the title.
+	Slug string `json:"slug" xml:"slug" gorm:"type:varchar(96);index"`
+
+	Status    string     `json:"status" xml:"status" gorm:"type:varchar(16);not null;default:published;index"`
+	PublishAt *time.Time `json:"publishAt,omitempty" xml:"publishAt,omitempty" gorm:"index"`
+
+	// Tags and Categories are set by their names, a missing list keeps the
+	// post's tags or categories as they are.
+	Tags       []Tag      `json:"tags" xml:"tags" gorm:"many2many:post_tags;constraint:OnDelete:CASCADE"`
+	Categories []Category `json:"categories" xml:"categories" gorm:"many2many:post_categories;constraint:OnDelete:CASCADE"`
+
+	// Reactions are counts of post's reactions, they're set only for posts
+	// returned by GET requests.
+	Reactions []ReactionCount `json:"reactions,omitempty" xml:"reactions>reaction,omitempty" gorm:"-"`
+
+	// Version is incremented on every update, it's the post's ETag.
+	Version int `json:"version" xml:"version" gorm:"not null;default:1"`
+
+	CreatedAt time.Time      `json:"createdAt" xml:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt" xml:"updatedAt" gorm:"index"`
+	DeletedAt gorm.DeletedAt `json:"-" xml:"-" gorm:"index"`
+
+	// Comments, Revisions and Slugs are never loaded, the relations make
+	// post's comments, revisions and slugs be purged together with it.
+	Comments  []Comment      `json:"-" xml:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Revisions []PostRevision `json:"-" xml:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Slugs     []PostSlug     `json:"-" xml:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// PostFilter keeps conditions posts are listed by.
+type PostFilter struct {
+	UpdatedSince time.Time `query:"updated_since"`
+	Status       string    `query:"status"`
+
+	// Tags and Categories are names posts are filtered by, Match is "all" for
+	// posts having all of them or "any" for posts having at least one.
+	Tags       []string `query:"tag"`
+	Categories []string `query:"category"`
+	Match      string   `query:"match"`
+
+	// Viewer is ID of the user listing posts, drafts and scheduled posts of
+	// other users are hidden from them.
+	Viewer string `query:"-"`
+	// IncludeHidden lists drafts and scheduled posts of all users whoever the
+	// viewer is, it's for internal listings such as search indexing.
+	IncludeHidden bool `query:"-"`
 }
 
 // Validate validates post's fields.
@@ -16,7 +98,32 @@ func (p *Post) Validate() error {
 	return validation.ValidateStruct(
 		p,
 		validation.Field(&p.Title, validation.Required),
-		validation.Field(&p.Body, validation.Required),
+		validation.Field(&p.Body, validation.Required, validation.RuneLength(0, MaxPostBodyLength)),
 		validation.Field(&p.UserID, validation.Required),
+		validation.Field(
+			&p.Status,
+			validation.Required,
+			validation.In(PostDraft, PostScheduled, PostPublished, PostArchived),
+		),
+		validation.Field(&p.PublishAt, validation.By(p.validatePublishAt)),
 	)
 }
+
+// validatePublishAt requires the publication time of scheduled posts.
+func (p *Post) validatePublishAt(interface{}) error {
+	if p.Status == PostScheduled && p.PublishAt == nil {
+		return errPublishAtRequired
+	}
+
+	return nil
+}
+
+// Public reports whether the post is visible to everyone.
+func (p *Post) Public() bool {
+	return p.Status == PostPublished || p.Status == PostArchived
+}
+
+// VisibleTo reports whether the post is visible to the user with specific ID.
+func (p *Post) VisibleTo(userID string) bool {
+	return p.Public() || userID != "" && p.UserID == userID
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// Reaction target types.
+const (
+	// ReactionPost is the target type of reactions on posts.
+	ReactionPost = "post"
+	// ReactionComment is the target type of reactions on comments.
+	ReactionComment = "comment"
+)
+
+// ReactionKinds are the kinds of reactions users can add.
+var ReactionKinds = []string{"like", "heart", "laugh", "wow", "sad", "angry"}
+
+// Reaction model represents a user's reaction on a post or a comment. Users
+// can add every kind of reaction on an item once.
+type Reaction struct {
+	ID         int    `json:"id" xml:"id" gorm:"primaryKey"`
+	TargetType string `json:"targetType" xml:"targetType" gorm:"type:varchar(16);not null;uniqueIndex:idx_reactions_user"`
+	TargetID   int    `json:"targetId" xml:"targetId" gorm:"not null;uniqueIndex:idx_reactions_user"`
+	Kind       string `json:"kind" xml:"kind" gorm:"type:varchar(16);not null;uniqueIndex:idx_reactions_user"`
+	UserID     string `json:"userId" xml:"userId" gorm:"type:varchar(64);not null;uniqueIndex:idx_reactions_user"`
+
+	CreatedAt time.Time `json:"createdAt" xml:"createdAt"`
+}
+
+// ReactionCount is the number of reactions of a kind on an item.
+type ReactionCount struct {
+	Kind  string `json:"kind" xml:"kind"`
+	Count int64  `json:"count" xml:"count"`
+	// ReactedByMe reports whether the user who made the request added the
+	// reaction.
+	ReactedByMe bool `json:"reactedByMe" xml:"reactedByMe"`
+}
+
+// ReactionFilter keeps conditions reactions are listed by.
+type ReactionFilter struct {
+	Kind string `json:"kind" query:"kind"`
+
+	// Viewer is ID of the user listing reactions, reactions on drafts and
+	// scheduled posts of other users and on their comments are hidden from
+	// them.
+	Viewer string `json:"-" query:"-"`
+}
+
+// reactionKinds returns reaction kinds as validation.In arguments.
+func reactionKinds() []interface{} {
+	kinds := make([]interface{}, len(ReactionKinds))
+	for i, k := range ReactionKinds {
+		kinds[i] = k
+	}
+
+	return kinds
+}
+
+// Validate validates reaction's fields.
+func (r *Reaction) Validate() error {
+	return validation.ValidateStruct(
+		r,
+		validation.Field(&r.TargetType, validation.Required, validation.In(ReactionPost, ReactionComment)),
+		validation.Field(&r.TargetID, validation.Required),
+		validation.Field(&r.Kind, validation.Required, validation.In(reactionKinds()...)),
+		validation.Field(&r.UserID, validation.Required),
+	)
+}
+
+// Validate validates filter's fields.
+func (f *ReactionFilter) Validate() error {
+	return validation.ValidateStruct(
+		f,
+		validation.Field(&f.Kind, validation.In(reactionKinds()...)),
+	)
+}
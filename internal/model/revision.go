@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+const (
+	// DiffEqual is the operation of lines present in both versions.
+	DiffEqual = " "
+	// DiffInsert is the operation of lines present only in the newer version.
+	DiffInsert = "+"
+	// DiffDelete is the operation of lines present only in the older version.
+	DiffDelete = "-"
+)
+
+// PostRevision model represents a saved version of a post. Revisions are
+// numbered from 1 for every post.
+type PostRevision struct {
+	ID     int    `json:"id" xml:"id" gorm:"primaryKey"`
+	PostID int    `json:"postId" xml:"postId" gorm:"uniqueIndex:idx_post_revisions_rev"`
+	Rev    int    `json:"rev" xml:"rev" gorm:"uniqueIndex:idx_post_revisions_rev"`
+	Title  string `json:"title" xml:"title"`
+	Body   string `json:"body" xml:"body"`
+	UserID string `json:"userId" xml:"userId"`
+
+	CreatedAt time.Time `json:"createdAt" xml:"createdAt"`
+}
+
+// DiffLine is a line of a line-level diff.
+type DiffLine struct {
+	Op   string `json:"op" xml:"op"`
+	Text string `json:"text" xml:"text"`
+}
+
+// RevisionDiff is a post revision with the diff from it to the current
+// version of the post.
+type RevisionDiff struct {
+	Revision PostRevision `json:"revision" xml:"revision"`
+	Title    []DiffLine   `json:"title" xml:"title"`
+	Body     []DiffLine   `json:"body" xml:"body"`
+}
@@ -0,0 +1,70 @@
+package model
+
+import validation "github.com/go-ozzo/ozzo-validation"
+
+// Roles of users.
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Roles are all roles of users.
+var Roles = []string{RoleUser, RoleModerator, RoleAdmin}
+
+// Permission is a permission granted to roles beyond the ownership of posts
+// and comments.
+type Permission string
+
+// Permissions of roles.
+const (
+	// PermModerateComments allows editing, deleting and restoring any comment.
+	PermModerateComments Permission = "comments:moderate"
+	// PermModeratePosts allows editing, deleting and restoring any post.
+	PermModeratePosts Permission = "posts:moderate"
+	// PermManageRoles allows changing users' roles.
+	PermManageRoles Permission = "roles:manage"
+	// PermManageTaxonomy allows creating, editing and deleting tags and
+	// categories.
+	PermManageTaxonomy Permission = "taxonomy:manage"
+)
+
+// rolePermissions are permissions granted to roles.
+var rolePermissions = map[string][]Permission{
+	RoleModerator: {PermModerateComments, PermManageTaxonomy},
+	RoleAdmin:     {PermModerateComments, PermModeratePosts, PermManageRoles, PermManageTaxonomy},
+}
+
+// RoleCan returns whether the role has the permission.
+func RoleCan(role string, p Permission) bool {
+	for _, rp := range rolePermissions[role] {
+		if rp == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RoleChange is the request of changing user's role.
+type RoleChange struct {
+	Role string `json:"role" xml:"role"`
+}
+
+// Validate validates the role change.
+func (r *RoleChange) Validate() error {
+	return validation.ValidateStruct(
+		r,
+		validation.Field(&r.Role, validation.Required, validation.In(roles()...)),
+	)
+}
+
+// roles returns roles of users as a slice of interfaces.
+func roles() []interface{} {
+	rs := make([]interface{}, len(Roles))
+	for i, r := range Roles {
+		rs[i] = r
+	}
+
+	return rs
+}
@@ -0,0 +1,38 @@
+package model
+
+import validation "github.com/go-ozzo/ozzo-validation"
+
+const (
+	// SearchTypePost is the type of search results that are posts.
+	SearchTypePost = "post"
+	// SearchTypeComment is the type of search results that are comments.
+	SearchTypeComment = "comment"
+)
+
+// SearchQuery keeps parameters of a search request.
+type SearchQuery struct {
+	Q      string `json:"q" query:"q"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+// SearchResult represents a post or a comment found by search. Title and Body
+// are HTML escaped and have the matching words wrapped in <mark> tags.
+type SearchResult struct {
+	Type   string  `json:"type" xml:"type"`
+	ID     int     `json:"id" xml:"id"`
+	PostID int     `json:"postId" xml:"postId"`
+	Title  string  `json:"title,omitempty" xml:"title,omitempty"`
+	Body   string  `json:"body" xml:"body"`
+	Score  float64 `json:"score" xml:"score"`
+}
+
+// Validate validates search query's fields.
+func (q *SearchQuery) Validate() error {
+	return validation.ValidateStruct(
+		q,
+		validation.Field(&q.Q, validation.Required, validation.Length(1, 200)),
+		validation.Field(&q.Limit, validation.Required, validation.Min(1), validation.Max(MaxPageLimit)),
+		validation.Field(&q.Offset, validation.Min(0)),
+	)
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// Session model represents a sign-in of a user. Access tokens carry the
+// session ID, revoking the session makes its access and refresh tokens
+// invalid.
+type Session struct {
+	ID     string `gorm:"type:varchar(64);primaryKey"`
+	UserID string `gorm:"type:varchar(64);not null;index"`
+	Email  string `gorm:"type:varchar(255)"`
+	// Role is the user's current role, it's updated when the role changes so
+	// access tokens get it without signing in again.
+	Role string `gorm:"type:varchar(16);not null;default:user"`
+
+	// RefreshHash is SHA-256 of the current refresh token, refresh tokens are
+	// replaced on every refresh. ExpiresAt is when the refresh token expires.
+	RefreshHash string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt   time.Time  `gorm:"not null;index"`
+	RevokedAt   *time.Time `gorm:"index"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TokenPair is the pair of tokens issued for a session.
+type TokenPair struct {
+	AccessToken  string `json:"accessToken" xml:"accessToken"`
+	RefreshToken string `json:"refreshToken" xml:"refreshToken"`
+	TokenType    string `json:"tokenType" xml:"tokenType"`
+	// ExpiresIn is the number of seconds the access token is valid for.
+	ExpiresIn int64 `json:"expiresIn" xml:"expiresIn"`
+}
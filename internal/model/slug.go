@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// PostSlug model represents a URL slug of a post. Posts keep the slugs of
+// their earlier titles, so old URLs still lead to them.
+type PostSlug struct {
+	Slug   string `json:"slug" xml:"slug" gorm:"type:varchar(96);primaryKey"`
+	PostID int    `json:"postId" xml:"postId" gorm:"not null;index"`
+
+	CreatedAt time.Time `json:"createdAt" xml:"createdAt"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// Tag model represents a post tag.
+type Tag struct {
+	ID   int    `json:"id" xml:"id" gorm:"primaryKey"`
+	Name string `json:"name" xml:"name" gorm:"type:varchar(64);not null;uniqueIndex"`
+
+	CreatedAt time.Time `json:"createdAt" xml:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" xml:"updatedAt"`
+}
+
+// TagCount is the number of public posts tagged with a tag.
+type TagCount struct {
+	ID    int    `json:"id" xml:"id"`
+	Name  string `json:"name" xml:"name"`
+	Count int64  `json:"count" xml:"count"`
+}
+
+// Validate validates tag's fields.
+func (t *Tag) Validate() error {
+	return validation.ValidateStruct(
+		t,
+		validation.Field(&t.Name, validation.Required, validation.Length(1, 64)),
+	)
+}
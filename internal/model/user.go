@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+// User model represents a user signed in with an identity provider, ID is
+// unique across providers.
+type User struct {
+	ID    string `json:"id" xml:"id" gorm:"type:varchar(64);primaryKey"`
+	Email string `json:"-" xml:"-" gorm:"type:varchar(255);index"`
+	// EmailVerified is whether the provider verified that the email belongs
+	// to the user, unverified emails don't grant access to comments written
+	// with them.
+	EmailVerified bool `json:"-" xml:"-" gorm:"not null;default:false"`
+	// Role grants permissions over other users' posts and comments.
+	Role string `json:"role" xml:"role" gorm:"type:varchar(16);not null;default:user"`
+
+	// Name and AvatarURL are taken from the provider's profile on the first
+	// sign-in, users can change them later.
+	Name      string `json:"name" xml:"name" gorm:"type:varchar(128)"`
+	AvatarURL string `json:"avatarUrl" xml:"avatarUrl" gorm:"type:varchar(512)"`
+	Bio       string `json:"bio" xml:"bio" gorm:"type:text"`
+
+	CreatedAt time.Time `json:"createdAt" xml:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" xml:"updatedAt"`
+
+	// Posts and Comments are never loaded, the relations link users' posts
+	// and comments to them.
+	Posts    []Post    `json:"-" xml:"-" gorm:"foreignKey:UserID"`
+	Comments []Comment `json:"-" xml:"-" gorm:"foreignKey:UserID"`
+}
+
+// Validate validates user's profile fields.
+func (u *User) Validate() error {
+	return validation.ValidateStruct(
+		u,
+		validation.Field(&u.Name, validation.Required, validation.Length(1, 128)),
+		validation.Field(&u.AvatarURL, validation.Length(0, 512), is.URL),
+		validation.Field(&u.Bio, validation.Length(0, 2000)),
+	)
+}
+
+// ProfileUpdate is the request of updating user's profile, fields left out of
+// it keep their values.
+type ProfileUpdate struct {
+	Name      *string `json:"name,omitempty" xml:"name,omitempty"`
+	AvatarURL *string `json:"avatarUrl,omitempty" xml:"avatarUrl,omitempty"`
+	Bio       *string `json:"bio,omitempty" xml:"bio,omitempty"`
+}
@@ -0,0 +1,95 @@
+// Package patch applies JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902)
+// documents to models.
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch"
+)
+
+const (
+	// MIMEMergePatch is the media type of JSON Merge Patch documents.
+	MIMEMergePatch = "application/merge-patch+json"
+	// MIMEJSONPatch is the media type of JSON Patch documents.
+	MIMEJSONPatch = "application/json-patch+json"
+)
+
+var (
+	// ErrInvalid is thrown when the patch document is malformed.
+	ErrInvalid = errors.New("patch document is malformed")
+	// ErrConflict is thrown when the patch can't be applied to the current
+	// state, e.g. a JSON Patch test operation failed.
+	ErrConflict = errors.New("patch can't be applied")
+	// ErrUnprocessable is thrown when the patched document doesn't fit the
+	// model.
+	ErrUnprocessable = errors.New("patched document is invalid")
+)
+
+// MediaType returns the patch media type of the Content-Type header or an
+// empty string when it isn't a supported patch format.
+func MediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mt != MIMEMergePatch && mt != MIMEJSONPatch {
+		return ""
+	}
+
+	return mt
+}
+
+// StatusCode returns HTTP status code of the error returned by Apply.
+func StatusCode(err error) int {
+	switch err {
+	case ErrInvalid:
+		return http.StatusBadRequest
+	case ErrConflict:
+		return http.StatusConflict
+	case ErrUnprocessable:
+		return http.StatusUnprocessableEntity
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Apply applies the patch document of specific media type to the JSON form
+// of v, which must be a pointer, and stores the result in v.
+func Apply(mediaType string, patch []byte, v interface{}) error {
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	switch mediaType {
+	case MIMEMergePatch:
+		if !json.Valid(patch) {
+			return ErrInvalid
+		}
+		if doc, err = jsonpatch.MergePatch(doc, patch); err != nil {
+			return ErrInvalid
+		}
+	case MIMEJSONPatch:
+		p, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return ErrInvalid
+		}
+		if doc, err = p.Apply(doc); err != nil {
+			return ErrConflict
+		}
+	default:
+		return ErrInvalid
+	}
+
+	// Fields removed by the patch must end up zero instead of keeping their
+	// previous values.
+	rv := reflect.ValueOf(v).Elem()
+	rv.Set(reflect.Zero(rv.Type()))
+	if err := json.Unmarshal(doc, v); err != nil {
+		return ErrUnprocessable
+	}
+
+	return nil
+}
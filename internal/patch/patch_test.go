@@ -0,0 +1,89 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type doc struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Count int    `json:"count"`
+}
+
+func TestApply(t *testing.T) {
+	testcases := []struct {
+		name      string
+		mediaType string
+		patch     string
+		expDoc    doc
+		expError  error
+	}{
+		{
+			name:      "merge patch changes sent fields only",
+			mediaType: MIMEMergePatch,
+			patch:     `{"title":"New"}`,
+			expDoc:    doc{Title: "New", Body: "Body.", Count: 1},
+		},
+		{
+			name:      "merge patch removes null fields",
+			mediaType: MIMEMergePatch,
+			patch:     `{"body":null}`,
+			expDoc:    doc{Title: "Title", Count: 1},
+		},
+		{
+			name:      "malformed merge patch",
+			mediaType: MIMEMergePatch,
+			patch:     `{"title":`,
+			expDoc:    doc{Title: "Title", Body: "Body.", Count: 1},
+			expError:  ErrInvalid,
+		},
+		{
+			name:      "json patch is applied",
+			mediaType: MIMEJSONPatch,
+			patch:     `[{"op":"test","path":"/count","value":1},{"op":"replace","path":"/body","value":"New."}]`,
+			expDoc:    doc{Title: "Title", Body: "New.", Count: 1},
+		},
+		{
+			name:      "json patch test fails",
+			mediaType: MIMEJSONPatch,
+			patch:     `[{"op":"test","path":"/count","value":2},{"op":"replace","path":"/body","value":"New."}]`,
+			expDoc:    doc{Title: "Title", Body: "Body.", Count: 1},
+			expError:  ErrConflict,
+		},
+		{
+			name:      "malformed json patch",
+			mediaType: MIMEJSONPatch,
+			patch:     `{"op":"replace"}`,
+			expDoc:    doc{Title: "Title", Body: "Body.", Count: 1},
+			expError:  ErrInvalid,
+		},
+		{
+			name:      "patched document doesn't fit",
+			mediaType: MIMEMergePatch,
+			patch:     `{"count":"one"}`,
+			expError:  ErrUnprocessable,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := doc{Title: "Title", Body: "Body.", Count: 1}
+
+			err := Apply(tc.mediaType, []byte(tc.patch), &d)
+
+			assert.Equal(t, tc.expError, err)
+			if err != ErrUnprocessable {
+				assert.Equal(t, tc.expDoc, d)
+			}
+		})
+	}
+}
+
+func TestMediaType(t *testing.T) {
+	assert.Equal(t, MIMEMergePatch, MediaType("application/merge-patch+json; charset=UTF-8"))
+	assert.Equal(t, MIMEJSONPatch, MediaType("application/json-patch+json"))
+	assert.Equal(t, "", MediaType("application/json"))
+	assert.Equal(t, "", MediaType(""))
+}
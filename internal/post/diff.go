@@ -0,0 +1,105 @@
+package post
+
+import (
+	"strings"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// maxDiffCells bounds the size of the table diff builds, lines that differ
+// in texts too large for it are diffed as deleted and inserted as a whole.
+const maxDiffCells = 1 << 20
+
+// lines splits text into lines, empty text has no lines.
+func lines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(text, "\n")
+}
+
+// diff returns the line-level diff from old to new text built on the longest
+// common subsequence of their lines.
+func diff(old, new string) []model.DiffLine {
+	a, b := lines(old), lines(new)
+
+	// Lines around the edit are left out of the table.
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+
+	d := []model.DiffLine{}
+	for _, l := range a[:pre] {
+		d = append(d, model.DiffLine{Op: model.DiffEqual, Text: l})
+	}
+	d = append(d, diffLCS(a[pre:len(a)-suf], b[pre:len(b)-suf])...)
+	for _, l := range a[len(a)-suf:] {
+		d = append(d, model.DiffLine{Op: model.DiffEqual, Text: l})
+	}
+
+	return d
+}
+
+// diffLCS returns the diff from a to b lines, it matches lines of their
+// longest common subsequence unless the table is larger than maxDiffCells.
+func diffLCS(a, b []string) []model.DiffLine {
+	if len(a) > 0 && len(b) > maxDiffCells/len(a) {
+		return replace(a, b)
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	d := []model.DiffLine{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			d = append(d, model.DiffLine{Op: model.DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			d = append(d, model.DiffLine{Op: model.DiffDelete, Text: a[i]})
+			i++
+		default:
+			d = append(d, model.DiffLine{Op: model.DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+
+	return append(d, replace(a[i:], b[j:])...)
+}
+
+// replace returns the diff deleting a lines and inserting b lines.
+func replace(a, b []string) []model.DiffLine {
+	d := make([]model.DiffLine, 0, len(a)+len(b))
+	for _, l := range a {
+		d = append(d, model.DiffLine{Op: model.DiffDelete, Text: l})
+	}
+	for _, l := range b {
+		d = append(d, model.DiffLine{Op: model.DiffInsert, Text: l})
+	}
+
+	return d
+}
@@ -1,8 +1,20 @@
 package post
 
-import "errors"
+import "github.com/imarrche/nix-ed/internal/store"
 
 var (
 	// ErrNotFound is thrown when specified post was not found in database.
-	ErrNotFound = errors.New("specified post was not found")
+	ErrNotFound = store.New(store.ErrNotFound, "specified post was not found")
+	// ErrRevisionNotFound is thrown when specified post revision was not found
+	// in database.
+	ErrRevisionNotFound = store.New(store.ErrNotFound, "specified post revision was not found")
+	// ErrVersionMismatch is thrown when specified post was updated since the
+	// version being updated was read.
+	ErrVersionMismatch = store.New(store.ErrConflict, "specified post version is outdated")
+	// ErrNotPublished is thrown when a post that isn't published is archived.
+	ErrNotPublished = store.New(store.ErrConflict, "only published posts can be archived")
+	// ErrUnknownTag is thrown when post's tag doesn't exist.
+	ErrUnknownTag = store.New(store.ErrConstraint, "specified tag doesn't exist")
+	// ErrUnknownCategory is thrown when post's category doesn't exist.
+	ErrUnknownCategory = store.New(store.ErrConstraint, "specified category doesn't exist")
 )
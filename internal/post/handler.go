@@ -2,94 +2,100 @@ package post
 
 import (
 	"context"
-	"encoding/json"
+	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 
+	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/labstack/echo/v4"
 
 	"github.com/imarrche/nix-ed/internal/auth"
+	"github.com/imarrche/nix-ed/internal/etag"
 	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/patch"
+	"github.com/imarrche/nix-ed/internal/render"
 )
 
-type key int
-
-const (
-	uIDkey key = iota
-)
-
-type errResponse struct {
-	Title  string `json:"title" xml:"title"`
-	Body   string `json:"body" xml:"body"`
-	UserID string `json:"userId" xml:"userId"`
-}
-
 // Handler is http handler for post resource.
 type Handler struct {
 	ps Service
-	as auth.Service
+	rc ReactionCounter
 }
 
-// NewHandler creates and returns a new Handler instacne.
-func NewHandler(ps Service, as auth.Service) *Handler {
-	return &Handler{ps: ps, as: as}
+// NewHandler creates and returns a new Handler instacne, posts get reaction
+// counts from rc when it isn't nil.
+func NewHandler(ps Service, rc ReactionCounter) *Handler {
+	return &Handler{ps: ps, rc: rc}
 }
 
-// respond responds to request with XML or JSON.
-func respond(c echo.Context, code int, data interface{}) error {
-	if c.Request().Header.Get("Accept-Encoding") == "text/xml" {
-		return c.XML(code, data)
+// setPageHeaders sets the total count and the next page cursor headers.
+func setPageHeaders(c echo.Context, pi model.PageInfo) {
+	c.Response().Header().Set("X-Total-Count", strconv.FormatInt(pi.Total, 10))
+	if pi.NextCursor != "" {
+		c.Response().Header().Set("X-Next-Cursor", pi.NextCursor)
 	}
-
-	return c.JSON(code, data)
 }
 
-type authResponse struct {
-	Error map[string]interface{} `json:"error"`
-	ID    string                 `json:"id"`
-	Email string                 `json:"email"`
-}
-
-// Auth is middleware for user authentication.
-func (h *Handler) Auth(next echo.HandlerFunc) echo.HandlerFunc {
+// Visible is middleware that ensures that the post is visible to the user who
+// made a request, drafts and scheduled posts are visible only to their authors.
+func (h *Handler) Visible(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		data, err := h.as.GetUserInfo(c.Request().Header.Get("Authorization"))
+		pID, err := strconv.Atoi(c.Param("id"))
 		if err != nil {
-			return c.Redirect(http.StatusTemporaryRedirect, "/auth/google/sign-in")
+			return echo.NewHTTPError(http.StatusBadRequest, err)
 		}
-		udata := &authResponse{}
-		if err := json.Unmarshal(data, &udata); err != nil || udata.Error != nil {
-			return c.NoContent(http.StatusUnauthorized)
+
+		p, err := h.ps.GetByID(c.Request().Context(), pID)
+		if err == ErrNotFound {
+			return echo.NewHTTPError(http.StatusNotFound, err)
+		} else if err != nil {
+			return err
+		}
+
+		if !p.VisibleTo(auth.Viewer(c)) {
+			return echo.NewHTTPError(http.StatusNotFound)
 		}
 
-		r := c.Request()
-		r = r.WithContext(context.WithValue(r.Context(), uIDkey, udata.ID))
-		c.SetRequest(r)
 		return next(c)
 	}
 }
 
-// PostAuthor is middleware that ensures that post's author made a request.
+// PostAuthor is middleware that ensures that post's author or a user who
+// moderates posts made a request.
 func (h *Handler) PostAuthor(next echo.HandlerFunc) echo.HandlerFunc {
+	return h.author(h.ps.GetByID, next)
+}
+
+// DeletedPostAuthor is middleware that ensures that deleted post's author or
+// a user who moderates posts made a request.
+func (h *Handler) DeletedPostAuthor(next echo.HandlerFunc) echo.HandlerFunc {
+	return h.author(h.ps.GetDeletedByID, next)
+}
+
+// author returns middleware that ensures that the author of the post got by
+// get made a request.
+func (h *Handler) author(get func(context.Context, int) (model.Post, error), next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		uID, ok := c.Request().Context().Value(uIDkey).(string)
+		pr, ok := auth.PrincipalFrom(c)
 		if !ok {
-			return c.NoContent(http.StatusInternalServerError)
+			return echo.NewHTTPError(http.StatusInternalServerError)
 		}
 		pID, err := strconv.Atoi(c.Param("id"))
 		if err != nil {
-			return respond(c, http.StatusBadRequest, err)
+			return echo.NewHTTPError(http.StatusBadRequest, err)
 		}
 
-		p, err := h.ps.GetByID(pID)
+		p, err := get(c.Request().Context(), pID)
 		if err == ErrNotFound {
-			return c.NoContent(http.StatusNotFound)
+			return echo.NewHTTPError(http.StatusNotFound, err)
 		} else if err != nil {
-			return c.NoContent(http.StatusInternalServerError)
+			return err
 		}
 
-		if p.UserID != uID {
-			return c.NoContent(http.StatusForbidden)
+		if p.UserID != pr.ID && !pr.Can(model.PermModeratePosts) {
+			return echo.NewHTTPError(http.StatusForbidden)
 		}
 
 		return next(c)
@@ -98,21 +104,50 @@ func (h *Handler) PostAuthor(next echo.HandlerFunc) echo.HandlerFunc {
 
 // GetAll returns post list.
 // @Summary Show all posts
-// @Descriptions show all posts
+// @Descriptions show the page of posts
 // @Tags posts
 // @ID post-list
-// @Accept json
-// @Produce json,xml
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack,text/csv
+// @Param limit query int false "page size" default(20) maximum(100)
+// @Param offset query int false "number of posts to skip"
+// @Param cursor query string false "cursor returned by the previous page"
+// @Param sort query string false "sort field" Enums(id, title, created, updated)
+// @Param order query string false "sort order" Enums(asc, desc)
+// @Param updated_since query string false "only posts updated at or after the time" format(date-time)
+// @Param status query string false "post status, drafts and scheduled posts are listed only for their authors" Enums(draft, scheduled, published, archived)
+// @Param tag query []string false "tag names, repeated or comma separated" collectionFormat(multi)
+// @Param category query []string false "category names, repeated or comma separated" collectionFormat(multi)
+// @Param match query string false "whether posts must have all or any of the tags and categories" Enums(all, any) default(all)
 // @Success 200 {array} model.Post
-// @Failure 500 ""
+// @Header 200 {integer} X-Total-Count "total number of posts"
+// @Header 200 {string} X-Next-Cursor "cursor of the next page"
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
 // @Router /posts [get]
 func (h *Handler) GetAll(c echo.Context) error {
-	ps, err := h.ps.GetAll()
-	if err != nil {
-		return c.NoContent(http.StatusInternalServerError)
+	f := model.PostFilter{}
+	if err := c.Bind(&f); err != nil {
+		return err
+	}
+	f.Viewer = auth.Viewer(c)
+	pg := model.Page{Limit: model.DefaultPageLimit}
+	if err := c.Bind(&pg); err != nil {
+		return err
+	}
+
+	ps, pi, err := h.ps.GetAll(c.Request().Context(), f, pg)
+	if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+	if err := h.countReactions(c, ps); err != nil {
+		return err
 	}
 
-	return respond(c, http.StatusOK, ps)
+	setPageHeaders(c, pi)
+	return render.Respond(c, http.StatusOK, ps)
 }
 
 // Create creates a post.
@@ -120,30 +155,33 @@ func (h *Handler) GetAll(c echo.Context) error {
 // @Descriptions create a post
 // @Tags posts
 // @ID post-create
-// @Accept json
-// @Produce json,xml
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
 // @Param input body model.Post true "post data"
 // @Success 201 {object} model.Post
-// @Failure 400 {object} errResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
 // @Router /posts [post]
 func (h *Handler) Create(c echo.Context) error {
-	id, ok := c.Request().Context().Value(uIDkey).(string)
+	pr, ok := auth.PrincipalFrom(c)
 	if !ok {
-		return c.NoContent(http.StatusInternalServerError)
+		return echo.NewHTTPError(http.StatusInternalServerError)
 	}
 
 	p := model.Post{}
 	if err := c.Bind(&p); err != nil {
-		return respond(c, http.StatusBadRequest, err)
+		return err
 	}
-	p.UserID = id
+	p.UserID = pr.ID
 
-	p, err := h.ps.Create(p)
-	if err != nil {
-		return respond(c, http.StatusInternalServerError, err)
+	p, err := h.ps.Create(c.Request().Context(), p)
+	if err == ErrUnknownTag || err == ErrUnknownCategory {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err)
+	} else if err != nil {
+		return err
 	}
 
-	return respond(c, http.StatusCreated, p)
+	return render.Respond(c, http.StatusCreated, p)
 }
 
 // GetByID returns post detail.
@@ -151,88 +189,480 @@ func (h *Handler) Create(c echo.Context) error {
 // @Descriptions post detail
 // @Tags posts
 // @ID post-detail
-// @Accept json
-// @Produce json,xml
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
 // @Param id path int true "post id"
+// @Param If-None-Match header string false "ETag of the cached post"
 // @Success 200 {object} model.Post
-// @Failure 400 {object} errResponse
-// @Failure 404 ""
+// @Header 200 {string} ETag "post version"
+// @Success 304 ""
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
 // @Router /posts/{id} [get]
 func (h *Handler) GetByID(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return respond(c, http.StatusBadRequest, err)
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
-	p, err := h.ps.GetByID(id)
-	if err == ErrNotFound {
-		return c.NoContent(http.StatusNotFound)
+	p, err := h.ps.GetByID(c.Request().Context(), id)
+	if err == ErrNotFound || err == nil && !p.VisibleTo(auth.Viewer(c)) {
+		return echo.NewHTTPError(http.StatusNotFound, err)
 	} else if err != nil {
-		return respond(c, http.StatusInternalServerError, err)
+		return err
+	}
+
+	return h.respondPost(c, p)
+}
+
+// countReactions sets reaction counts of the posts, reactions of the user who
+// made a request are flagged.
+func (h *Handler) countReactions(c echo.Context, ps []model.Post) error {
+	if h.rc == nil || len(ps) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(ps))
+	for i, p := range ps {
+		ids[i] = p.ID
+	}
+	counts, err := h.rc.Count(c.Request().Context(), model.ReactionPost, ids, auth.Viewer(c))
+	if err != nil {
+		return err
+	}
+	for i := range ps {
+		ps[i].Reactions = counts[ps[i].ID]
+	}
+
+	return nil
+}
+
+// respondPost responds with the post with its reaction counts and its ETag, or
+// with no content when the client's cached version is current.
+func (h *Handler) respondPost(c echo.Context, p model.Post) error {
+	tag := etag.New(p.Version)
+	c.Response().Header().Set("ETag", tag)
+	if etag.Match(c.Request().Header.Get("If-None-Match"), tag, true) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	ps := []model.Post{p}
+	if err := h.countReactions(c, ps); err != nil {
+		return err
 	}
 
-	return respond(c, http.StatusOK, p)
+	return render.Respond(c, http.StatusOK, ps[0])
+}
+
+// GetBySlug returns post detail.
+// @Summary Post detail by slug
+// @Descriptions post detail, earlier slugs of the post redirect to the current one
+// @Tags posts
+// @ID post-detail-slug
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param slug path string true "post slug"
+// @Param If-None-Match header string false "ETag of the cached post"
+// @Success 200 {object} model.Post
+// @Header 200 {string} ETag "post version"
+// @Success 301 ""
+// @Header 301 {string} Location "URL of the post's current slug"
+// @Success 304 ""
+// @Failure 404 {object} problem.Problem
+// @Router /posts/by-slug/{slug} [get]
+func (h *Handler) GetBySlug(c echo.Context) error {
+	slug := c.Param("slug")
+
+	p, err := h.ps.GetBySlug(c.Request().Context(), slug)
+	if err == ErrNotFound || err == nil && !p.VisibleTo(auth.Viewer(c)) {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err != nil {
+		return err
+	}
+
+	if p.Slug != slug {
+		return c.Redirect(http.StatusMovedPermanently, "/api/posts/by-slug/"+url.PathEscape(p.Slug))
+	}
+
+	return h.respondPost(c, p)
 }
 
 // Update updates a post.
 // @Summary Post update
-// @Descriptions post update
+// @Descriptions post update, JSON Merge Patch and JSON Patch bodies change only the fields they contain
 // @Tags posts
 // @ID post-update
-// @Accept json
-// @Produce json,xml
+// @Accept json,xml,application/yaml,application/msgpack,application/merge-patch+json,application/json-patch+json
+// @Produce json,xml,application/yaml,application/msgpack
 // @Param id path int true "post id"
-// @Param input body model.Post true "post data"
+// @Param input body model.Post true "post data or patch"
+// @Param If-Match header string false "ETag of the post version being updated"
 // @Success 200 {object} model.Post
-// @Failure 400 {object} errResponse
-// @Failure 404 ""
+// @Header 200 {string} ETag "post version"
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 412 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
 // @Router /posts/{id} [patch]
 func (h *Handler) Update(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return respond(c, http.StatusBadRequest, err)
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
 	p := model.Post{}
-	if err := c.Bind(&p); err != nil {
-		return respond(c, http.StatusBadRequest, err)
+	mt := patch.MediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if mt == "" {
+		if err := c.Bind(&p); err != nil {
+			return err
+		}
 	}
 	p.ID = id
+	p.Version = 0
+
+	im := c.Request().Header.Get("If-Match")
+	if im != "" || mt != "" {
+		cur, err := h.ps.GetByID(c.Request().Context(), id)
+		if err == ErrNotFound {
+			return echo.NewHTTPError(http.StatusNotFound, err)
+		} else if err != nil {
+			return err
+		}
+		if im != "" && !etag.Match(im, etag.New(cur.Version), false) {
+			return echo.NewHTTPError(http.StatusPreconditionFailed)
+		}
 
-	p, err = h.ps.Update(p)
+		// Patches are applied to the current post, so fields missing in the
+		// patch keep their values.
+		if mt != "" {
+			doc, err := ioutil.ReadAll(c.Request().Body)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err)
+			}
+			p = cur
+			if err := patch.Apply(mt, doc, &p); err != nil {
+				return echo.NewHTTPError(patch.StatusCode(err), err)
+			}
+			p.ID = id
+		}
+		p.Version = cur.Version
+	}
+
+	p, err = h.ps.Update(c.Request().Context(), p)
 	if err == ErrNotFound {
-		return c.NoContent(http.StatusNotFound)
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err == ErrVersionMismatch && im == "" {
+		return echo.NewHTTPError(http.StatusConflict, err)
+	} else if err == ErrVersionMismatch {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, err)
+	} else if err == ErrUnknownTag || err == ErrUnknownCategory {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err)
+	} else if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	} else if err != nil {
-		return respond(c, http.StatusInternalServerError, err)
+		return err
 	}
 
-	return respond(c, http.StatusOK, p)
+	c.Response().Header().Set("ETag", etag.New(p.Version))
+	return render.Respond(c, http.StatusOK, p)
 }
 
 // DeleteByID deletes a post.
 // @Summary Post delete
-// @Descriptions post delete
+// @Descriptions move a post and its comments to trash
 // @Tags posts
 // @ID posts-delete
-// @Accept json
-// @Produce json,xml
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
 // @Param id path int true "post id"
 // @Success 204 ""
-// @Failure 400 {object} errResponse
-// @Failure 404 ""
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
 // @Router /posts/{id} [delete]
 func (h *Handler) DeleteByID(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return respond(c, http.StatusBadRequest, err)
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
-	err = h.ps.DeleteByID(id)
+	err = h.ps.DeleteByID(c.Request().Context(), id)
 	if err == ErrNotFound {
-		return c.NoContent(http.StatusNotFound)
+		return echo.NewHTTPError(http.StatusNotFound, err)
 	} else if err != nil {
-		return respond(c, http.StatusInternalServerError, err)
+		return err
 	}
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// GetTrash returns user's deleted post list.
+// @Summary Show user's deleted posts
+// @Descriptions show the page of posts user moved to trash
+// @Tags posts
+// @ID post-trash
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack,text/csv
+// @Param limit query int false "page size" default(20) maximum(100)
+// @Param offset query int false "number of posts to skip"
+// @Param cursor query string false "cursor returned by the previous page"
+// @Param sort query string false "sort field" Enums(id, title, created, updated)
+// @Param order query string false "sort order" Enums(asc, desc)
+// @Success 200 {array} model.Post
+// @Header 200 {integer} X-Total-Count "total number of deleted posts"
+// @Header 200 {string} X-Next-Cursor "cursor of the next page"
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /posts/trash [get]
+func (h *Handler) GetTrash(c echo.Context) error {
+	pr, ok := auth.PrincipalFrom(c)
+	if !ok {
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	pg := model.Page{Limit: model.DefaultPageLimit}
+	if err := c.Bind(&pg); err != nil {
+		return err
+	}
+
+	ps, pi, err := h.ps.GetTrash(c.Request().Context(), pr.ID, pg)
+	if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+
+	setPageHeaders(c, pi)
+	return render.Respond(c, http.StatusOK, ps)
+}
+
+// Restore restores a deleted post.
+// @Summary Post restore
+// @Descriptions restore a post and its comments from trash
+// @Tags posts
+// @ID post-restore
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "post id"
+// @Success 200 {object} model.Post
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /posts/{id}/restore [post]
+func (h *Handler) Restore(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	p, err := h.ps.Restore(c.Request().Context(), id)
+	if err == ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusOK, p)
+}
+
+// GetRevisions returns post's revision list.
+// @Summary Show post revisions
+// @Descriptions show the page of post's revisions
+// @Tags posts
+// @ID post-revision-list
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack,text/csv
+// @Param id path int true "post id"
+// @Param limit query int false "page size" default(20) maximum(100)
+// @Param offset query int false "number of revisions to skip"
+// @Param cursor query string false "cursor returned by the previous page"
+// @Param sort query string false "sort field" Enums(rev)
+// @Param order query string false "sort order" Enums(asc, desc)
+// @Success 200 {array} model.PostRevision
+// @Header 200 {integer} X-Total-Count "total number of revisions"
+// @Header 200 {string} X-Next-Cursor "cursor of the next page"
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /posts/{id}/revisions [get]
+func (h *Handler) GetRevisions(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	pg := model.Page{Limit: model.DefaultPageLimit}
+	if err := c.Bind(&pg); err != nil {
+		return err
+	}
+
+	rs, pi, err := h.ps.GetRevisions(c.Request().Context(), id, pg)
+	if err == ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+
+	setPageHeaders(c, pi)
+	return render.Respond(c, http.StatusOK, rs)
+}
+
+// GetRevision returns post's revision detail.
+// @Summary Post revision detail
+// @Descriptions show post's revision and the line diff from it to the current version
+// @Tags posts
+// @ID post-revision-get
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "post id"
+// @Param rev path int true "revision number"
+// @Success 200 {object} model.RevisionDiff
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /posts/{id}/revisions/{rev} [get]
+func (h *Handler) GetRevision(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	d, err := h.ps.GetRevision(c.Request().Context(), id, rev)
+	if err == ErrNotFound || err == ErrRevisionNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusOK, d)
+}
+
+// RestoreRevision restores post's revision.
+// @Summary Post revision restore
+// @Descriptions update a post to its earlier revision, saved as a new revision
+// @Tags posts
+// @ID post-revision-restore
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "post id"
+// @Param rev path int true "revision number"
+// @Success 200 {object} model.Post
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /posts/{id}/revisions/{rev}/restore [post]
+func (h *Handler) RestoreRevision(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	p, err := h.ps.RestoreRevision(c.Request().Context(), id, rev)
+	if err == ErrNotFound || err == ErrRevisionNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusOK, p)
+}
+
+type publishRequest struct {
+	PublishAt *time.Time `json:"publishAt" xml:"publishAt"`
+}
+
+// Publish publishes a post.
+// @Summary Post publish
+// @Descriptions publish a post now or schedule it, posts are published immediately when publishAt is missing or has passed
+// @Tags posts
+// @ID post-publish
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "post id"
+// @Param input body publishRequest false "publication time"
+// @Success 200 {object} model.Post
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Router /posts/{id}/publish [post]
+func (h *Handler) Publish(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	pr := publishRequest{}
+	if err := c.Bind(&pr); err != nil {
+		return err
+	}
+
+	return h.setStatus(c, func() (model.Post, error) { return h.ps.Publish(c.Request().Context(), id, pr.PublishAt) })
+}
+
+// Unpublish unpublishes a post.
+// @Summary Post unpublish
+// @Descriptions turn a post into a draft
+// @Tags posts
+// @ID post-unpublish
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "post id"
+// @Success 200 {object} model.Post
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Router /posts/{id}/unpublish [post]
+func (h *Handler) Unpublish(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	return h.setStatus(c, func() (model.Post, error) { return h.ps.Unpublish(c.Request().Context(), id) })
+}
+
+// Archive archives a post.
+// @Summary Post archive
+// @Descriptions archive a published post, archived posts stay visible
+// @Tags posts
+// @ID post-archive
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "post id"
+// @Success 200 {object} model.Post
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Router /posts/{id}/archive [post]
+func (h *Handler) Archive(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	return h.setStatus(c, func() (model.Post, error) { return h.ps.Archive(c.Request().Context(), id) })
+}
+
+// setStatus responds with the post which status was changed by set.
+func (h *Handler) setStatus(c echo.Context, set func() (model.Post, error)) error {
+	p, err := set()
+	if err == ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err == ErrVersionMismatch || err == ErrNotPublished {
+		return echo.NewHTTPError(http.StatusConflict, err)
+	} else if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("ETag", etag.New(p.Version))
+	return render.Respond(c, http.StatusOK, p)
+}
@@ -2,76 +2,28 @@ package post
 
 import (
 	"bytes"
-	"context"
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/golang/mock/gomock"
+	"github.com/imarrche/nix-ed/internal/handlertest"
 	"github.com/imarrche/nix-ed/internal/model"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 
-	mockauth "github.com/imarrche/nix-ed/internal/auth/mock"
+	"github.com/imarrche/nix-ed/internal/auth"
+	"github.com/imarrche/nix-ed/internal/patch"
 	mockpost "github.com/imarrche/nix-ed/internal/post/mock"
+	"github.com/imarrche/nix-ed/internal/store"
 )
 
-func TestHandler_Auth(t *testing.T) {
-	next := func(c echo.Context) error {
-		return c.NoContent(http.StatusOK)
-	}
-
-	testcases := []struct {
-		name    string
-		mock    func(*mockauth.MockService)
-		expCode int
-	}{
-		{
-			name: "user is authenticated",
-			mock: func(s *mockauth.MockService) {
-				data := []byte(`{"id":"1","email":"u@t.com"}`)
-				s.EXPECT().GetUserInfo("token").Return(data, nil)
-			},
-			expCode: http.StatusOK,
-		},
-		{
-			name: "get user info error",
-			mock: func(s *mockauth.MockService) {
-				s.EXPECT().GetUserInfo("token").Return(nil, errors.New("internal error"))
-			},
-			expCode: http.StatusTemporaryRedirect,
-		},
-		{
-			name: "invalid auth data",
-			mock: func(s *mockauth.MockService) {
-				data := []byte(`{"id":1,"email":"u@t.com"}`)
-				s.EXPECT().GetUserInfo("token").Return(data, nil)
-			},
-			expCode: http.StatusUnauthorized,
-		},
-	}
-
-	for _, tc := range testcases {
-		c := gomock.NewController(t)
-		defer c.Finish()
-		as := mockauth.NewMockService(c)
-		tc.mock(as)
-		w := httptest.NewRecorder()
-		r := httptest.NewRequest(http.MethodGet, "/posts", nil)
-		r.Header.Add("Authorization", "token")
-
-		ctx := echo.New().NewContext(r, w)
-
-		hf := NewHandler(nil, as).Auth(next)
-		hf(ctx)
-
-		assert.Equal(t, tc.expCode, w.Code)
-
-	}
-}
-
 func TestHandler_PostAuthor(t *testing.T) {
 	next := func(c echo.Context) error {
 		return c.NoContent(http.StatusOK)
@@ -81,12 +33,13 @@ func TestHandler_PostAuthor(t *testing.T) {
 		name    string
 		mock    func(*mockpost.MockService, model.Post)
 		post    model.Post
+		role    string
 		expCode int
 	}{
 		{
 			name: "user is post author",
 			mock: func(s *mockpost.MockService, p model.Post) {
-				s.EXPECT().GetByID(p.ID).Return(p, nil)
+				s.EXPECT().GetByID(gomock.Any(), p.ID).Return(p, nil)
 			},
 			post:    model.Post{ID: 1, Title: "Post 1", UserID: "1"},
 			expCode: http.StatusOK,
@@ -94,7 +47,7 @@ func TestHandler_PostAuthor(t *testing.T) {
 		{
 			name: "post not found",
 			mock: func(s *mockpost.MockService, p model.Post) {
-				s.EXPECT().GetByID(p.ID).Return(model.Post{}, ErrNotFound)
+				s.EXPECT().GetByID(gomock.Any(), p.ID).Return(model.Post{}, ErrNotFound)
 			},
 			post:    model.Post{ID: 1, Title: "Post 1", UserID: "1"},
 			expCode: http.StatusNotFound,
@@ -102,7 +55,7 @@ func TestHandler_PostAuthor(t *testing.T) {
 		{
 			name: "internal error",
 			mock: func(s *mockpost.MockService, p model.Post) {
-				s.EXPECT().GetByID(p.ID).Return(model.Post{}, errors.New("internal error"))
+				s.EXPECT().GetByID(gomock.Any(), p.ID).Return(model.Post{}, errors.New("internal error"))
 			},
 			post:    model.Post{ID: 1, Title: "Post 1", UserID: "1"},
 			expCode: http.StatusInternalServerError,
@@ -110,11 +63,29 @@ func TestHandler_PostAuthor(t *testing.T) {
 		{
 			name: "user is not a post author",
 			mock: func(s *mockpost.MockService, p model.Post) {
-				s.EXPECT().GetByID(p.ID).Return(p, nil)
+				s.EXPECT().GetByID(gomock.Any(), p.ID).Return(p, nil)
+			},
+			post:    model.Post{ID: 1, Title: "Post 1", UserID: "2"},
+			expCode: http.StatusForbidden,
+		},
+		{
+			name: "moderator is not a post author",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				s.EXPECT().GetByID(gomock.Any(), p.ID).Return(p, nil)
 			},
 			post:    model.Post{ID: 1, Title: "Post 1", UserID: "2"},
+			role:    model.RoleModerator,
 			expCode: http.StatusForbidden,
 		},
+		{
+			name: "admin is not a post author",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				s.EXPECT().GetByID(gomock.Any(), p.ID).Return(p, nil)
+			},
+			post:    model.Post{ID: 1, Title: "Post 1", UserID: "2"},
+			role:    model.RoleAdmin,
+			expCode: http.StatusOK,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -124,14 +95,18 @@ func TestHandler_PostAuthor(t *testing.T) {
 		tc.mock(ps, tc.post)
 		w := httptest.NewRecorder()
 		r := httptest.NewRequest(http.MethodPatch, "/posts/1", nil)
-		r = r.WithContext(context.WithValue(r.Context(), uIDkey, "1"))
+		pr := auth.Principal{ID: "1"}
+		if tc.role != "" {
+			pr.Roles = []string{tc.role}
+		}
+		r = r.WithContext(auth.WithPrincipal(r.Context(), pr))
 
 		ctx := echo.New().NewContext(r, w)
 		ctx.SetParamNames("id")
 		ctx.SetParamValues("1")
 
 		hf := NewHandler(ps, nil).PostAuthor(next)
-		hf(ctx)
+		handlertest.Serve(ctx, hf)
 
 		assert.Equal(t, tc.expCode, w.Code)
 
@@ -140,25 +115,92 @@ func TestHandler_PostAuthor(t *testing.T) {
 
 func TestHandler_GetAll(t *testing.T) {
 	testcases := []struct {
-		name     string
-		mock     func(*mockpost.MockService, []model.Post)
-		posts    []model.Post
-		expPosts []model.Post
-		expCode  int
+		name          string
+		mock          func(*mockpost.MockService, []model.Post)
+		query         string
+		posts         []model.Post
+		expPosts      []model.Post
+		expTotal      string
+		expNextCursor string
+		expCode       int
 	}{
 		{
 			name: "posts are retrieved",
 			mock: func(s *mockpost.MockService, ps []model.Post) {
-				s.EXPECT().GetAll().Return(ps, nil)
+				pg := model.Page{Limit: model.DefaultPageLimit}
+				s.EXPECT().GetAll(gomock.Any(), model.PostFilter{}, pg).Return(ps, model.PageInfo{Total: 2}, nil)
 			},
 			posts:    []model.Post{{Title: "Post1"}, {Title: "Post2"}},
 			expPosts: []model.Post{{Title: "Post1"}, {Title: "Post2"}},
+			expTotal: "2",
+			expCode:  http.StatusOK,
+		},
+		{
+			name: "next page is retrieved by cursor",
+			mock: func(s *mockpost.MockService, ps []model.Post) {
+				pg := model.Page{Limit: 1, Cursor: "c1", Sort: "title"}
+				s.EXPECT().GetAll(gomock.Any(), model.PostFilter{}, pg).Return(ps, model.PageInfo{Total: 3, NextCursor: "c2"}, nil)
+			},
+			query:         "?limit=1&cursor=c1&sort=title",
+			posts:         []model.Post{{Title: "Post2"}},
+			expPosts:      []model.Post{{Title: "Post2"}},
+			expTotal:      "3",
+			expNextCursor: "c2",
+			expCode:       http.StatusOK,
+		},
+		{
+			name: "updated posts are retrieved",
+			mock: func(s *mockpost.MockService, ps []model.Post) {
+				f := model.PostFilter{UpdatedSince: time.Date(2021, 2, 1, 10, 0, 0, 0, time.UTC)}
+				pg := model.Page{Limit: model.DefaultPageLimit, Sort: "updated"}
+				s.EXPECT().GetAll(gomock.Any(), f, pg).Return(ps, model.PageInfo{Total: 1}, nil)
+			},
+			query:    "?updated_since=2021-02-01T10:00:00Z&sort=updated",
+			posts:    []model.Post{{Title: "Post2"}},
+			expPosts: []model.Post{{Title: "Post2"}},
+			expTotal: "1",
+			expCode:  http.StatusOK,
+		},
+		{
+			name: "tagged posts are retrieved",
+			mock: func(s *mockpost.MockService, ps []model.Post) {
+				f := model.PostFilter{Tags: []string{"go", "sql"}, Categories: []string{"backend"}, Match: model.MatchAny}
+				pg := model.Page{Limit: model.DefaultPageLimit}
+				s.EXPECT().GetAll(gomock.Any(), f, pg).Return(ps, model.PageInfo{Total: 1}, nil)
+			},
+			query:    "?tag=go&tag=sql&category=backend&match=any",
+			posts:    []model.Post{{Title: "Post1"}},
+			expPosts: []model.Post{{Title: "Post1"}},
+			expTotal: "1",
 			expCode:  http.StatusOK,
 		},
+		{
+			name:    "invalid query",
+			mock:    func(s *mockpost.MockService, ps []model.Post) {},
+			query:   "?offset=first",
+			expCode: http.StatusBadRequest,
+		},
+		{
+			name:    "invalid updated since",
+			mock:    func(s *mockpost.MockService, ps []model.Post) {},
+			query:   "?updated_since=yesterday",
+			expCode: http.StatusBadRequest,
+		},
+		{
+			name: "validation errors",
+			mock: func(s *mockpost.MockService, ps []model.Post) {
+				pg := model.Page{Limit: model.DefaultPageLimit, Order: "up"}
+				err := validation.Errors{"order": errors.New("must be a valid value")}
+				s.EXPECT().GetAll(gomock.Any(), model.PostFilter{}, pg).Return(nil, model.PageInfo{}, err)
+			},
+			query:   "?order=up",
+			expCode: http.StatusBadRequest,
+		},
 		{
 			name: "internal error",
 			mock: func(s *mockpost.MockService, ps []model.Post) {
-				s.EXPECT().GetAll().Return(nil, errors.New("internal error"))
+				pg := model.Page{Limit: model.DefaultPageLimit}
+				s.EXPECT().GetAll(gomock.Any(), model.PostFilter{}, pg).Return(nil, model.PageInfo{}, errors.New("internal error"))
 			},
 			posts:   []model.Post{{Title: "Post1"}, {Title: "Post2"}},
 			expCode: http.StatusInternalServerError,
@@ -169,19 +211,20 @@ func TestHandler_GetAll(t *testing.T) {
 		c := gomock.NewController(t)
 		defer c.Finish()
 		ps := mockpost.NewMockService(c)
-		as := mockauth.NewMockService(c)
 		tc.mock(ps, tc.posts)
 		w := httptest.NewRecorder()
-		r := httptest.NewRequest(http.MethodGet, "/posts", nil)
+		r := httptest.NewRequest(http.MethodGet, "/posts"+tc.query, nil)
 
 		ctx := echo.New().NewContext(r, w)
 
-		NewHandler(ps, as).GetAll(ctx)
+		handlertest.Serve(ctx, NewHandler(ps, nil).GetAll)
 
 		var posts []model.Post
 		json.NewDecoder(w.Body).Decode(&posts)
 
 		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expTotal, w.Header().Get("X-Total-Count"))
+		assert.Equal(t, tc.expNextCursor, w.Header().Get("X-Next-Cursor"))
 		assert.Equal(t, tc.expPosts, posts)
 	}
 }
@@ -197,16 +240,24 @@ func TestHandler_Create(t *testing.T) {
 		{
 			name: "post is created",
 			mock: func(s *mockpost.MockService, p model.Post) {
-				s.EXPECT().Create(p).Return(p, nil)
+				s.EXPECT().Create(gomock.Any(), p).Return(p, nil)
 			},
 			post:    model.Post{Title: "Post 1", UserID: "1"},
 			expPost: model.Post{Title: "Post 1", UserID: "1"},
 			expCode: http.StatusCreated,
 		},
+		{
+			name: "unknown tag",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				s.EXPECT().Create(gomock.Any(), p).Return(model.Post{}, ErrUnknownTag)
+			},
+			post:    model.Post{Title: "Post 1", UserID: "1", Tags: []model.Tag{{Name: "go"}}},
+			expCode: http.StatusUnprocessableEntity,
+		},
 		{
 			name: "post creating error",
 			mock: func(s *mockpost.MockService, p model.Post) {
-				s.EXPECT().Create(p).Return(model.Post{}, errors.New("internal error"))
+				s.EXPECT().Create(gomock.Any(), p).Return(model.Post{}, errors.New("internal error"))
 			},
 			post:    model.Post{Title: "Post 1", UserID: "1"},
 			expCode: http.StatusInternalServerError,
@@ -217,22 +268,23 @@ func TestHandler_Create(t *testing.T) {
 		c := gomock.NewController(t)
 		defer c.Finish()
 		ps := mockpost.NewMockService(c)
-		as := mockauth.NewMockService(c)
 		tc.mock(ps, tc.post)
 		w := httptest.NewRecorder()
 
 		b := &bytes.Buffer{}
 		json.NewEncoder(b).Encode(tc.post)
 		r := httptest.NewRequest(http.MethodPost, "/posts", b)
-		r = r.WithContext(context.WithValue(r.Context(), uIDkey, tc.post.UserID))
+		r = r.WithContext(auth.WithPrincipal(r.Context(), auth.Principal{ID: tc.post.UserID}))
 		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
 		ctx := echo.New().NewContext(r, w)
 
-		NewHandler(ps, as).Create(ctx)
+		handlertest.Serve(ctx, NewHandler(ps, nil).Create)
 
 		var p model.Post
-		json.NewDecoder(w.Body).Decode(&p)
+		if w.Code < http.StatusBadRequest {
+			json.NewDecoder(w.Body).Decode(&p)
+		}
 
 		assert.Equal(t, tc.expCode, w.Code)
 		assert.Equal(t, tc.expPost, p)
@@ -245,54 +297,213 @@ func TestHandler_GetByID(t *testing.T) {
 		mock    func(*mockpost.MockService, model.Post)
 		post    model.Post
 		expPost model.Post
+		header  string
 		expCode int
 	}{
 		{
 			name: "post is retrieved",
 			mock: func(s *mockpost.MockService, p model.Post) {
-				s.EXPECT().GetByID(p.ID).Return(p, nil)
+				s.EXPECT().GetByID(gomock.Any(), p.ID).Return(p, nil)
 			},
-			post:    model.Post{ID: 1, Title: "Post1"},
-			expPost: model.Post{ID: 1, Title: "Post1"},
+			post:    model.Post{ID: 1, Title: "Post1", Status: model.PostPublished},
+			expPost: model.Post{ID: 1, Title: "Post1", Status: model.PostPublished},
 			expCode: http.StatusOK,
 		},
 		{
 			name: "post is not found",
 			mock: func(s *mockpost.MockService, p model.Post) {
-				s.EXPECT().GetByID(p.ID).Return(model.Post{}, ErrNotFound)
+				s.EXPECT().GetByID(gomock.Any(), p.ID).Return(model.Post{}, ErrNotFound)
 			},
-			post:    model.Post{ID: 1, Title: "Post1"},
+			post:    model.Post{ID: 1, Title: "Post1", Status: model.PostPublished},
 			expCode: http.StatusNotFound,
 		},
 		{
 			name: "internal error",
 			mock: func(s *mockpost.MockService, p model.Post) {
-				s.EXPECT().GetByID(p.ID).Return(model.Post{}, errors.New("internal error"))
+				s.EXPECT().GetByID(gomock.Any(), p.ID).Return(model.Post{}, errors.New("internal error"))
 			},
-			post:    model.Post{ID: 1, Title: "Post1"},
+			post:    model.Post{ID: 1, Title: "Post1", Status: model.PostPublished},
 			expCode: http.StatusInternalServerError,
 		},
+		{
+			name: "database is unavailable",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				s.EXPECT().GetByID(gomock.Any(), p.ID).Return(model.Post{}, store.Wrap(driver.ErrBadConn))
+			},
+			post:    model.Post{ID: 1, Title: "Post1", Status: model.PostPublished},
+			expCode: http.StatusServiceUnavailable,
+		},
+		{
+			name: "post is not modified",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				s.EXPECT().GetByID(gomock.Any(), p.ID).Return(p, nil)
+			},
+			post:    model.Post{ID: 1, Title: "Post1", Status: model.PostPublished, Version: 2},
+			header:  `"1", "2"`,
+			expCode: http.StatusNotModified,
+		},
+		{
+			name: "draft is hidden",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				s.EXPECT().GetByID(gomock.Any(), p.ID).Return(p, nil)
+			},
+			post:    model.Post{ID: 1, Title: "Post1", UserID: "1", Status: model.PostDraft},
+			expCode: http.StatusNotFound,
+		},
 	}
 
 	for _, tc := range testcases {
 		c := gomock.NewController(t)
 		defer c.Finish()
 		ps := mockpost.NewMockService(c)
-		as := mockauth.NewMockService(c)
 		tc.mock(ps, tc.post)
 		w := httptest.NewRecorder()
 		r := httptest.NewRequest(http.MethodGet, "/posts/1", nil)
+		if tc.header != "" {
+			r.Header.Set("If-None-Match", tc.header)
+		}
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, NewHandler(ps, nil).GetByID)
+
+		var post model.Post
+		if w.Code < http.StatusBadRequest {
+			json.NewDecoder(w.Body).Decode(&post)
+		}
+
+		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expPost, post)
+	}
+}
+
+func TestHandler_GetByID_Reactions(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mock    func(*mockpost.MockReactionCounter)
+		expPost model.Post
+		expCode int
+	}{
+		{
+			name: "reactions are counted",
+			mock: func(rc *mockpost.MockReactionCounter) {
+				counts := map[int][]model.ReactionCount{1: {{Kind: "like", Count: 2, ReactedByMe: true}}}
+				rc.EXPECT().Count(gomock.Any(), model.ReactionPost, []int{1}, "2").Return(counts, nil)
+			},
+			expPost: model.Post{
+				ID: 1, Status: model.PostPublished,
+				Reactions: []model.ReactionCount{{Kind: "like", Count: 2, ReactedByMe: true}},
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "internal error",
+			mock: func(rc *mockpost.MockReactionCounter) {
+				rc.EXPECT().Count(gomock.Any(), model.ReactionPost, []int{1}, "2").Return(nil, errors.New("internal error"))
+			},
+			expCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ps := mockpost.NewMockService(c)
+		ps.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostPublished}, nil)
+		rc := mockpost.NewMockReactionCounter(c)
+		tc.mock(rc)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/posts/1", nil)
+		r = r.WithContext(auth.WithPrincipal(r.Context(), auth.Principal{ID: "2"}))
 
 		ctx := echo.New().NewContext(r, w)
 		ctx.SetParamNames("id")
 		ctx.SetParamValues("1")
 
-		NewHandler(ps, as).GetByID(ctx)
+		handlertest.Serve(ctx, NewHandler(ps, rc).GetByID)
+
+		var post model.Post
+		if w.Code < http.StatusBadRequest {
+			json.NewDecoder(w.Body).Decode(&post)
+		}
+
+		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expPost, post)
+	}
+}
+
+func TestHandler_GetBySlug(t *testing.T) {
+	testcases := []struct {
+		name        string
+		mock        func(*mockpost.MockService, model.Post)
+		slug        string
+		post        model.Post
+		expPost     model.Post
+		expLocation string
+		expCode     int
+	}{
+		{
+			name: "post is retrieved",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				s.EXPECT().GetBySlug(gomock.Any(), "post-1").Return(p, nil)
+			},
+			slug:    "post-1",
+			post:    model.Post{ID: 1, Title: "Post 1", Slug: "post-1", Status: model.PostPublished},
+			expPost: model.Post{ID: 1, Title: "Post 1", Slug: "post-1", Status: model.PostPublished},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "old slug is redirected",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				s.EXPECT().GetBySlug(gomock.Any(), "post-1").Return(p, nil)
+			},
+			slug:        "post-1",
+			post:        model.Post{ID: 1, Title: "Post 2", Slug: "post-2", Status: model.PostPublished},
+			expLocation: "/api/posts/by-slug/post-2",
+			expCode:     http.StatusMovedPermanently,
+		},
+		{
+			name: "post is not found",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				s.EXPECT().GetBySlug(gomock.Any(), "post-1").Return(model.Post{}, ErrNotFound)
+			},
+			slug:    "post-1",
+			expCode: http.StatusNotFound,
+		},
+		{
+			name: "draft is hidden",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				s.EXPECT().GetBySlug(gomock.Any(), "post-1").Return(p, nil)
+			},
+			slug:    "post-1",
+			post:    model.Post{ID: 1, Title: "Post 1", Slug: "post-1", UserID: "1", Status: model.PostDraft},
+			expCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ps := mockpost.NewMockService(c)
+		tc.mock(ps, tc.post)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/posts/by-slug/"+tc.slug, nil)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("slug")
+		ctx.SetParamValues(tc.slug)
+
+		handlertest.Serve(ctx, NewHandler(ps, nil).GetBySlug)
 
 		var post model.Post
-		json.NewDecoder(w.Body).Decode(&post)
+		if w.Code < http.StatusBadRequest {
+			json.NewDecoder(w.Body).Decode(&post)
+		}
 
 		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expLocation, w.Header().Get("Location"))
 		assert.Equal(t, tc.expPost, post)
 	}
 }
@@ -303,12 +514,13 @@ func TestHandler_Update(t *testing.T) {
 		mock    func(*mockpost.MockService, model.Post)
 		post    model.Post
 		expPost model.Post
+		header  string
 		expCode int
 	}{
 		{
 			name: "post is updated",
 			mock: func(s *mockpost.MockService, p model.Post) {
-				s.EXPECT().Update(p).Return(p, nil)
+				s.EXPECT().Update(gomock.Any(), p).Return(p, nil)
 			},
 			post:    model.Post{ID: 1, Title: "Post1"},
 			expPost: model.Post{ID: 1, Title: "Post1"},
@@ -317,7 +529,7 @@ func TestHandler_Update(t *testing.T) {
 		{
 			name: "post is not found",
 			mock: func(s *mockpost.MockService, p model.Post) {
-				s.EXPECT().Update(p).Return(model.Post{}, ErrNotFound)
+				s.EXPECT().Update(gomock.Any(), p).Return(model.Post{}, ErrNotFound)
 			},
 			post:    model.Post{ID: 1, Title: "Post1"},
 			expCode: http.StatusNotFound,
@@ -325,39 +537,183 @@ func TestHandler_Update(t *testing.T) {
 		{
 			name: "internal error",
 			mock: func(s *mockpost.MockService, p model.Post) {
-				s.EXPECT().Update(p).Return(model.Post{}, errors.New("internal error"))
+				s.EXPECT().Update(gomock.Any(), p).Return(model.Post{}, errors.New("internal error"))
 			},
 			post:    model.Post{ID: 1, Title: "Post1"},
 			expCode: http.StatusInternalServerError,
 		},
+		{
+			name: "post version matches",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				p.Version = 2
+				s.EXPECT().GetByID(gomock.Any(), p.ID).Return(p, nil)
+				s.EXPECT().Update(gomock.Any(), p).Return(p, nil)
+			},
+			post:    model.Post{ID: 1, Title: "Post1"},
+			header:  `"2"`,
+			expPost: model.Post{ID: 1, Title: "Post1", Version: 2},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "post version doesn't match",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				s.EXPECT().GetByID(gomock.Any(), p.ID).Return(model.Post{ID: 1, Version: 3}, nil)
+			},
+			post:    model.Post{ID: 1, Title: "Post1"},
+			header:  `"2"`,
+			expCode: http.StatusPreconditionFailed,
+		},
+		{
+			name: "post is updated concurrently",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				p.Version = 2
+				s.EXPECT().GetByID(gomock.Any(), p.ID).Return(p, nil)
+				s.EXPECT().Update(gomock.Any(), p).Return(model.Post{}, ErrVersionMismatch)
+			},
+			post:    model.Post{ID: 1, Title: "Post1"},
+			header:  `"2"`,
+			expCode: http.StatusPreconditionFailed,
+		},
 	}
 
 	for _, tc := range testcases {
 		c := gomock.NewController(t)
 		defer c.Finish()
 		ps := mockpost.NewMockService(c)
-		as := mockauth.NewMockService(c)
 		tc.mock(ps, tc.post)
 		w := httptest.NewRecorder()
 		b := &bytes.Buffer{}
 		json.NewEncoder(b).Encode(tc.post)
 		r := httptest.NewRequest(http.MethodPatch, "/posts/1", b)
 		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if tc.header != "" {
+			r.Header.Set("If-Match", tc.header)
+		}
 
 		ctx := echo.New().NewContext(r, w)
 		ctx.SetParamNames("id")
 		ctx.SetParamValues("1")
 
-		NewHandler(ps, as).Update(ctx)
+		handlertest.Serve(ctx, NewHandler(ps, nil).Update)
 
 		var post model.Post
-		json.NewDecoder(w.Body).Decode(&post)
+		if w.Code < http.StatusBadRequest {
+			json.NewDecoder(w.Body).Decode(&post)
+		}
 
 		assert.Equal(t, tc.expCode, w.Code)
 		assert.Equal(t, tc.expPost, post)
 	}
 }
 
+func TestHandler_Update_Patch(t *testing.T) {
+	cur := model.Post{ID: 1, Title: "Title", Body: "Body.", UserID: "1", Version: 2}
+	testcases := []struct {
+		name        string
+		mock        func(*mockpost.MockService)
+		contentType string
+		patch       string
+		expPost     model.Post
+		expCode     int
+	}{
+		{
+			name: "merge patch keeps missing fields",
+			mock: func(s *mockpost.MockService) {
+				p := cur
+				p.Title = "New title"
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(cur, nil)
+				s.EXPECT().Update(gomock.Any(), p).Return(p, nil)
+			},
+			contentType: patch.MIMEMergePatch,
+			patch:       `{"title":"New title"}`,
+			expPost:     model.Post{ID: 1, Title: "New title", Body: "Body.", UserID: "1", Version: 2},
+			expCode:     http.StatusOK,
+		},
+		{
+			name: "json patch is applied",
+			mock: func(s *mockpost.MockService) {
+				p := cur
+				p.Body = "New body."
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(cur, nil)
+				s.EXPECT().Update(gomock.Any(), p).Return(p, nil)
+			},
+			contentType: patch.MIMEJSONPatch,
+			patch:       `[{"op":"replace","path":"/body","value":"New body."}]`,
+			expPost:     model.Post{ID: 1, Title: "Title", Body: "New body.", UserID: "1", Version: 2},
+			expCode:     http.StatusOK,
+		},
+		{
+			name: "merged post is invalid",
+			mock: func(s *mockpost.MockService) {
+				p := cur
+				p.Body = ""
+				err := validation.Errors{"body": errors.New("cannot be blank")}
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(cur, nil)
+				s.EXPECT().Update(gomock.Any(), p).Return(model.Post{}, err)
+			},
+			contentType: patch.MIMEMergePatch,
+			patch:       `{"body":null}`,
+			expCode:     http.StatusBadRequest,
+		},
+		{
+			name: "malformed patch",
+			mock: func(s *mockpost.MockService) {
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(cur, nil)
+			},
+			contentType: patch.MIMEJSONPatch,
+			patch:       `{"title":"New title"}`,
+			expCode:     http.StatusBadRequest,
+		},
+		{
+			name: "json patch test fails",
+			mock: func(s *mockpost.MockService) {
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(cur, nil)
+			},
+			contentType: patch.MIMEJSONPatch,
+			patch:       `[{"op":"test","path":"/title","value":"Old title"}]`,
+			expCode:     http.StatusConflict,
+		},
+		{
+			name: "post is updated concurrently",
+			mock: func(s *mockpost.MockService) {
+				p := cur
+				p.Title = "New title"
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(cur, nil)
+				s.EXPECT().Update(gomock.Any(), p).Return(model.Post{}, ErrVersionMismatch)
+			},
+			contentType: patch.MIMEMergePatch,
+			patch:       `{"title":"New title"}`,
+			expCode:     http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ps := mockpost.NewMockService(c)
+		tc.mock(ps)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPatch, "/posts/1", strings.NewReader(tc.patch))
+		r.Header.Set(echo.HeaderContentType, tc.contentType)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, NewHandler(ps, nil).Update)
+
+		var post model.Post
+		if w.Code < http.StatusBadRequest {
+			json.NewDecoder(w.Body).Decode(&post)
+		}
+
+		assert.Equal(t, tc.expCode, w.Code, tc.name)
+		if tc.expCode == http.StatusOK {
+			assert.Equal(t, tc.expPost, post, tc.name)
+		}
+	}
+}
+
 func TestHandler_DeleteyByID(t *testing.T) {
 	testcases := []struct {
 		name    string
@@ -368,7 +724,7 @@ func TestHandler_DeleteyByID(t *testing.T) {
 		{
 			name: "post is deleted",
 			mock: func(s *mockpost.MockService, p model.Post) {
-				s.EXPECT().DeleteByID(p.ID).Return(nil)
+				s.EXPECT().DeleteByID(gomock.Any(), p.ID).Return(nil)
 			},
 			post:    model.Post{ID: 1, Title: "Post1"},
 			expCode: http.StatusNoContent,
@@ -376,7 +732,7 @@ func TestHandler_DeleteyByID(t *testing.T) {
 		{
 			name: "post is not found",
 			mock: func(s *mockpost.MockService, p model.Post) {
-				s.EXPECT().DeleteByID(p.ID).Return(ErrNotFound)
+				s.EXPECT().DeleteByID(gomock.Any(), p.ID).Return(ErrNotFound)
 			},
 			post:    model.Post{ID: 1, Title: "Post1"},
 			expCode: http.StatusNotFound,
@@ -384,7 +740,7 @@ func TestHandler_DeleteyByID(t *testing.T) {
 		{
 			name: "internal error",
 			mock: func(s *mockpost.MockService, p model.Post) {
-				s.EXPECT().DeleteByID(p.ID).Return(errors.New("internal error"))
+				s.EXPECT().DeleteByID(gomock.Any(), p.ID).Return(errors.New("internal error"))
 			},
 			post:    model.Post{ID: 1, Title: "Post1"},
 			expCode: http.StatusInternalServerError,
@@ -395,7 +751,6 @@ func TestHandler_DeleteyByID(t *testing.T) {
 		c := gomock.NewController(t)
 		defer c.Finish()
 		ps := mockpost.NewMockService(c)
-		as := mockauth.NewMockService(c)
 		tc.mock(ps, tc.post)
 		w := httptest.NewRecorder()
 		r := httptest.NewRequest(http.MethodDelete, "/posts/1", nil)
@@ -404,8 +759,508 @@ func TestHandler_DeleteyByID(t *testing.T) {
 		ctx.SetParamNames("id")
 		ctx.SetParamValues("1")
 
-		NewHandler(ps, as).DeleteByID(ctx)
+		handlertest.Serve(ctx, NewHandler(ps, nil).DeleteByID)
 
 		assert.Equal(t, tc.expCode, w.Code)
 	}
 }
+
+func TestHandler_GetTrash(t *testing.T) {
+	testcases := []struct {
+		name     string
+		mock     func(*mockpost.MockService, []model.Post)
+		posts    []model.Post
+		expPosts []model.Post
+		expTotal string
+		expCode  int
+	}{
+		{
+			name: "deleted posts are retrieved",
+			mock: func(s *mockpost.MockService, ps []model.Post) {
+				pg := model.Page{Limit: model.DefaultPageLimit}
+				s.EXPECT().GetTrash(gomock.Any(), "1", pg).Return(ps, model.PageInfo{Total: 1}, nil)
+			},
+			posts:    []model.Post{{ID: 1, Title: "Post1", UserID: "1"}},
+			expPosts: []model.Post{{ID: 1, Title: "Post1", UserID: "1"}},
+			expTotal: "1",
+			expCode:  http.StatusOK,
+		},
+		{
+			name: "internal error",
+			mock: func(s *mockpost.MockService, ps []model.Post) {
+				pg := model.Page{Limit: model.DefaultPageLimit}
+				s.EXPECT().GetTrash(gomock.Any(), "1", pg).Return(nil, model.PageInfo{}, errors.New("internal error"))
+			},
+			expCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ps := mockpost.NewMockService(c)
+		tc.mock(ps, tc.posts)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/posts/trash", nil)
+		r = r.WithContext(auth.WithPrincipal(r.Context(), auth.Principal{ID: "1"}))
+
+		ctx := echo.New().NewContext(r, w)
+
+		handlertest.Serve(ctx, NewHandler(ps, nil).GetTrash)
+
+		var posts []model.Post
+		json.NewDecoder(w.Body).Decode(&posts)
+
+		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expTotal, w.Header().Get("X-Total-Count"))
+		assert.Equal(t, tc.expPosts, posts)
+	}
+}
+
+func TestHandler_Restore(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mock    func(*mockpost.MockService, model.Post)
+		post    model.Post
+		expPost model.Post
+		expCode int
+	}{
+		{
+			name: "post is restored",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				s.EXPECT().Restore(gomock.Any(), p.ID).Return(p, nil)
+			},
+			post:    model.Post{ID: 1, Title: "Post1"},
+			expPost: model.Post{ID: 1, Title: "Post1"},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "post is not found",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				s.EXPECT().Restore(gomock.Any(), p.ID).Return(model.Post{}, ErrNotFound)
+			},
+			post:    model.Post{ID: 1, Title: "Post1"},
+			expCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ps := mockpost.NewMockService(c)
+		tc.mock(ps, tc.post)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/posts/1/restore", nil)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, NewHandler(ps, nil).Restore)
+
+		var post model.Post
+		if w.Code < http.StatusBadRequest {
+			json.NewDecoder(w.Body).Decode(&post)
+		}
+
+		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expPost, post)
+	}
+}
+
+func TestHandler_GetRevisions(t *testing.T) {
+	testcases := []struct {
+		name         string
+		mock         func(*mockpost.MockService, []model.PostRevision)
+		query        string
+		revisions    []model.PostRevision
+		expRevisions []model.PostRevision
+		expTotal     string
+		expCode      int
+	}{
+		{
+			name: "revisions are retrieved",
+			mock: func(s *mockpost.MockService, rs []model.PostRevision) {
+				pg := model.Page{Limit: model.DefaultPageLimit, Order: "desc"}
+				s.EXPECT().GetRevisions(gomock.Any(), 1, pg).Return(rs, model.PageInfo{Total: 2}, nil)
+			},
+			query:        "?order=desc",
+			revisions:    []model.PostRevision{{Rev: 2}, {Rev: 1}},
+			expRevisions: []model.PostRevision{{Rev: 2}, {Rev: 1}},
+			expTotal:     "2",
+			expCode:      http.StatusOK,
+		},
+		{
+			name: "post is not found",
+			mock: func(s *mockpost.MockService, rs []model.PostRevision) {
+				pg := model.Page{Limit: model.DefaultPageLimit}
+				s.EXPECT().GetRevisions(gomock.Any(), 1, pg).Return(nil, model.PageInfo{}, ErrNotFound)
+			},
+			expCode: http.StatusNotFound,
+		},
+		{
+			name: "validation errors",
+			mock: func(s *mockpost.MockService, rs []model.PostRevision) {
+				pg := model.Page{Limit: model.DefaultPageLimit, Sort: "title"}
+				err := validation.Errors{"sort": errors.New("must be a valid value")}
+				s.EXPECT().GetRevisions(gomock.Any(), 1, pg).Return(nil, model.PageInfo{}, err)
+			},
+			query:   "?sort=title",
+			expCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ps := mockpost.NewMockService(c)
+		tc.mock(ps, tc.revisions)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/posts/1/revisions"+tc.query, nil)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, NewHandler(ps, nil).GetRevisions)
+
+		var revisions []model.PostRevision
+		json.NewDecoder(w.Body).Decode(&revisions)
+
+		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expTotal, w.Header().Get("X-Total-Count"))
+		assert.Equal(t, tc.expRevisions, revisions)
+	}
+}
+
+func TestHandler_GetRevision(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mock    func(*mockpost.MockService)
+		rev     string
+		expDiff model.RevisionDiff
+		expCode int
+	}{
+		{
+			name: "revision is retrieved",
+			mock: func(s *mockpost.MockService) {
+				d := model.RevisionDiff{
+					Revision: model.PostRevision{PostID: 1, Rev: 2},
+					Body:     []model.DiffLine{{Op: model.DiffInsert, Text: "Body."}},
+				}
+				s.EXPECT().GetRevision(gomock.Any(), 1, 2).Return(d, nil)
+			},
+			rev: "2",
+			expDiff: model.RevisionDiff{
+				Revision: model.PostRevision{PostID: 1, Rev: 2},
+				Body:     []model.DiffLine{{Op: model.DiffInsert, Text: "Body."}},
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "revision is not found",
+			mock: func(s *mockpost.MockService) {
+				s.EXPECT().GetRevision(gomock.Any(), 1, 3).Return(model.RevisionDiff{}, ErrRevisionNotFound)
+			},
+			rev:     "3",
+			expCode: http.StatusNotFound,
+		},
+		{
+			name:    "invalid revision",
+			mock:    func(s *mockpost.MockService) {},
+			rev:     "last",
+			expCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ps := mockpost.NewMockService(c)
+		tc.mock(ps)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/posts/1/revisions/"+tc.rev, nil)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id", "rev")
+		ctx.SetParamValues("1", tc.rev)
+
+		handlertest.Serve(ctx, NewHandler(ps, nil).GetRevision)
+
+		var d model.RevisionDiff
+		json.NewDecoder(w.Body).Decode(&d)
+
+		assert.Equal(t, tc.expCode, w.Code)
+		if tc.expCode == http.StatusOK {
+			assert.Equal(t, tc.expDiff, d)
+		}
+	}
+}
+
+func TestHandler_RestoreRevision(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mock    func(*mockpost.MockService, model.Post)
+		post    model.Post
+		expPost model.Post
+		expCode int
+	}{
+		{
+			name: "revision is restored",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				s.EXPECT().RestoreRevision(gomock.Any(), 1, 1).Return(p, nil)
+			},
+			post:    model.Post{ID: 1, Title: "Post1"},
+			expPost: model.Post{ID: 1, Title: "Post1"},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "revision is not found",
+			mock: func(s *mockpost.MockService, p model.Post) {
+				s.EXPECT().RestoreRevision(gomock.Any(), 1, 1).Return(model.Post{}, ErrRevisionNotFound)
+			},
+			expCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ps := mockpost.NewMockService(c)
+		tc.mock(ps, tc.post)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/posts/1/revisions/1/restore", nil)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id", "rev")
+		ctx.SetParamValues("1", "1")
+
+		handlertest.Serve(ctx, NewHandler(ps, nil).RestoreRevision)
+
+		var post model.Post
+		if w.Code < http.StatusBadRequest {
+			json.NewDecoder(w.Body).Decode(&post)
+		}
+
+		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expPost, post)
+	}
+}
+
+func TestHandler_Visible(t *testing.T) {
+	next := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+
+	testcases := []struct {
+		name    string
+		mock    func(*mockpost.MockService)
+		uID     string
+		expCode int
+	}{
+		{
+			name: "published post is visible",
+			mock: func(s *mockpost.MockService) {
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, UserID: "1", Status: model.PostPublished}, nil)
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "draft is visible to its author",
+			mock: func(s *mockpost.MockService) {
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, UserID: "1", Status: model.PostDraft}, nil)
+			},
+			uID:     "1",
+			expCode: http.StatusOK,
+		},
+		{
+			name: "draft is hidden from others",
+			mock: func(s *mockpost.MockService) {
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, UserID: "1", Status: model.PostScheduled}, nil)
+			},
+			uID:     "2",
+			expCode: http.StatusNotFound,
+		},
+		{
+			name: "post is not found",
+			mock: func(s *mockpost.MockService) {
+				s.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{}, ErrNotFound)
+			},
+			expCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ps := mockpost.NewMockService(c)
+		tc.mock(ps)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/posts/1/revisions", nil)
+		if tc.uID != "" {
+			r = r.WithContext(auth.WithPrincipal(r.Context(), auth.Principal{ID: tc.uID}))
+		}
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, NewHandler(ps, nil).Visible(next))
+
+		assert.Equal(t, tc.expCode, w.Code, tc.name)
+	}
+}
+
+func TestHandler_Publish(t *testing.T) {
+	at := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	testcases := []struct {
+		name    string
+		mock    func(*mockpost.MockService)
+		body    string
+		expCode int
+	}{
+		{
+			name: "post is published",
+			mock: func(s *mockpost.MockService) {
+				s.EXPECT().Publish(gomock.Any(), 1, nil).Return(model.Post{ID: 1, Status: model.PostPublished}, nil)
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "post is scheduled",
+			mock: func(s *mockpost.MockService) {
+				s.EXPECT().Publish(gomock.Any(), 1, &at).Return(model.Post{ID: 1, Status: model.PostScheduled, PublishAt: &at}, nil)
+			},
+			body:    `{"publishAt":"2030-01-01T10:00:00Z"}`,
+			expCode: http.StatusOK,
+		},
+		{
+			name:    "invalid publication time",
+			mock:    func(s *mockpost.MockService) {},
+			body:    `{"publishAt":"tomorrow"}`,
+			expCode: http.StatusBadRequest,
+		},
+		{
+			name: "post is changed concurrently",
+			mock: func(s *mockpost.MockService) {
+				s.EXPECT().Publish(gomock.Any(), 1, nil).Return(model.Post{}, ErrVersionMismatch)
+			},
+			expCode: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ps := mockpost.NewMockService(c)
+		tc.mock(ps)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/posts/1/publish", strings.NewReader(tc.body))
+		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, NewHandler(ps, nil).Publish)
+
+		assert.Equal(t, tc.expCode, w.Code, tc.name)
+	}
+}
+
+func TestHandler_Unpublish(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mock    func(*mockpost.MockService)
+		expCode int
+	}{
+		{
+			name: "post is unpublished",
+			mock: func(s *mockpost.MockService) {
+				s.EXPECT().Unpublish(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostDraft, Version: 2}, nil)
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "post doesn't exist",
+			mock: func(s *mockpost.MockService) {
+				s.EXPECT().Unpublish(gomock.Any(), 1).Return(model.Post{}, ErrNotFound)
+			},
+			expCode: http.StatusNotFound,
+		},
+		{
+			name: "post is changed concurrently",
+			mock: func(s *mockpost.MockService) {
+				s.EXPECT().Unpublish(gomock.Any(), 1).Return(model.Post{}, ErrVersionMismatch)
+			},
+			expCode: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ps := mockpost.NewMockService(c)
+		tc.mock(ps)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/posts/1/unpublish", nil)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, NewHandler(ps, nil).Unpublish)
+
+		assert.Equal(t, tc.expCode, w.Code, tc.name)
+		if w.Code == http.StatusOK {
+			assert.Equal(t, `"2"`, w.Header().Get("ETag"), tc.name)
+		}
+	}
+}
+
+func TestHandler_Archive(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mock    func(*mockpost.MockService)
+		expCode int
+	}{
+		{
+			name: "post is archived",
+			mock: func(s *mockpost.MockService) {
+				s.EXPECT().Archive(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostArchived, Version: 2}, nil)
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "draft isn't archived",
+			mock: func(s *mockpost.MockService) {
+				s.EXPECT().Archive(gomock.Any(), 1).Return(model.Post{}, ErrNotPublished)
+			},
+			expCode: http.StatusConflict,
+		},
+		{
+			name: "post doesn't exist",
+			mock: func(s *mockpost.MockService) {
+				s.EXPECT().Archive(gomock.Any(), 1).Return(model.Post{}, ErrNotFound)
+			},
+			expCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ps := mockpost.NewMockService(c)
+		tc.mock(ps)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/posts/1/archive", nil)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, NewHandler(ps, nil).Archive)
+
+		assert.Equal(t, tc.expCode, w.Code, tc.name)
+	}
+}
@@ -1,24 +1,56 @@
 // Package post provides all post domain related logic.
 package post
 
-import "github.com/imarrche/nix-ed/internal/model"
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
 
 //go:generate mockgen -source=interface.go -destination=mock/mock.go
 
 // Repo is the interface all post repositories must implement.
 type Repo interface {
-	GetAll() ([]model.Post, error)
-	Create(model.Post) (model.Post, error)
-	GetByID(int) (model.Post, error)
-	Update(model.Post) (model.Post, error)
-	DeleteByID(int) error
+	GetAll(context.Context, model.PostFilter, model.Page) ([]model.Post, model.PageInfo, error)
+	Create(context.Context, model.Post) (model.Post, error)
+	GetByID(context.Context, int) (model.Post, error)
+	GetBySlug(context.Context, string) (model.Post, error)
+	Update(context.Context, model.Post) (model.Post, error)
+	DeleteByID(context.Context, int) error
+	GetTrash(context.Context, string, model.Page) ([]model.Post, model.PageInfo, error)
+	GetDeletedByID(context.Context, int) (model.Post, error)
+	Restore(context.Context, int) (model.Post, error)
+	Purge(context.Context, time.Time) (int64, error)
+	GetRevisions(context.Context, int, model.Page) ([]model.PostRevision, model.PageInfo, error)
+	GetRevision(context.Context, int, int) (model.PostRevision, error)
+	UpdateStatus(context.Context, model.Post) (model.Post, error)
+	PublishDue(context.Context, time.Time) ([]model.Post, error)
 }
 
 // Service is the interface all post services must implement.
 type Service interface {
-	GetAll() ([]model.Post, error)
-	Create(model.Post) (model.Post, error)
-	GetByID(int) (model.Post, error)
-	Update(model.Post) (model.Post, error)
-	DeleteByID(int) error
+	GetAll(context.Context, model.PostFilter, model.Page) ([]model.Post, model.PageInfo, error)
+	Create(context.Context, model.Post) (model.Post, error)
+	GetByID(context.Context, int) (model.Post, error)
+	GetBySlug(context.Context, string) (model.Post, error)
+	Update(context.Context, model.Post) (model.Post, error)
+	DeleteByID(context.Context, int) error
+	GetTrash(context.Context, string, model.Page) ([]model.Post, model.PageInfo, error)
+	GetDeletedByID(context.Context, int) (model.Post, error)
+	Restore(context.Context, int) (model.Post, error)
+	Purge(context.Context, time.Time) (int64, error)
+	GetRevisions(context.Context, int, model.Page) ([]model.PostRevision, model.PageInfo, error)
+	GetRevision(context.Context, int, int) (model.RevisionDiff, error)
+	RestoreRevision(context.Context, int, int) (model.Post, error)
+	Publish(context.Context, int, *time.Time) (model.Post, error)
+	Unpublish(context.Context, int) (model.Post, error)
+	Archive(context.Context, int) (model.Post, error)
+	PublishDue(context.Context, time.Time) ([]model.Post, error)
+}
+
+// ReactionCounter is the interface of services counting reactions on posts
+// and comments.
+type ReactionCounter interface {
+	Count(context.Context, string, []int, string) (map[int][]model.ReactionCount, error)
 }
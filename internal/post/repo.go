@@ -1,9 +1,14 @@
 package post
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"gorm.io/gorm"
 
 	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/store"
 )
 
 // repo is post repository implementation.
@@ -16,43 +21,447 @@ func NewRepo(db *gorm.DB) Repo {
 	return &repo{db}
 }
 
-// GetAll gets and returns all posts.
-func (r *repo) GetAll() (ps []model.Post, err error) {
-	r.db.Find(&ps)
+// sortColumns maps sort parameters to post table columns.
+var sortColumns = map[string]string{
+	"":        "id",
+	"id":      "id",
+	"title":   "title",
+	"created": "created_at",
+	"updated": "updated_at",
+}
+
+// GetAll gets and returns the page of posts matching the filter.
+func (r *repo) GetAll(ctx context.Context, f model.PostFilter, pg model.Page) ([]model.Post, model.PageInfo, error) {
+	return r.page(ctx, pg, func(db *gorm.DB) *gorm.DB {
+		if !f.UpdatedSince.IsZero() {
+			db = db.Where("updated_at >= ?", f.UpdatedSince)
+		}
+		if f.Status != "" {
+			db = db.Where("status = ?", f.Status)
+		}
+		db = r.filterTaxonomy(db, f)
+		if f.IncludeHidden {
+			return db
+		}
+		if f.Viewer == "" {
+			return db.Where("status IN ?", model.PublicPostStatuses)
+		}
+
+		return db.Where("status IN ? OR user_id = ?", model.PublicPostStatuses, f.Viewer)
+	})
+}
+
+// filterTaxonomy adds the filter's tag and category conditions to db.
+func (r *repo) filterTaxonomy(db *gorm.DB, f model.PostFilter) *gorm.DB {
+	if f.Match == model.MatchAny {
+		conds := r.db
+		if len(f.Tags) > 0 {
+			conds = conds.Or("id IN (?)", r.tagged(f.Tags...))
+		}
+		if len(f.Categories) > 0 {
+			conds = conds.Or("id IN (?)", r.categorized(f.Categories...))
+		}
+		if len(f.Tags) > 0 || len(f.Categories) > 0 {
+			db = db.Where(conds)
+		}
+
+		return db
+	}
+
+	for _, t := range f.Tags {
+		db = db.Where("id IN (?)", r.tagged(t))
+	}
+	for _, c := range f.Categories {
+		db = db.Where("id IN (?)", r.categorized(c))
+	}
+
+	return db
+}
+
+// tagged returns the subquery of IDs of posts tagged with any of the tags.
+func (r *repo) tagged(names ...string) *gorm.DB {
+	return r.db.Table("post_tags").Select("post_tags.post_id").
+		Joins("JOIN tags ON tags.id = post_tags.tag_id").
+		Where("tags.name IN ?", names)
+}
+
+// categorized returns the subquery of IDs of posts in any of the categories.
+func (r *repo) categorized(names ...string) *gorm.DB {
+	return r.db.Table("post_categories").Select("post_categories.post_id").
+		Joins("JOIN categories ON categories.id = post_categories.category_id").
+		Where("categories.name IN ?", names)
+}
+
+// withRelations is a GORM scope that loads posts' tags and categories.
+func withRelations(db *gorm.DB) *gorm.DB {
+	return db.Preload("Tags").Preload("Categories")
+}
+
+// GetTrash gets and returns the page of user's deleted posts.
+func (r *repo) GetTrash(ctx context.Context, userID string, pg model.Page) ([]model.Post, model.PageInfo, error) {
+	return r.page(ctx, pg, func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", userID)
+	})
+}
+
+// page gets and returns the page of posts matching all scopes.
+func (r *repo) page(ctx context.Context, pg model.Page, scopes ...func(*gorm.DB) *gorm.DB) (ps []model.Post, pi model.PageInfo, err error) {
+	if err := r.db.WithContext(ctx).Model(&model.Post{}).Scopes(scopes...).Count(&pi.Total).Error; err != nil {
+		return nil, pi, store.Wrap(err)
+	}
+	scopes = append(scopes, pg.Paginate(sortColumns[pg.Sort]))
+	scopes = append(scopes, withRelations)
+	if err := r.db.WithContext(ctx).Scopes(scopes...).Find(&ps).Error; err != nil {
+		return nil, pi, store.Wrap(err)
+	}
+
+	if len(ps) > pg.Limit {
+		ps = ps[:pg.Limit]
+		last := ps[len(ps)-1]
+		pi.NextCursor = pg.NextCursor(sortValue(last, pg.Sort), last.ID)
+	}
+
+	return ps, pi, nil
+}
+
+// sortValue returns the value post is sorted by.
+func sortValue(p model.Post, sort string) interface{} {
+	switch sort {
+	case "title":
+		return p.Title
+	case "created":
+		return p.CreatedAt
+	case "updated":
+		return p.UpdatedAt
+	}
 
-	return
+	return p.ID
 }
 
-// Create creates a post and returns it.
-func (r *repo) Create(p model.Post) (model.Post, error) {
-	res := r.db.Create(&p)
+// Create creates a post with its first revision and slug and returns it.
+func (r *repo) Create(ctx context.Context, p model.Post) (model.Post, error) {
+	p.Version = 1
+	p.Slug = ""
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := resolveRelations(tx, &p); err != nil {
+			return err
+		}
+		if err := tx.Create(&p).Error; err != nil {
+			return err
+		}
+
+		slug, err := addSlug(tx, p.ID, p.Title)
+		if err != nil {
+			return err
+		}
+		p.Slug = slug
+		if err := tx.Model(&model.Post{}).Where("id = ?", p.ID).UpdateColumn("slug", slug).Error; err != nil {
+			return err
+		}
+
+		return addRevision(tx, p)
+	})
 
-	return p, res.Error
+	return p, store.Wrap(err)
+}
+
+// resolveRelations replaces post's tags and categories with the stored ones
+// that have the same names.
+func resolveRelations(tx *gorm.DB, p *model.Post) error {
+	if p.Tags != nil {
+		names := make([]string, len(p.Tags))
+		for i, t := range p.Tags {
+			names[i] = t.Name
+		}
+		p.Tags = []model.Tag{}
+		if err := tx.Where("name IN ?", names).Find(&p.Tags).Error; err != nil {
+			return err
+		}
+		if len(p.Tags) != len(unique(names)) {
+			return ErrUnknownTag
+		}
+	}
+
+	if p.Categories != nil {
+		names := make([]string, len(p.Categories))
+		for i, c := range p.Categories {
+			names[i] = c.Name
+		}
+		p.Categories = []model.Category{}
+		if err := tx.Where("name IN ?", names).Find(&p.Categories).Error; err != nil {
+			return err
+		}
+		if len(p.Categories) != len(unique(names)) {
+			return ErrUnknownCategory
+		}
+	}
+
+	return nil
+}
+
+// unique returns names without duplicates.
+func unique(names []string) map[string]bool {
+	set := map[string]bool{}
+	for _, n := range names {
+		set[n] = true
+	}
+
+	return set
 }
 
 // GetByID gets and returns the post with specifid ID.
-func (r *repo) GetByID(id int) (p model.Post, err error) {
-	r.db.First(&p, id)
-	if p.ID != id {
+func (r *repo) GetByID(ctx context.Context, id int) (p model.Post, err error) {
+	err = r.db.WithContext(ctx).Scopes(withRelations).First(&p, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return p, ErrNotFound
 	}
 
-	return p, nil
+	return p, store.Wrap(err)
 }
 
-// Update updates the post and returns it.
-func (r *repo) Update(p model.Post) (model.Post, error) {
-	r.db.Save(&p)
+// GetBySlug gets and returns the post with specific current or earlier slug.
+func (r *repo) GetBySlug(ctx context.Context, slug string) (model.Post, error) {
+	var ps model.PostSlug
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&ps).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Post{}, ErrNotFound
+	} else if err != nil {
+		return model.Post{}, store.Wrap(err)
+	}
+
+	return r.GetByID(ctx, ps.PostID)
+}
+
+// Update updates the post if its version wasn't changed since it was read,
+// saves the update as a new revision and returns the post. Posts get a new
+// slug when their title changes.
+func (r *repo) Update(ctx context.Context, p model.Post) (model.Post, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := resolveRelations(tx, &p); err != nil {
+			return err
+		}
+
+		var cur model.Post
+		err := tx.Select("id", "title", "slug").First(&cur, p.ID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		} else if err != nil {
+			return err
+		}
+		p.Slug = cur.Slug
+		if cur.Title != p.Title {
+			slug, err := addSlug(tx, p.ID, p.Title)
+			if err != nil {
+				return err
+			}
+			p.Slug = slug
+		}
+
+		p.UpdatedAt = tx.NowFunc()
+		res := tx.Model(&model.Post{}).Where("id = ? AND version = ?", p.ID, p.Version).
+			Updates(map[string]interface{}{
+				"title":      p.Title,
+				"body":       p.Body,
+				"slug":       p.Slug,
+				"updated_at": p.UpdatedAt,
+				"version":    gorm.Expr("version + 1"),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrVersionMismatch
+		}
+		p.Version++
+
+		if p.Tags != nil {
+			if err := tx.Model(&p).Association("Tags").Replace(p.Tags); err != nil {
+				return err
+			}
+		}
+		if p.Categories != nil {
+			if err := tx.Model(&p).Association("Categories").Replace(p.Categories); err != nil {
+				return err
+			}
+		}
+
+		return addRevision(tx, p)
+	})
+	if err != nil {
+		return model.Post{}, store.Wrap(err)
+	}
 
 	return p, nil
 }
 
-// DeleteByID deletes the post with specific ID.
-func (r *repo) DeleteByID(id int) error {
-	if _, err := r.GetByID(id); err != nil {
+// addRevision saves post's title and body as its next revision.
+func addRevision(tx *gorm.DB, p model.Post) error {
+	var last int
+	err := tx.Model(&model.PostRevision{}).Where("post_id = ?", p.ID).
+		Select("COALESCE(MAX(rev), 0)").Scan(&last).Error
+	if err != nil {
 		return err
 	}
-	r.db.Where("id = ?", id).Delete(model.Post{})
 
-	return nil
+	return tx.Create(&model.PostRevision{
+		PostID:    p.ID,
+		Rev:       last + 1,
+		Title:     p.Title,
+		Body:      p.Body,
+		UserID:    p.UserID,
+		CreatedAt: p.UpdatedAt,
+	}).Error
+}
+
+// DeleteByID moves the post with specific ID and all its comments to trash.
+func (r *repo) DeleteByID(ctx context.Context, id int) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return store.Wrap(err)
+	}
+
+	return store.Wrap(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Comments share post's deletion time, so the ones deleted together
+		// with the post can be told apart when it's restored.
+		now := tx.NowFunc()
+		err := tx.Model(&model.Comment{}).
+			Where("post_id = ? AND deleted_at IS NULL", id).
+			UpdateColumn("deleted_at", now).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Post{}).Where("id = ?", id).UpdateColumn("deleted_at", now).Error
+	}))
+}
+
+// GetDeletedByID gets and returns the deleted post with specific ID.
+func (r *repo) GetDeletedByID(ctx context.Context, id int) (p model.Post, err error) {
+	err = r.db.WithContext(ctx).Unscoped().Scopes(withRelations).Where("deleted_at IS NOT NULL").First(&p, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return p, ErrNotFound
+	}
+
+	return p, store.Wrap(err)
+}
+
+// Restore restores the deleted post with specific ID and the comments deleted
+// together with it and returns the post.
+func (r *repo) Restore(ctx context.Context, id int) (model.Post, error) {
+	p, err := r.GetDeletedByID(ctx, id)
+	if err != nil {
+		return model.Post{}, store.Wrap(err)
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&model.Comment{}).
+			Where("post_id = ? AND deleted_at = ?", id, p.DeletedAt).
+			UpdateColumn("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Model(&model.Post{}).Where("id = ?", id).UpdateColumn("deleted_at", nil).Error
+	})
+	if err != nil {
+		return model.Post{}, store.Wrap(err)
+	}
+	p.DeletedAt = gorm.DeletedAt{}
+
+	return p, nil
+}
+
+// Purge permanently deletes posts moved to trash before specific time and
+// returns their number, posts' comments are deleted by the foreign key.
+func (r *repo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Unscoped().Where("deleted_at < ?", before).Delete(&model.Post{})
+
+	return res.RowsAffected, store.Wrap(res.Error)
+}
+
+// GetRevisions gets and returns the page of post's revisions.
+func (r *repo) GetRevisions(ctx context.Context, postID int, pg model.Page) (rs []model.PostRevision, pi model.PageInfo, err error) {
+	q := r.db.WithContext(ctx).Model(&model.PostRevision{}).Where("post_id = ?", postID)
+	if err := q.Count(&pi.Total).Error; err != nil {
+		return nil, pi, store.Wrap(err)
+	}
+	if err := q.Scopes(pg.Paginate("rev")).Find(&rs).Error; err != nil {
+		return nil, pi, store.Wrap(err)
+	}
+
+	if len(rs) > pg.Limit {
+		rs = rs[:pg.Limit]
+		last := rs[len(rs)-1]
+		pi.NextCursor = pg.NextCursor(last.Rev, last.ID)
+	}
+
+	return rs, pi, nil
+}
+
+// GetRevision gets and returns post's revision with specific number.
+func (r *repo) GetRevision(ctx context.Context, postID, rev int) (pr model.PostRevision, err error) {
+	err = r.db.WithContext(ctx).Where("post_id = ? AND rev = ?", postID, rev).First(&pr).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return pr, ErrRevisionNotFound
+	}
+
+	return pr, store.Wrap(err)
+}
+
+// UpdateStatus updates post's status and publication time if its version
+// wasn't changed since it was read and returns the post.
+func (r *repo) UpdateStatus(ctx context.Context, p model.Post) (model.Post, error) {
+	p.UpdatedAt = r.db.NowFunc()
+	res := r.db.WithContext(ctx).Model(&model.Post{}).Where("id = ? AND version = ?", p.ID, p.Version).
+		Updates(map[string]interface{}{
+			"status":     p.Status,
+			"publish_at": p.PublishAt,
+			"updated_at": p.UpdatedAt,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if res.Error != nil {
+		return model.Post{}, store.Wrap(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return model.Post{}, ErrVersionMismatch
+	}
+	p.Version++
+
+	return p, nil
+}
+
+// PublishDue publishes scheduled posts whose publication time is before
+// specific time and returns them.
+func (r *repo) PublishDue(ctx context.Context, now time.Time) ([]model.Post, error) {
+	var ids []int
+	err := r.db.WithContext(ctx).Model(&model.Post{}).
+		Where("status = ? AND publish_at <= ?", model.PostScheduled, now).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, store.Wrap(err)
+	}
+
+	ps := []model.Post{}
+	for _, id := range ids {
+		// Posts unpublished after their IDs were selected stay as they are.
+		res := r.db.WithContext(ctx).Model(&model.Post{}).Where("id = ? AND status = ?", id, model.PostScheduled).
+			Updates(map[string]interface{}{
+				"status":     model.PostPublished,
+				"updated_at": r.db.NowFunc(),
+				"version":    gorm.Expr("version + 1"),
+			})
+		if res.Error != nil {
+			return ps, store.Wrap(res.Error)
+		}
+		if res.RowsAffected == 0 {
+			continue
+		}
+
+		p, err := r.GetByID(ctx, id)
+		if err != nil {
+			return ps, store.Wrap(err)
+		}
+		ps = append(ps, p)
+	}
+
+	return ps, nil
 }
@@ -0,0 +1,612 @@
+package post
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/testdb"
+)
+
+// testModels are the models migrated in test databases.
+var testModels = []interface{}{&model.Tag{}, &model.Category{}, &model.Post{}, &model.Comment{}, &model.PostRevision{}, &model.PostSlug{}}
+
+// seed creates two posts with two comments each.
+func seed(t *testing.T, db *gorm.DB) {
+	for i := 1; i <= 2; i++ {
+		p := model.Post{ID: i, Title: "Title", Body: "Body.", UserID: "1"}
+		if err := db.Create(&p).Error; err != nil {
+			t.Fatal(err)
+		}
+		for j := 0; j < 2; j++ {
+			c := model.Comment{Name: "Name", Email: "u@t.com", Body: "Body.", PostID: i}
+			if err := db.Create(&c).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestPostRepo_GetAll(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	now := time.Date(2021, 2, 1, 10, 0, 0, 0, time.UTC)
+	for i, title := range []string{"B", "A", "C"} {
+		p := model.Post{
+			Title:     title,
+			Body:      "Body.",
+			UserID:    "1",
+			CreatedAt: now.Add(time.Duration(i) * time.Hour),
+			UpdatedAt: now.Add(time.Duration(i) * time.Hour),
+		}
+		if err := db.Create(&p).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := NewRepo(db)
+
+	pg := model.Page{Limit: 2, Sort: "created", Order: "desc"}
+	ps, pi, err := r.GetAll(context.Background(), model.PostFilter{}, pg)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), pi.Total)
+	assert.Equal(t, []string{"C", "A"}, titles(ps))
+	assert.NotEmpty(t, pi.NextCursor)
+
+	pg.Cursor = pi.NextCursor
+	ps, pi, err = r.GetAll(context.Background(), model.PostFilter{}, pg)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"B"}, titles(ps))
+	assert.Empty(t, pi.NextCursor)
+
+	pg = model.Page{Limit: 1, Sort: "title"}
+	ps, pi, err = r.GetAll(context.Background(), model.PostFilter{}, pg)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A"}, titles(ps))
+	pg.Cursor = pi.NextCursor
+	ps, _, err = r.GetAll(context.Background(), model.PostFilter{}, pg)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"B"}, titles(ps))
+
+	f := model.PostFilter{UpdatedSince: now.Add(time.Hour)}
+	ps, pi, err = r.GetAll(context.Background(), f, model.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), pi.Total)
+	assert.Equal(t, []string{"A", "C"}, titles(ps))
+}
+
+// titles returns titles of posts.
+func titles(ps []model.Post) []string {
+	ts := make([]string, len(ps))
+	for i, p := range ps {
+		ts[i] = p.Title
+	}
+
+	return ts
+}
+
+// count returns the numbers of visible posts, visible comments and visible
+// comments of hidden or missing posts.
+func count(db *gorm.DB) (posts, comments, orphans int64) {
+	db.Model(&model.Post{}).Count(&posts)
+	db.Model(&model.Comment{}).Count(&comments)
+	db.Model(&model.Comment{}).Where("post_id NOT IN (?)", db.Model(&model.Post{}).Select("id")).Count(&orphans)
+
+	return
+}
+
+func TestPostRepo_DeleteByID(t *testing.T) {
+	testcases := []struct {
+		name        string
+		id          int
+		expPosts    int64
+		expComments int64
+		expError    error
+	}{
+		{
+			name:        "post and its comments are deleted",
+			id:          1,
+			expPosts:    1,
+			expComments: 2,
+			expError:    nil,
+		},
+		{
+			name:        "post not found",
+			id:          3,
+			expPosts:    2,
+			expComments: 4,
+			expError:    ErrNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := testdb.New(t, testModels...)
+			seed(t, db)
+
+			err := NewRepo(db).DeleteByID(context.Background(), tc.id)
+
+			posts, comments, orphans := count(db)
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expPosts, posts)
+			assert.Equal(t, tc.expComments, comments)
+			assert.Zero(t, orphans)
+		})
+	}
+}
+
+func TestPostRepo_Restore(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	seed(t, db)
+	r := NewRepo(db)
+
+	// The comment deleted before the post stays in trash.
+	assert.NoError(t, db.Where("id = ?", 1).Delete(&model.Comment{}).Error)
+	assert.NoError(t, r.DeleteByID(context.Background(), 1))
+
+	_, err := r.Restore(context.Background(), 2)
+	assert.Equal(t, ErrNotFound, err)
+
+	p, err := r.Restore(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, p.ID)
+	assert.False(t, p.DeletedAt.Valid)
+
+	posts, comments, orphans := count(db)
+	assert.Equal(t, int64(2), posts)
+	assert.Equal(t, int64(3), comments)
+	assert.Zero(t, orphans)
+}
+
+func TestPostRepo_GetTrash(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	seed(t, db)
+	r := NewRepo(db)
+	assert.NoError(t, r.DeleteByID(context.Background(), 2))
+
+	ps, pi, err := r.GetTrash(context.Background(), "1", model.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), pi.Total)
+	assert.Len(t, ps, 1)
+	assert.Equal(t, 2, ps[0].ID)
+
+	ps, pi, err = r.GetTrash(context.Background(), "2", model.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Zero(t, pi.Total)
+	assert.Empty(t, ps)
+}
+
+func TestPostRepo_Purge(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	seed(t, db)
+	r := NewRepo(db)
+	assert.NoError(t, r.DeleteByID(context.Background(), 1))
+
+	n, err := r.Purge(context.Background(), time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+
+	n, err = r.Purge(context.Background(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	var posts, comments int64
+	db.Unscoped().Model(&model.Post{}).Count(&posts)
+	db.Unscoped().Model(&model.Comment{}).Count(&comments)
+	assert.Equal(t, int64(1), posts)
+	assert.Equal(t, int64(2), comments)
+}
+
+func TestPostRepo_CommentsForeignKey(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	seed(t, db)
+
+	// Deleting the post permanently still removes its comments.
+	assert.NoError(t, db.Unscoped().Where("id = ?", 1).Delete(&model.Post{}).Error)
+	var comments int64
+	db.Unscoped().Model(&model.Comment{}).Where("post_id = ?", 1).Count(&comments)
+	assert.Zero(t, comments)
+
+	// Comments can't reference a missing post.
+	c := model.Comment{Name: "Name", Email: "u@t.com", Body: "Body.", PostID: 3}
+	assert.Error(t, db.Create(&c).Error)
+}
+
+func TestPostRepo_Revisions(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	r := NewRepo(db)
+
+	p, err := r.Create(context.Background(), model.Post{Title: "Title", Body: "Body.", UserID: "1"})
+	assert.NoError(t, err)
+	p.Title = "Title 2"
+	p, err = r.Update(context.Background(), p)
+	assert.NoError(t, err)
+	p.Body = "Body 3."
+	p, err = r.Update(context.Background(), p)
+	assert.NoError(t, err)
+
+	pg := model.Page{Limit: 2, Order: "desc"}
+	rs, pi, err := r.GetRevisions(context.Background(), p.ID, pg)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), pi.Total)
+	assert.Len(t, rs, 2)
+	assert.Equal(t, 3, rs[0].Rev)
+	assert.Equal(t, "Body 3.", rs[0].Body)
+	assert.Equal(t, 2, rs[1].Rev)
+
+	pg.Cursor = pi.NextCursor
+	rs, pi, err = r.GetRevisions(context.Background(), p.ID, pg)
+	assert.NoError(t, err)
+	assert.Len(t, rs, 1)
+	assert.Equal(t, "Title", rs[0].Title)
+	assert.Empty(t, pi.NextCursor)
+
+	pr, err := r.GetRevision(context.Background(), p.ID, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "Title 2", pr.Title)
+	assert.Equal(t, "Body.", pr.Body)
+	assert.Equal(t, "1", pr.UserID)
+
+	_, err = r.GetRevision(context.Background(), p.ID, 4)
+	assert.Equal(t, ErrRevisionNotFound, err)
+
+	// Revisions are purged together with the post.
+	assert.NoError(t, db.Unscoped().Where("id = ?", p.ID).Delete(&model.Post{}).Error)
+	var revisions int64
+	db.Model(&model.PostRevision{}).Count(&revisions)
+	assert.Zero(t, revisions)
+}
+
+func TestPostRepo_Update(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	r := NewRepo(db)
+
+	p, err := r.Create(context.Background(), model.Post{Title: "Title", Body: "Body.", UserID: "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, p.Version)
+
+	p.Title = "Title 2"
+	up, err := r.Update(context.Background(), p)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, up.Version)
+
+	// The update read version 1, which is outdated now.
+	p.Title = "Title 3"
+	_, err = r.Update(context.Background(), p)
+	assert.Equal(t, ErrVersionMismatch, err)
+
+	p, err = r.GetByID(context.Background(), p.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Title 2", p.Title)
+	assert.Equal(t, 2, p.Version)
+	var revisions int64
+	db.Model(&model.PostRevision{}).Count(&revisions)
+	assert.Equal(t, int64(2), revisions)
+}
+
+func TestPostRepo_GetAll_Status(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	r := NewRepo(db)
+	for i, status := range []string{model.PostDraft, model.PostPublished, model.PostArchived, model.PostDraft} {
+		p := model.Post{Title: status, Body: "Body.", UserID: strconv.Itoa(i%2 + 1), Status: status}
+		if _, err := r.Create(context.Background(), p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ps, _, err := r.GetAll(context.Background(), model.PostFilter{}, model.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{model.PostPublished, model.PostArchived}, titles(ps))
+
+	// Drafts of the first user are visible only to them.
+	ps, _, err = r.GetAll(context.Background(), model.PostFilter{Viewer: "1"}, model.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{model.PostDraft, model.PostPublished, model.PostArchived}, titles(ps))
+
+	ps, _, err = r.GetAll(context.Background(), model.PostFilter{Viewer: "2", Status: model.PostDraft}, model.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{model.PostDraft}, titles(ps))
+	assert.Equal(t, 4, ps[0].ID)
+
+	ps, _, err = r.GetAll(context.Background(), model.PostFilter{IncludeHidden: true}, model.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{model.PostDraft, model.PostPublished, model.PostArchived, model.PostDraft}, titles(ps))
+}
+
+func TestPostRepo_PublishDue(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	r := NewRepo(db)
+	now := time.Now()
+	for _, at := range []time.Time{now.Add(-time.Minute), now.Add(time.Hour)} {
+		at := at
+		p := model.Post{Title: "Title", Body: "Body.", UserID: "1", Status: model.PostScheduled, PublishAt: &at}
+		if _, err := r.Create(context.Background(), p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ps, err := r.PublishDue(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Len(t, ps, 1)
+	assert.Equal(t, 1, ps[0].ID)
+	assert.Equal(t, model.PostPublished, ps[0].Status)
+	assert.Equal(t, 2, ps[0].Version)
+
+	ps, err = r.PublishDue(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Empty(t, ps)
+
+	p, err := r.GetByID(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, model.PostScheduled, p.Status)
+}
+
+func TestPostRepo_UpdateStatus(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	r := NewRepo(db)
+	p, err := r.Create(context.Background(), model.Post{Title: "Title", Body: "Body.", UserID: "1", Status: model.PostDraft})
+	assert.NoError(t, err)
+
+	p.Status = model.PostArchived
+	up, err := r.UpdateStatus(context.Background(), p)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, up.Version)
+
+	_, err = r.UpdateStatus(context.Background(), p)
+	assert.Equal(t, ErrVersionMismatch, err)
+
+	p, err = r.GetByID(context.Background(), p.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.PostArchived, p.Status)
+}
+
+func TestPostRepo_GetAll_Taxonomy(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	r := NewRepo(db)
+	for _, name := range []string{"go", "sql"} {
+		if err := db.Create(&model.Tag{Name: name}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Create(&model.Category{Name: "backend"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	posts := []model.Post{
+		{Title: "go", Tags: []model.Tag{{Name: "go"}}},
+		{Title: "go sql", Tags: []model.Tag{{Name: "go"}, {Name: "sql"}}, Categories: []model.Category{{Name: "backend"}}},
+		{Title: "backend", Categories: []model.Category{{Name: "backend"}}},
+		{Title: "none"},
+	}
+	for _, p := range posts {
+		p.Body, p.UserID, p.Status = "Body.", "1", model.PostPublished
+		if _, err := r.Create(context.Background(), p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testcases := []struct {
+		name      string
+		filter    model.PostFilter
+		expTitles []string
+	}{
+		{
+			name:      "posts with the tag",
+			filter:    model.PostFilter{Tags: []string{"go"}, Match: model.MatchAll},
+			expTitles: []string{"go", "go sql"},
+		},
+		{
+			name:      "posts with all tags and categories",
+			filter:    model.PostFilter{Tags: []string{"go", "sql"}, Categories: []string{"backend"}, Match: model.MatchAll},
+			expTitles: []string{"go sql"},
+		},
+		{
+			name:      "posts with any tag or category",
+			filter:    model.PostFilter{Tags: []string{"sql"}, Categories: []string{"backend"}, Match: model.MatchAny},
+			expTitles: []string{"go sql", "backend"},
+		},
+		{
+			name:      "unknown tag",
+			filter:    model.PostFilter{Tags: []string{"rust"}, Match: model.MatchAll},
+			expTitles: []string{},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ps, pi, err := r.GetAll(context.Background(), tc.filter, model.Page{Limit: 10})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expTitles, titles(ps))
+			assert.Equal(t, int64(len(tc.expTitles)), pi.Total)
+		})
+	}
+}
+
+func TestPostRepo_Taxonomy(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	r := NewRepo(db)
+	for _, name := range []string{"go", "sql"} {
+		if err := db.Create(&model.Tag{Name: name}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := model.Post{Title: "Title", Body: "Body.", UserID: "1", Tags: []model.Tag{{Name: "rust"}}}
+	_, err := r.Create(context.Background(), p)
+	assert.Equal(t, ErrUnknownTag, err)
+	p.Categories = []model.Category{{Name: "backend"}}
+	p.Tags = nil
+	_, err = r.Create(context.Background(), p)
+	assert.Equal(t, ErrUnknownCategory, err)
+
+	p = model.Post{Title: "Title", Body: "Body.", UserID: "1", Tags: []model.Tag{{Name: "go"}}}
+	p, err = r.Create(context.Background(), p)
+	assert.NoError(t, err)
+
+	// Posts updated without tags keep them.
+	p.Tags = nil
+	p, err = r.Update(context.Background(), p)
+	assert.NoError(t, err)
+	p, err = r.GetByID(context.Background(), p.ID)
+	assert.NoError(t, err)
+	assert.Len(t, p.Tags, 1)
+
+	p.Tags = []model.Tag{{Name: "sql"}}
+	p, err = r.Update(context.Background(), p)
+	assert.NoError(t, err)
+	p, err = r.GetByID(context.Background(), p.ID)
+	assert.NoError(t, err)
+	assert.Len(t, p.Tags, 1)
+	assert.Equal(t, "sql", p.Tags[0].Name)
+}
+
+func TestPostRepo_Slugs(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	r := NewRepo(db)
+
+	p1, err := r.Create(context.Background(), model.Post{Title: "Hello, World", Body: "Body.", UserID: "1", Slug: "custom"})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello-world", p1.Slug)
+	p2, err := r.Create(context.Background(), model.Post{Title: "Hello world!", Body: "Body.", UserID: "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello-world-2", p2.Slug)
+
+	// Posts keep their slug when the title doesn't change.
+	p1.Body = "Body 2."
+	p1, err = r.Update(context.Background(), p1)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello-world", p1.Slug)
+
+	p1.Title = "Goodbye"
+	p1, err = r.Update(context.Background(), p1)
+	assert.NoError(t, err)
+	assert.Equal(t, "goodbye", p1.Slug)
+
+	// The old slug still leads to the post and isn't given to other posts.
+	p, err := r.GetBySlug(context.Background(), "hello-world")
+	assert.NoError(t, err)
+	assert.Equal(t, p1.ID, p.ID)
+	assert.Equal(t, "goodbye", p.Slug)
+	p3, err := r.Create(context.Background(), model.Post{Title: "Hello world", Body: "Body.", UserID: "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello-world-3", p3.Slug)
+
+	// Going back to the old title reuses the post's old slug.
+	p1.Title = "Hello, World"
+	p1, err = r.Update(context.Background(), p1)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello-world", p1.Slug)
+
+	_, err = r.GetBySlug(context.Background(), "missing")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestPostRepo_Create_SlugRace(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	r := NewRepo(db)
+
+	// The other post takes the slug after the post has read the taken slugs
+	// and before it reserves one. The post's transaction doesn't see the
+	// other post's slug afterwards, like REPEATABLE READ snapshots don't.
+	var other model.Post
+	raced := false
+	err := db.Callback().Query().After("gorm:query").Register("test:race", func(tx *gorm.DB) {
+		if tx.Statement.Table != "post_slugs" {
+			return
+		}
+		if raced {
+			switch dest := tx.Statement.Dest.(type) {
+			case *[]model.PostSlug:
+				visible := (*dest)[:0]
+				for _, s := range *dest {
+					if s.PostID != other.ID {
+						visible = append(visible, s)
+					}
+				}
+				*dest = visible
+			case *int64:
+				*dest = 0
+			}
+			return
+		}
+		raced = true
+
+		// The test database has a single connection, the other post is
+		// created in a savepoint of the post's transaction.
+		otherTx := db.Session(&gorm.Session{Context: context.Background()})
+		otherTx.Statement.ConnPool = tx.Statement.ConnPool
+		var err error
+		other, err = NewRepo(otherTx).Create(context.Background(), model.Post{Title: "Hello", Body: "Body.", UserID: "2"})
+		assert.NoError(t, err)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := r.Create(context.Background(), model.Post{Title: "Hello", Body: "Body.", UserID: "1"})
+	assert.NoError(t, err)
+	assert.True(t, raced)
+	assert.Equal(t, "hello", other.Slug)
+	assert.Equal(t, "hello-2", p.Slug)
+
+	got, err := r.GetBySlug(context.Background(), "hello-2")
+	assert.NoError(t, err)
+	assert.Equal(t, p.ID, got.ID)
+}
+func TestPostRepo_Slugs_Concurrent(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	r := NewRepo(db)
+
+	var wg sync.WaitGroup
+	slugs := make([]string, 10)
+	for i := range slugs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p, err := r.Create(context.Background(), model.Post{Title: "Title", Body: "Body.", UserID: "1"})
+			assert.NoError(t, err)
+			slugs[i] = p.Slug
+		}(i)
+	}
+	wg.Wait()
+
+	seen := map[string]bool{}
+	for _, s := range slugs {
+		assert.False(t, seen[s], s)
+		seen[s] = true
+	}
+}
+
+func TestBackfillSlugs(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	seed(t, db)
+
+	assert.NoError(t, BackfillSlugs(db))
+
+	var slugs []string
+	db.Model(&model.Post{}).Order("id").Pluck("slug", &slugs)
+	assert.Equal(t, []string{"title", "title-2"}, slugs)
+}
+
+func TestPostRepo_CanceledContext(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	seed(t, db)
+	r := NewRepo(db)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := r.GetByID(ctx, 1)
+	assert.Equal(t, context.Canceled, err)
+	_, _, err = r.GetAll(ctx, model.PostFilter{}, model.Page{Limit: 10})
+	assert.Equal(t, context.Canceled, err)
+	_, err = r.Create(ctx, model.Post{Title: "Title", Body: "Body.", UserID: "1"})
+	assert.Equal(t, context.Canceled, err)
+
+	var n int64
+	db.Model(&model.Post{}).Count(&n)
+	assert.Equal(t, int64(2), n)
+}
@@ -1,6 +1,23 @@
 package post
 
-import "github.com/imarrche/nix-ed/internal/model"
+import (
+	"context"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// sorts are post list sort parameters.
+var sorts = []string{"id", "title", "created", "updated"}
+
+// statuses are post statuses posts can be listed by.
+var statuses = []interface{}{model.PostDraft, model.PostScheduled, model.PostPublished, model.PostArchived}
+
+// revisionSorts are post revision list sort parameters.
+var revisionSorts = []string{"rev"}
 
 // service is post service implementation.
 type service struct {
@@ -12,42 +29,217 @@ func NewService(r Repo) Service {
 	return &service{r}
 }
 
-// GetAll gets and returns all posts.
-func (s *service) GetAll() (ps []model.Post, err error) {
-	return s.r.GetAll()
+// GetAll gets and returns the page of posts matching the filter.
+func (s *service) GetAll(ctx context.Context, f model.PostFilter, pg model.Page) ([]model.Post, model.PageInfo, error) {
+	if err := pg.Validate(sorts...); err != nil {
+		return nil, model.PageInfo{}, err
+	}
+	if err := validation.Validate(f.Status, validation.In(statuses...)); err != nil {
+		return nil, model.PageInfo{}, validation.Errors{"status": err}
+	}
+	if f.Match == "" {
+		f.Match = model.MatchAll
+	}
+	if err := validation.Validate(f.Match, validation.In(model.MatchAll, model.MatchAny)); err != nil {
+		return nil, model.PageInfo{}, validation.Errors{"match": err}
+	}
+	f.Tags = splitNames(f.Tags)
+	f.Categories = splitNames(f.Categories)
+
+	return s.r.GetAll(ctx, f, pg)
 }
 
-// Create creates a post and returns it.
-func (s *service) Create(p model.Post) (model.Post, error) {
+// splitNames splits comma separated tag or category names.
+func splitNames(values []string) []string {
+	var names []string
+	for _, v := range values {
+		for _, n := range strings.Split(v, ",") {
+			if n = strings.TrimSpace(n); n != "" {
+				names = append(names, n)
+			}
+		}
+	}
+
+	return names
+}
+
+// Create creates a post and returns it. Posts without status are drafts,
+// published posts get the current publication time.
+func (s *service) Create(ctx context.Context, p model.Post) (model.Post, error) {
+	if p.Status == "" {
+		p.Status = model.PostDraft
+	}
+	if p.Status == model.PostPublished {
+		now := time.Now()
+		p.PublishAt = &now
+	}
 	if err := p.Validate(); err != nil {
 		return model.Post{}, err
 	}
 
-	return s.r.Create(p)
+	return s.r.Create(ctx, p)
 }
 
 // GetByID gets and returns the post with specific ID.
-func (s *service) GetByID(id int) (p model.Post, err error) {
-	return s.r.GetByID(id)
+func (s *service) GetByID(ctx context.Context, id int) (p model.Post, err error) {
+	return s.r.GetByID(ctx, id)
+}
+
+// GetBySlug gets and returns the post with specific current or earlier slug.
+func (s *service) GetBySlug(ctx context.Context, slug string) (model.Post, error) {
+	return s.r.GetBySlug(ctx, slug)
 }
 
-// Update updates the post and returns it.
-func (s *service) Update(p model.Post) (model.Post, error) {
-	up, err := s.r.GetByID(p.ID)
+// Update updates the post and returns it. When post's version is set, it must
+// be the current one.
+func (s *service) Update(ctx context.Context, p model.Post) (model.Post, error) {
+	up, err := s.r.GetByID(ctx, p.ID)
 	if err != nil {
 		return model.Post{}, err
 	}
+	if p.Version != 0 && p.Version != up.Version {
+		return model.Post{}, ErrVersionMismatch
+	}
 
 	up.Title = p.Title
 	up.Body = p.Body
+	if p.Tags != nil {
+		up.Tags = p.Tags
+	}
+	if p.Categories != nil {
+		up.Categories = p.Categories
+	}
 	if err := up.Validate(); err != nil {
 		return model.Post{}, err
 	}
 
-	return s.r.Update(up)
+	return s.r.Update(ctx, up)
 }
 
 // DeleteByID deletes the post with specific ID.
-func (s *service) DeleteByID(id int) error {
-	return s.r.DeleteByID(id)
+func (s *service) DeleteByID(ctx context.Context, id int) error {
+	return s.r.DeleteByID(ctx, id)
+}
+
+// GetTrash gets and returns the page of user's deleted posts.
+func (s *service) GetTrash(ctx context.Context, userID string, pg model.Page) ([]model.Post, model.PageInfo, error) {
+	if err := pg.Validate(sorts...); err != nil {
+		return nil, model.PageInfo{}, err
+	}
+
+	return s.r.GetTrash(ctx, userID, pg)
+}
+
+// GetDeletedByID gets and returns the deleted post with specific ID.
+func (s *service) GetDeletedByID(ctx context.Context, id int) (model.Post, error) {
+	return s.r.GetDeletedByID(ctx, id)
+}
+
+// Restore restores the deleted post with specific ID and returns it.
+func (s *service) Restore(ctx context.Context, id int) (model.Post, error) {
+	return s.r.Restore(ctx, id)
+}
+
+// Purge permanently deletes posts moved to trash before specific time and
+// returns their number.
+func (s *service) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return s.r.Purge(ctx, before)
+}
+
+// GetRevisions gets and returns the page of revisions of the post with
+// specific ID.
+func (s *service) GetRevisions(ctx context.Context, postID int, pg model.Page) ([]model.PostRevision, model.PageInfo, error) {
+	if _, err := s.r.GetByID(ctx, postID); err != nil {
+		return nil, model.PageInfo{}, err
+	}
+	if err := pg.Validate(revisionSorts...); err != nil {
+		return nil, model.PageInfo{}, err
+	}
+
+	return s.r.GetRevisions(ctx, postID, pg)
+}
+
+// GetRevision gets and returns post's revision with specific number and the
+// diff from it to the current version of the post.
+func (s *service) GetRevision(ctx context.Context, postID, rev int) (model.RevisionDiff, error) {
+	p, err := s.r.GetByID(ctx, postID)
+	if err != nil {
+		return model.RevisionDiff{}, err
+	}
+	pr, err := s.r.GetRevision(ctx, postID, rev)
+	if err != nil {
+		return model.RevisionDiff{}, err
+	}
+
+	return model.RevisionDiff{
+		Revision: pr,
+		Title:    diff(pr.Title, p.Title),
+		Body:     diff(pr.Body, p.Body),
+	}, nil
+}
+
+// RestoreRevision updates the post to post's revision with specific number,
+// which saves it as a new revision, and returns the post.
+func (s *service) RestoreRevision(ctx context.Context, postID, rev int) (model.Post, error) {
+	pr, err := s.r.GetRevision(ctx, postID, rev)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	return s.Update(ctx, model.Post{ID: postID, Title: pr.Title, Body: pr.Body})
+}
+
+// Publish publishes the post with specific ID at specific time, the post is
+// published immediately when the time is nil or has passed.
+func (s *service) Publish(ctx context.Context, id int, at *time.Time) (model.Post, error) {
+	p, err := s.r.GetByID(ctx, id)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	now := time.Now()
+	if at != nil && at.After(now) {
+		p.Status = model.PostScheduled
+		p.PublishAt = at
+	} else {
+		p.Status = model.PostPublished
+		p.PublishAt = &now
+	}
+
+	return s.r.UpdateStatus(ctx, p)
+}
+
+// Unpublish turns the post with specific ID into a draft.
+func (s *service) Unpublish(ctx context.Context, id int) (model.Post, error) {
+	p, err := s.r.GetByID(ctx, id)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	p.Status = model.PostDraft
+	p.PublishAt = nil
+
+	return s.r.UpdateStatus(ctx, p)
+}
+
+// Archive archives the post with specific ID, only published posts can be
+// archived.
+func (s *service) Archive(ctx context.Context, id int) (model.Post, error) {
+	p, err := s.r.GetByID(ctx, id)
+	if err != nil {
+		return model.Post{}, err
+	}
+	if p.Status != model.PostPublished {
+		return model.Post{}, ErrNotPublished
+	}
+
+	p.Status = model.PostArchived
+
+	return s.r.UpdateStatus(ctx, p)
+}
+
+// PublishDue publishes scheduled posts whose publication time is before
+// specific time and returns them.
+func (s *service) PublishDue(ctx context.Context, now time.Time) ([]model.Post, error) {
+	return s.r.PublishDue(ctx, now)
 }
@@ -1,8 +1,12 @@
 package post
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/golang/mock/gomock"
@@ -14,25 +18,37 @@ import (
 
 func TestPostService_GetAll(t *testing.T) {
 	testcases := []struct {
-		name     string
-		mock     func(*mockpost.MockRepo, []model.Post)
-		posts    []model.Post
-		expPosts []model.Post
-		expError error
+		name        string
+		mock        func(*mockpost.MockRepo, model.Page, []model.Post)
+		page        model.Page
+		posts       []model.Post
+		expPosts    []model.Post
+		expPageInfo model.PageInfo
+		expError    error
 	}{
 		{
 			name: "posts are retrieved",
-			mock: func(r *mockpost.MockRepo, ps []model.Post) {
-				r.EXPECT().GetAll().Return(ps, nil)
+			mock: func(r *mockpost.MockRepo, pg model.Page, ps []model.Post) {
+				r.EXPECT().GetAll(gomock.Any(), model.PostFilter{Match: model.MatchAll}, pg).Return(ps, model.PageInfo{Total: 2}, nil)
 
 			},
+			page: model.Page{Limit: 20, Sort: "title", Order: "desc"},
 			posts: []model.Post{
 				{Title: "Title 1"}, {Title: "Title 2"},
 			},
 			expPosts: []model.Post{
 				{Title: "Title 1"}, {Title: "Title 2"},
 			},
-			expError: nil,
+			expPageInfo: model.PageInfo{Total: 2},
+			expError:    nil,
+		},
+		{
+			name: "validation errors",
+			mock: func(_ *mockpost.MockRepo, _ model.Page, _ []model.Post) {},
+			page: model.Page{Limit: 20, Sort: "body"},
+			expError: validation.Errors{
+				"sort": errors.New("must be a valid value"),
+			},
 		},
 	}
 
@@ -41,17 +57,34 @@ func TestPostService_GetAll(t *testing.T) {
 			c := gomock.NewController(t)
 			defer c.Finish()
 			repo := mockpost.NewMockRepo(c)
-			tc.mock(repo, tc.posts)
+			tc.mock(repo, tc.page, tc.posts)
 			s := NewService(repo)
 
-			ps, err := s.GetAll()
+			ps, pi, err := s.GetAll(context.Background(), model.PostFilter{}, tc.page)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expPosts, ps)
+			assert.Equal(t, tc.expPageInfo, pi)
 		})
 	}
 }
 
+func TestPostService_GetAll_Taxonomy(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	repo := mockpost.NewMockRepo(c)
+	pg := model.Page{Limit: 20}
+	f := model.PostFilter{Tags: []string{"go", "sql"}, Categories: []string{"backend"}, Match: model.MatchAll}
+	repo.EXPECT().GetAll(gomock.Any(), f, pg).Return(nil, model.PageInfo{}, nil)
+	s := NewService(repo)
+
+	_, _, err := s.GetAll(context.Background(), model.PostFilter{Tags: []string{"go, sql", ""}, Categories: []string{"backend"}}, pg)
+	assert.NoError(t, err)
+
+	_, _, err = s.GetAll(context.Background(), model.PostFilter{Match: "some"}, pg)
+	assert.Equal(t, validation.Errors{"match": errors.New("must be a valid value")}, err)
+}
+
 func TestPostService_Create(t *testing.T) {
 	testcases := []struct {
 		name     string
@@ -63,18 +96,40 @@ func TestPostService_Create(t *testing.T) {
 		{
 			name: "posts is created",
 			mock: func(r *mockpost.MockRepo, p model.Post) {
-				r.EXPECT().Create(p).Return(p, nil)
+				r.EXPECT().Create(gomock.Any(), p).Return(p, nil)
+			},
+			post:     model.Post{Title: "Title 1", Body: "Body.", UserID: "1", Status: model.PostDraft},
+			expPost:  model.Post{Title: "Title 1", Body: "Body.", UserID: "1", Status: model.PostDraft},
+			expError: nil,
+		},
+		{
+			name: "post without status is a draft",
+			mock: func(r *mockpost.MockRepo, p model.Post) {
+				p.Status = model.PostDraft
+				r.EXPECT().Create(gomock.Any(), p).Return(p, nil)
 			},
 			post:     model.Post{Title: "Title 1", Body: "Body.", UserID: "1"},
-			expPost:  model.Post{Title: "Title 1", Body: "Body.", UserID: "1"},
+			expPost:  model.Post{Title: "Title 1", Body: "Body.", UserID: "1", Status: model.PostDraft},
 			expError: nil,
 		},
+		{
+			name:     "scheduled post without publication time",
+			mock:     func(_ *mockpost.MockRepo, _ model.Post) {},
+			post:     model.Post{Title: "Title 1", Body: "Body.", UserID: "1", Status: model.PostScheduled},
+			expError: validation.Errors{"publishAt": errors.New("is required for scheduled posts")},
+		},
 		{
 			name:     "validation errors",
 			mock:     func(_ *mockpost.MockRepo, _ model.Post) {},
 			post:     model.Post{Title: "Title 1", UserID: "1"},
 			expError: validation.Errors{"body": errors.New("cannot be blank")},
 		},
+		{
+			name:     "body is too long",
+			mock:     func(_ *mockpost.MockRepo, _ model.Post) {},
+			post:     model.Post{Title: "Title 1", Body: strings.Repeat("\n", model.MaxPostBodyLength+1), UserID: "1"},
+			expError: validation.Errors{"body": errors.New("the length must be no more than 100000")},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -85,7 +140,7 @@ func TestPostService_Create(t *testing.T) {
 			tc.mock(repo, tc.post)
 			s := NewService(repo)
 
-			p, err := s.Create(tc.post)
+			p, err := s.Create(context.Background(), tc.post)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expPost, p)
@@ -104,7 +159,7 @@ func TestPostService_GetByID(t *testing.T) {
 		{
 			name: "post is retrieved by ID",
 			mock: func(r *mockpost.MockRepo, p model.Post) {
-				r.EXPECT().GetByID(p.ID).Return(p, nil)
+				r.EXPECT().GetByID(gomock.Any(), p.ID).Return(p, nil)
 			},
 			post:     model.Post{Title: "Title 1"},
 			expPost:  model.Post{Title: "Title 1"},
@@ -120,7 +175,7 @@ func TestPostService_GetByID(t *testing.T) {
 			tc.mock(repo, tc.post)
 			s := NewService(repo)
 
-			p, err := s.GetByID(tc.post.ID)
+			p, err := s.GetByID(context.Background(), tc.post.ID)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expPost, p)
@@ -139,25 +194,33 @@ func TestPostService_Update(t *testing.T) {
 		{
 			name: "post is updated",
 			mock: func(r *mockpost.MockRepo, p model.Post) {
-				r.EXPECT().GetByID(p.ID).Return(p, nil)
-				r.EXPECT().Update(p).Return(p, nil)
+				r.EXPECT().GetByID(gomock.Any(), p.ID).Return(p, nil)
+				r.EXPECT().Update(gomock.Any(), p).Return(p, nil)
 			},
-			post:     model.Post{Title: "Updated title 1", Body: "Body.", UserID: "1"},
-			expPost:  model.Post{Title: "Updated title 1", Body: "Body.", UserID: "1"},
+			post:     model.Post{Title: "Updated title 1", Body: "Body.", UserID: "1", Status: model.PostDraft},
+			expPost:  model.Post{Title: "Updated title 1", Body: "Body.", UserID: "1", Status: model.PostDraft},
 			expError: nil,
 		},
 		{
 			name: "validation errors",
 			mock: func(r *mockpost.MockRepo, p model.Post) {
-				r.EXPECT().GetByID(p.ID).Return(p, nil)
+				r.EXPECT().GetByID(gomock.Any(), p.ID).Return(p, nil)
 			},
-			post:     model.Post{Title: "Title 1", UserID: "1"},
+			post:     model.Post{Title: "Title 1", UserID: "1", Status: model.PostDraft},
 			expError: validation.Errors{"body": errors.New("cannot be blank")},
 		},
+		{
+			name: "outdated version",
+			mock: func(r *mockpost.MockRepo, p model.Post) {
+				r.EXPECT().GetByID(gomock.Any(), p.ID).Return(model.Post{Version: 3}, nil)
+			},
+			post:     model.Post{Title: "Title 1", Body: "Body.", UserID: "1", Version: 2},
+			expError: ErrVersionMismatch,
+		},
 		{
 			name: "post not found",
 			mock: func(r *mockpost.MockRepo, p model.Post) {
-				r.EXPECT().GetByID(p.ID).Return(model.Post{}, errors.New("not found"))
+				r.EXPECT().GetByID(gomock.Any(), p.ID).Return(model.Post{}, errors.New("not found"))
 			},
 			post:     model.Post{Title: "Title 1", UserID: "1"},
 			expError: errors.New("not found"),
@@ -172,7 +235,7 @@ func TestPostService_Update(t *testing.T) {
 			tc.mock(repo, tc.post)
 			s := NewService(repo)
 
-			p, err := s.Update(tc.post)
+			p, err := s.Update(context.Background(), tc.post)
 
 			assert.Equal(t, tc.expError, err)
 			assert.Equal(t, tc.expPost, p)
@@ -190,7 +253,7 @@ func TestPostService_DeleteByID(t *testing.T) {
 		{
 			name: "post is deleted by ID",
 			mock: func(r *mockpost.MockRepo, p model.Post) {
-				r.EXPECT().DeleteByID(p.ID).Return(nil)
+				r.EXPECT().DeleteByID(gomock.Any(), p.ID).Return(nil)
 			},
 			post:     model.Post{Title: "Title 1"},
 			expError: nil,
@@ -205,9 +268,338 @@ func TestPostService_DeleteByID(t *testing.T) {
 			tc.mock(repo, tc.post)
 			s := NewService(repo)
 
-			err := s.DeleteByID(tc.post.ID)
+			err := s.DeleteByID(context.Background(), tc.post.ID)
 
 			assert.Equal(t, tc.expError, err)
 		})
 	}
 }
+
+func TestPostService_GetRevisions(t *testing.T) {
+	testcases := []struct {
+		name         string
+		mock         func(*mockpost.MockRepo, model.Page, []model.PostRevision)
+		page         model.Page
+		revisions    []model.PostRevision
+		expRevisions []model.PostRevision
+		expError     error
+	}{
+		{
+			name: "revisions are retrieved",
+			mock: func(r *mockpost.MockRepo, pg model.Page, rs []model.PostRevision) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1}, nil)
+				r.EXPECT().GetRevisions(gomock.Any(), 1, pg).Return(rs, model.PageInfo{Total: 2}, nil)
+			},
+			page:         model.Page{Limit: 20, Sort: "rev", Order: "desc"},
+			revisions:    []model.PostRevision{{Rev: 2}, {Rev: 1}},
+			expRevisions: []model.PostRevision{{Rev: 2}, {Rev: 1}},
+		},
+		{
+			name: "post not found",
+			mock: func(r *mockpost.MockRepo, pg model.Page, rs []model.PostRevision) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{}, ErrNotFound)
+			},
+			page:     model.Page{Limit: 20},
+			expError: ErrNotFound,
+		},
+		{
+			name: "validation errors",
+			mock: func(r *mockpost.MockRepo, pg model.Page, rs []model.PostRevision) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1}, nil)
+			},
+			page:     model.Page{Limit: 20, Sort: "title"},
+			expError: validation.Errors{"sort": errors.New("must be a valid value")},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mockpost.NewMockRepo(c)
+			tc.mock(repo, tc.page, tc.revisions)
+			s := NewService(repo)
+
+			rs, _, err := s.GetRevisions(context.Background(), 1, tc.page)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expRevisions, rs)
+		})
+	}
+}
+
+func TestPostService_GetRevision(t *testing.T) {
+	testcases := []struct {
+		name     string
+		mock     func(*mockpost.MockRepo)
+		expDiff  model.RevisionDiff
+		expError error
+	}{
+		{
+			name: "revision is retrieved with diff",
+			mock: func(r *mockpost.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, Title: "Title", Body: "a\nc\nd"}, nil)
+				r.EXPECT().GetRevision(gomock.Any(), 1, 1).Return(model.PostRevision{PostID: 1, Rev: 1, Title: "Title", Body: "a\nb\nc"}, nil)
+			},
+			expDiff: model.RevisionDiff{
+				Revision: model.PostRevision{PostID: 1, Rev: 1, Title: "Title", Body: "a\nb\nc"},
+				Title:    []model.DiffLine{{Op: model.DiffEqual, Text: "Title"}},
+				Body: []model.DiffLine{
+					{Op: model.DiffEqual, Text: "a"},
+					{Op: model.DiffDelete, Text: "b"},
+					{Op: model.DiffEqual, Text: "c"},
+					{Op: model.DiffInsert, Text: "d"},
+				},
+			},
+		},
+		{
+			name: "post not found",
+			mock: func(r *mockpost.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{}, ErrNotFound)
+			},
+			expError: ErrNotFound,
+		},
+		{
+			name: "revision not found",
+			mock: func(r *mockpost.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1}, nil)
+				r.EXPECT().GetRevision(gomock.Any(), 1, 1).Return(model.PostRevision{}, ErrRevisionNotFound)
+			},
+			expError: ErrRevisionNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mockpost.NewMockRepo(c)
+			tc.mock(repo)
+			s := NewService(repo)
+
+			d, err := s.GetRevision(context.Background(), 1, 1)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expDiff, d)
+		})
+	}
+}
+
+func TestPostService_RestoreRevision(t *testing.T) {
+	testcases := []struct {
+		name     string
+		mock     func(*mockpost.MockRepo)
+		expPost  model.Post
+		expError error
+	}{
+		{
+			name: "revision is restored",
+			mock: func(r *mockpost.MockRepo) {
+				p := model.Post{ID: 1, Title: "Title 2", Body: "Body 2.", UserID: "1", Status: model.PostPublished}
+				up := model.Post{ID: 1, Title: "Title", Body: "Body.", UserID: "1", Status: model.PostPublished}
+				r.EXPECT().GetRevision(gomock.Any(), 1, 1).Return(model.PostRevision{PostID: 1, Rev: 1, Title: "Title", Body: "Body."}, nil)
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(p, nil)
+				r.EXPECT().Update(gomock.Any(), up).Return(up, nil)
+			},
+			expPost: model.Post{ID: 1, Title: "Title", Body: "Body.", UserID: "1", Status: model.PostPublished},
+		},
+		{
+			name: "revision not found",
+			mock: func(r *mockpost.MockRepo) {
+				r.EXPECT().GetRevision(gomock.Any(), 1, 1).Return(model.PostRevision{}, ErrRevisionNotFound)
+			},
+			expError: ErrRevisionNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mockpost.NewMockRepo(c)
+			tc.mock(repo)
+			s := NewService(repo)
+
+			p, err := s.RestoreRevision(context.Background(), 1, 1)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expPost, p)
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	assert.Equal(t, []model.DiffLine{}, diff("", ""))
+	assert.Equal(t, []model.DiffLine{{Op: model.DiffInsert, Text: "a"}}, diff("", "a"))
+	assert.Equal(t, []model.DiffLine{
+		{Op: model.DiffDelete, Text: "a"},
+		{Op: model.DiffInsert, Text: "b"},
+		{Op: model.DiffEqual, Text: "c"},
+		{Op: model.DiffDelete, Text: ""},
+	}, diff("a\nc\n", "b\nc"))
+}
+
+func TestDiff_Large(t *testing.T) {
+	var old, new []string
+	for i := 0; i < 2000; i++ {
+		old = append(old, fmt.Sprintf("old %d", i))
+		new = append(new, fmt.Sprintf("new %d", i))
+	}
+
+	// Lines around the edit are equal, the rest is too large for the table.
+	d := diff("a\n"+strings.Join(old, "\n")+"\nz", "a\n"+strings.Join(new, "\n")+"\nz")
+
+	assert.Len(t, d, 4002)
+	assert.Equal(t, model.DiffLine{Op: model.DiffEqual, Text: "a"}, d[0])
+	assert.Equal(t, model.DiffLine{Op: model.DiffDelete, Text: "old 0"}, d[1])
+	assert.Equal(t, model.DiffLine{Op: model.DiffInsert, Text: "new 0"}, d[2001])
+	assert.Equal(t, model.DiffLine{Op: model.DiffEqual, Text: "z"}, d[4001])
+}
+
+func TestPostService_Publish(t *testing.T) {
+	later := time.Now().Add(time.Hour)
+	earlier := time.Now().Add(-time.Hour)
+	testcases := []struct {
+		name      string
+		mock      func(*mockpost.MockRepo)
+		at        *time.Time
+		expStatus string
+		expError  error
+	}{
+		{
+			name: "post is published now",
+			mock: func(r *mockpost.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostDraft}, nil)
+				r.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, p model.Post) (model.Post, error) {
+					return p, nil
+				})
+			},
+			expStatus: model.PostPublished,
+		},
+		{
+			name: "post with passed time is published now",
+			mock: func(r *mockpost.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostDraft}, nil)
+				r.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, p model.Post) (model.Post, error) {
+					return p, nil
+				})
+			},
+			at:        &earlier,
+			expStatus: model.PostPublished,
+		},
+		{
+			name: "post is scheduled",
+			mock: func(r *mockpost.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostDraft}, nil)
+				p := model.Post{ID: 1, Status: model.PostScheduled, PublishAt: &later}
+				r.EXPECT().UpdateStatus(gomock.Any(), p).Return(p, nil)
+			},
+			at:        &later,
+			expStatus: model.PostScheduled,
+		},
+		{
+			name: "post not found",
+			mock: func(r *mockpost.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{}, ErrNotFound)
+			},
+			expError: ErrNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mockpost.NewMockRepo(c)
+			tc.mock(repo)
+			s := NewService(repo)
+
+			p, err := s.Publish(context.Background(), 1, tc.at)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expStatus, p.Status)
+			if tc.expStatus == model.PostPublished {
+				assert.False(t, p.PublishAt.After(time.Now()))
+			}
+		})
+	}
+}
+
+func TestPostService_Unpublish(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	repo := mockpost.NewMockRepo(c)
+	now := time.Now()
+	repo.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostPublished, PublishAt: &now}, nil)
+	repo.EXPECT().UpdateStatus(gomock.Any(), model.Post{ID: 1, Status: model.PostDraft}).
+		Return(model.Post{ID: 1, Status: model.PostDraft, Version: 2}, nil)
+	s := NewService(repo)
+
+	p, err := s.Unpublish(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.Post{ID: 1, Status: model.PostDraft, Version: 2}, p)
+}
+
+func TestPostService_Archive(t *testing.T) {
+	now := time.Now()
+	testcases := []struct {
+		name     string
+		mock     func(*mockpost.MockRepo)
+		expPost  model.Post
+		expError error
+	}{
+		{
+			name: "published post is archived",
+			mock: func(r *mockpost.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostPublished, PublishAt: &now}, nil)
+				r.EXPECT().UpdateStatus(gomock.Any(), model.Post{ID: 1, Status: model.PostArchived, PublishAt: &now}).
+					Return(model.Post{ID: 1, Status: model.PostArchived, PublishAt: &now, Version: 2}, nil)
+			},
+			expPost: model.Post{ID: 1, Status: model.PostArchived, PublishAt: &now, Version: 2},
+		},
+		{
+			name: "draft isn't archived",
+			mock: func(r *mockpost.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostDraft}, nil)
+			},
+			expError: ErrNotPublished,
+		},
+		{
+			name: "scheduled post isn't archived",
+			mock: func(r *mockpost.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostScheduled, PublishAt: &now}, nil)
+			},
+			expError: ErrNotPublished,
+		},
+		{
+			name: "archived post isn't archived again",
+			mock: func(r *mockpost.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostArchived, PublishAt: &now}, nil)
+			},
+			expError: ErrNotPublished,
+		},
+		{
+			name: "post doesn't exist",
+			mock: func(r *mockpost.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{}, ErrNotFound)
+			},
+			expError: ErrNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mockpost.NewMockRepo(c)
+			tc.mock(repo)
+			s := NewService(repo)
+
+			p, err := s.Archive(context.Background(), 1)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expPost, p)
+		})
+	}
+}
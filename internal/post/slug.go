@@ -0,0 +1,144 @@
+package post
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/gorm"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/store"
+)
+
+const (
+	// maxSlugLen is the maximum length of a slug without its number suffix.
+	maxSlugLen = 80
+	// defaultSlug is the slug of posts which titles have no letters or
+	// digits that can be transliterated.
+	defaultSlug = "post"
+)
+
+// translit maps letters that don't decompose into Latin ones to their Latin
+// transliterations. Cyrillic letters follow Ukrainian national
+// transliteration, letters missing in Ukrainian follow Russian one.
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "h", 'ґ': "g", 'д': "d", 'е': "e",
+	'є': "ye", 'ж': "zh", 'з': "z", 'и': "y", 'і': "i", 'ї': "yi", 'й': "y",
+	'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch",
+	'ш': "sh", 'щ': "shch", 'ь': "", 'ю': "yu", 'я': "ya", 'ё': "yo",
+	'ы': "y", 'э': "e", 'ъ': "", 'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o",
+	'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th", 'ı': "i",
+}
+
+// slugify returns the slug of the title: lowercase transliterated words
+// joined by hyphens.
+func slugify(title string) string {
+	var b strings.Builder
+	hyphen := false
+	write := func(s string) {
+		if hyphen && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		hyphen = false
+		b.WriteString(s)
+	}
+
+	// Letters are transliterated before they're decomposed, otherwise
+	// letters like "й" would lose their marks and turn into other letters.
+	for _, r := range strings.ToLower(title) {
+		if s, ok := translit[r]; ok {
+			write(s)
+			continue
+		}
+		for _, d := range norm.NFD.String(string(r)) {
+			switch {
+			case d <= unicode.MaxASCII && (unicode.IsLetter(d) || unicode.IsDigit(d)):
+				write(string(d))
+			case unicode.Is(unicode.Mn, d):
+			default:
+				hyphen = true
+			}
+		}
+	}
+
+	s := b.String()
+	if len(s) > maxSlugLen {
+		s = strings.TrimRight(s[:maxSlugLen], "-")
+	}
+	if s == "" {
+		return defaultSlug
+	}
+
+	return s
+}
+
+// addSlug reserves a unique slug for the post with specific ID and returns it.
+// Slugs taken by other posts get the first free number suffix, the slug the
+// post already has is reused. The slugs table primary key keeps concurrent
+// creates from reserving the same slug, the losing one tries the next number.
+// The slugs it loses are remembered, the transaction's snapshot may not show
+// them.
+func addSlug(tx *gorm.DB, postID int, title string) (string, error) {
+	base := slugify(title)
+	lost := map[string]bool{}
+	for {
+		var taken []model.PostSlug
+		err := tx.Where("slug = ? OR slug LIKE ?", base, base+"-%").Find(&taken).Error
+		if err != nil {
+			return "", err
+		}
+		owners := make(map[string]int, len(taken))
+		for _, s := range taken {
+			owners[s.Slug] = s.PostID
+		}
+
+		slug := base
+		for n := 2; lost[slug] || owners[slug] != 0 && owners[slug] != postID; n++ {
+			slug = fmt.Sprintf("%s-%d", base, n)
+		}
+		if owners[slug] == postID {
+			return slug, nil
+		}
+
+		// The savepoint keeps the transaction usable when another post has
+		// just reserved the slug.
+		err = tx.Transaction(func(tx *gorm.DB) error {
+			return tx.Create(&model.PostSlug{Slug: slug, PostID: postID}).Error
+		})
+		if err == nil {
+			return slug, nil
+		}
+		if !errors.Is(store.Wrap(err), store.ErrConflict) {
+			return "", err
+		}
+		lost[slug] = true
+	}
+}
+
+// BackfillSlugs gives slugs to posts created before slugs were added.
+func BackfillSlugs(db *gorm.DB) error {
+	var ps []model.Post
+	if err := db.Unscoped().Where("slug = '' OR slug IS NULL").Find(&ps).Error; err != nil {
+		return err
+	}
+
+	for _, p := range ps {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			slug, err := addSlug(tx, p.ID, p.Title)
+			if err != nil {
+				return err
+			}
+
+			return tx.Unscoped().Model(&model.Post{}).Where("id = ?", p.ID).UpdateColumn("slug", slug).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
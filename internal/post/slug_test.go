@@ -0,0 +1,31 @@
+package post
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	testcases := []struct {
+		name    string
+		title   string
+		expSlug string
+	}{
+		{name: "words are joined by hyphens", title: "Hello, World!", expSlug: "hello-world"},
+		{name: "digits are kept", title: "Go 1.16 released", expSlug: "go-1-16-released"},
+		{name: "diacritics are removed", title: "Crème brûlée à Paris", expSlug: "creme-brulee-a-paris"},
+		{name: "special Latin letters", title: "Straße Łódź", expSlug: "strasse-lodz"},
+		{name: "Cyrillic is transliterated", title: "Привіт, світе", expSlug: "pryvit-svite"},
+		{name: "letters with marks are transliterated whole", title: "Їжак й ялинка", expSlug: "yizhak-y-yalynka"},
+		{name: "untransliterable title", title: "你好 ?!", expSlug: defaultSlug},
+		{name: "long title is cut", title: strings.Repeat("ab ", 40), expSlug: strings.TrimRight(strings.Repeat("ab-", 27), "-")},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expSlug, slugify(tc.title))
+		})
+	}
+}
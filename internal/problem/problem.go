@@ -0,0 +1,203 @@
+// Package problem provides RFC 7807 problem details error responses.
+package problem
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/labstack/echo/v4"
+
+	"github.com/imarrche/nix-ed/internal/render"
+	"github.com/imarrche/nix-ed/internal/store"
+)
+
+const (
+	// MIMEJSON and MIMEXML are the media types of problem details.
+	MIMEJSON = "application/problem+json"
+	MIMEXML  = "application/problem+xml"
+
+	// TypeValidation is the type of problems with invalid request fields.
+	TypeValidation = "/problems/validation"
+	// TypeBlank is the type of problems described by their status alone.
+	TypeBlank = "about:blank"
+
+	// StatusClientClosedRequest is the status of requests canceled by their
+	// clients before the response was written.
+	StatusClientClosedRequest = 499
+)
+
+// Problem is problem details error response.
+type Problem struct {
+	XMLName  xml.Name     `json:"-" xml:"urn:ietf:rfc:7807 problem"`
+	Type     string       `json:"type" xml:"type"`
+	Title    string       `json:"title" xml:"title"`
+	Status   int          `json:"status" xml:"status"`
+	Detail   string       `json:"detail,omitempty" xml:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty" xml:"instance,omitempty"`
+	TraceID  string       `json:"traceId" xml:"traceId"`
+	Errors   []FieldError `json:"errors,omitempty" xml:"errors>error,omitempty"`
+}
+
+// FieldError is the error of a request field, fields of nested objects are
+// joined with dots.
+type FieldError struct {
+	Field   string `json:"field" xml:"field"`
+	Message string `json:"message" xml:"message"`
+}
+
+// New returns the problem of the error with specific HTTP status. Validation
+// errors become field errors, details of server errors are hidden.
+func New(status int, err error) Problem {
+	p := Problem{Type: TypeBlank, Title: http.StatusText(status), Status: status}
+	if status == StatusClientClosedRequest {
+		p.Title = "Client Closed Request"
+	}
+
+	var es validation.Errors
+	if errors.As(err, &es) {
+		p.Type = TypeValidation
+		p.Title = "Request has invalid fields"
+		p.Errors = fieldErrors("", es)
+		return p
+	}
+	if err != nil && status < http.StatusInternalServerError && err.Error() != p.Title {
+		p.Detail = err.Error()
+	}
+
+	return p
+}
+
+// fieldErrors returns sorted field errors of the validation errors, nested
+// validation errors are prefixed with their field.
+func fieldErrors(prefix string, es validation.Errors) []FieldError {
+	var fes []FieldError
+	for field, err := range es {
+		if err == nil {
+			continue
+		}
+		if nested, ok := err.(validation.Errors); ok {
+			fes = append(fes, fieldErrors(prefix+field+".", nested)...)
+			continue
+		}
+		fes = append(fes, FieldError{Field: prefix + field, Message: err.Error()})
+	}
+	sort.Slice(fes, func(i, j int) bool { return fes[i].Field < fes[j].Field })
+
+	return fes
+}
+
+// Handler is echo HTTP error handler responding with problem details in JSON
+// or XML, whichever the request accepts. Errors are echo HTTP errors,
+// repository errors, validation errors, which are bad requests, or server
+// errors. Database errors are detailed by their kind and logged. The
+// problem's trace ID is the request ID.
+func Handler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status, cause := statusOf(err)
+	if status >= http.StatusInternalServerError {
+		c.Logger().Error(err)
+	} else if kind, ok := store.Redact(cause); ok {
+		c.Logger().Error(err)
+		cause = kind
+	}
+	p := New(status, cause)
+	p.Instance = c.Request().URL.Path
+	p.TraceID = traceID(c)
+
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else if isXML(c.Request().Header.Get(echo.HeaderAccept)) {
+		err = write(c, status, MIMEXML, xml.Marshal, p)
+	} else {
+		err = write(c, status, MIMEJSON, json.Marshal, p)
+	}
+	if err != nil {
+		c.Logger().Error(err)
+	}
+}
+
+// offers are the media types problems are written as, XML ones are written
+// in XML and the others in JSON.
+var offers = []string{
+	MIMEJSON, echo.MIMEApplicationJSON,
+	MIMEXML, echo.MIMEApplicationXML, echo.MIMETextXML,
+}
+
+// isXML reports whether the Accept header prefers problems in XML. Problems
+// are written in JSON when it accepts neither format, since the error is
+// what the client has to learn about.
+func isXML(accept string) bool {
+	mt, _ := render.Negotiate(accept, offers)
+	return strings.HasSuffix(mt, "xml")
+}
+
+// statusOf returns HTTP status of the error and the error that caused it.
+// Requests canceled by their clients aren't server errors, requests that ran
+// out of time are gateway timeouts.
+func statusOf(err error) (int, error) {
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		switch m := he.Message.(type) {
+		case *echo.HTTPError:
+			return he.Code, fmt.Errorf("%v", m.Message)
+		case error:
+			return he.Code, m
+		}
+		return he.Code, fmt.Errorf("%v", he.Message)
+	}
+
+	var es validation.Errors
+	if errors.As(err, &es) {
+		return http.StatusBadRequest, err
+	}
+	if errors.Is(err, context.Canceled) {
+		return StatusClientClosedRequest, err
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout, err
+	}
+
+	return store.Status(err), err
+}
+
+// traceID returns the request ID, or a new one when the request has none.
+func traceID(c echo.Context) string {
+	id := c.Response().Header().Get(echo.HeaderXRequestID)
+	if id == "" {
+		id = c.Request().Header.Get(echo.HeaderXRequestID)
+	}
+	if id == "" {
+		b := make([]byte, 16)
+		rand.Read(b)
+		id = hex.EncodeToString(b)
+	}
+	c.Response().Header().Set(echo.HeaderXRequestID, id)
+
+	return id
+}
+
+// write responds with the marshaled problem of the media type.
+func write(c echo.Context, status int, mime string, marshal func(interface{}) ([]byte, error), p Problem) error {
+	b, err := marshal(p)
+	if err != nil {
+		return err
+	}
+	if mime == MIMEXML {
+		b = append([]byte(xml.Header), b...)
+	}
+
+	return c.Blob(status, mime, b)
+}
@@ -0,0 +1,248 @@
+package problem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-sql-driver/mysql"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/store"
+)
+
+func TestNew(t *testing.T) {
+	testcases := []struct {
+		name   string
+		status int
+		err    error
+		exp    Problem
+	}{
+		{
+			name:   "validation errors",
+			status: http.StatusBadRequest,
+			err: validation.Errors{
+				"title":  errors.New("cannot be blank"),
+				"author": validation.Errors{"name": errors.New("is too long")},
+				"body":   nil,
+			},
+			exp: Problem{
+				Type: TypeValidation, Title: "Request has invalid fields", Status: http.StatusBadRequest,
+				Errors: []FieldError{
+					{Field: "author.name", Message: "is too long"},
+					{Field: "title", Message: "cannot be blank"},
+				},
+			},
+		},
+		{
+			name:   "client error",
+			status: http.StatusConflict,
+			err:    errors.New("name is already taken"),
+			exp:    Problem{Type: TypeBlank, Title: "Conflict", Status: http.StatusConflict, Detail: "name is already taken"},
+		},
+		{
+			name:   "detail repeating title",
+			status: http.StatusNotFound,
+			err:    errors.New("Not Found"),
+			exp:    Problem{Type: TypeBlank, Title: "Not Found", Status: http.StatusNotFound},
+		},
+		{
+			name:   "client closed request",
+			status: StatusClientClosedRequest,
+			err:    context.Canceled,
+			exp: Problem{
+				Type: TypeBlank, Title: "Client Closed Request", Status: StatusClientClosedRequest, Detail: "context canceled",
+			},
+		},
+		{
+			name:   "server error",
+			status: http.StatusInternalServerError,
+			err:    errors.New("dial tcp: connection refused"),
+			exp:    Problem{Type: TypeBlank, Title: "Internal Server Error", Status: http.StatusInternalServerError},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.exp, New(tc.status, tc.err))
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	testcases := []struct {
+		name      string
+		err       error
+		expStatus int
+		expDetail string
+	}{
+		{
+			name:      "echo error",
+			err:       echo.NewHTTPError(http.StatusForbidden, "post isn't yours"),
+			expStatus: http.StatusForbidden,
+			expDetail: "post isn't yours",
+		},
+		{
+			name:      "echo error with cause",
+			err:       echo.NewHTTPError(http.StatusUnauthorized, errors.New("token is expired")),
+			expStatus: http.StatusUnauthorized,
+			expDetail: "token is expired",
+		},
+		{
+			name:      "echo error without message",
+			err:       echo.NewHTTPError(http.StatusNotFound),
+			expStatus: http.StatusNotFound,
+		},
+		{
+			name:      "repository error",
+			err:       store.New(store.ErrConflict, "name is already taken"),
+			expStatus: http.StatusConflict,
+			expDetail: "name is already taken",
+		},
+		{
+			name:      "database error",
+			err:       store.Wrap(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a' for key 'idx_tags_name'"}),
+			expStatus: http.StatusConflict,
+			expDetail: store.ErrConflict.Error(),
+		},
+		{
+			name: "database error with echo error",
+			err: echo.NewHTTPError(
+				http.StatusUnprocessableEntity,
+				store.Wrap(&mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}),
+			),
+			expStatus: http.StatusUnprocessableEntity,
+			expDetail: store.ErrConstraint.Error(),
+		},
+		{
+			name:      "validation errors",
+			err:       validation.Errors{"title": errors.New("cannot be blank")},
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "canceled request",
+			err:       fmt.Errorf("query: %w", context.Canceled),
+			expStatus: StatusClientClosedRequest,
+			expDetail: "query: context canceled",
+		},
+		{
+			name:      "deadline exceeded",
+			err:       store.Wrap(fmt.Errorf("query: %w", context.DeadlineExceeded)),
+			expStatus: http.StatusGatewayTimeout,
+		},
+		{
+			name:      "unknown error",
+			err:       errors.New("internal error"),
+			expStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/posts/1", nil)
+			r.Header.Set(echo.HeaderXRequestID, "trace")
+			e := echo.New()
+			e.Logger.SetOutput(ioutil.Discard)
+
+			Handler(tc.err, e.NewContext(r, w))
+
+			var p Problem
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+			assert.Equal(t, tc.expStatus, w.Code)
+			assert.Equal(t, MIMEJSON, w.Header().Get(echo.HeaderContentType))
+			assert.Equal(t, tc.expStatus, p.Status)
+			assert.Equal(t, tc.expDetail, p.Detail)
+			assert.Equal(t, "/posts/1", p.Instance)
+			assert.Equal(t, "trace", p.TraceID)
+		})
+	}
+}
+
+func TestHandler_Log(t *testing.T) {
+	testcases := []struct {
+		name   string
+		err    error
+		expLog bool
+	}{
+		{name: "client error", err: echo.NewHTTPError(http.StatusNotFound)},
+		{name: "canceled request", err: context.Canceled},
+		{name: "database error", err: store.Wrap(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}), expLog: true},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, expLog: true},
+		{name: "server error", err: errors.New("internal error"), expLog: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			log := &bytes.Buffer{}
+			e := echo.New()
+			e.Logger.SetOutput(log)
+			r := httptest.NewRequest(http.MethodGet, "/posts", nil)
+
+			Handler(tc.err, e.NewContext(r, httptest.NewRecorder()))
+
+			assert.Equal(t, tc.expLog, log.Len() > 0)
+		})
+	}
+}
+
+func TestHandler_XML(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/posts", nil)
+	r.Header.Set(echo.HeaderAccept, "application/xml")
+
+	Handler(validation.Errors{"order": errors.New("must be a valid value")}, echo.New().NewContext(r, w))
+
+	var p Problem
+	assert.NoError(t, xml.NewDecoder(w.Body).Decode(&p))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, MIMEXML, w.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, TypeValidation, p.Type)
+	assert.Equal(t, []FieldError{{Field: "order", Message: "must be a valid value"}}, p.Errors)
+	assert.NotEqual(t, "", p.TraceID)
+	assert.Equal(t, p.TraceID, w.Header().Get(echo.HeaderXRequestID))
+}
+
+func TestHandler_Head(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodHead, "/posts/1", nil)
+
+	Handler(echo.NewHTTPError(http.StatusNotFound), echo.New().NewContext(r, w))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, 0, w.Body.Len())
+}
+
+func TestHandler_Negotiation(t *testing.T) {
+	testcases := []struct {
+		accept  string
+		expType string
+	}{
+		{accept: "application/json;q=0.5, application/xml", expType: MIMEXML},
+		{accept: "application/problem+xml", expType: MIMEXML},
+		{accept: "application/xhtml+xml, application/xml;q=0", expType: MIMEJSON},
+		{accept: "text/html", expType: MIMEJSON},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.accept, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/posts", nil)
+			r.Header.Set(echo.HeaderAccept, tc.accept)
+
+			Handler(echo.NewHTTPError(http.StatusNotAcceptable), echo.New().NewContext(r, w))
+
+			assert.Equal(t, http.StatusNotAcceptable, w.Code)
+			assert.Equal(t, tc.expType, w.Header().Get(echo.HeaderContentType))
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package reaction
+
+import (
+	"errors"
+
+	"github.com/imarrche/nix-ed/internal/store"
+)
+
+var (
+	// ErrNotFound is thrown when specified reaction was not found in database.
+	ErrNotFound = store.New(store.ErrNotFound, "specified reaction was not found")
+	// ErrAlreadyReacted is thrown when the user has already added the same
+	// reaction on the item.
+	ErrAlreadyReacted = store.New(store.ErrConflict, "specified reaction was already added")
+	// ErrTargetNotFound is thrown when the reacted post or comment was not
+	// found in database.
+	ErrTargetNotFound = errors.New("specified reaction target was not found")
+)
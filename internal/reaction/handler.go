@@ -0,0 +1,231 @@
+package reaction
+
+import (
+	"net/http"
+	"strconv"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/labstack/echo/v4"
+
+	"github.com/imarrche/nix-ed/internal/auth"
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/render"
+)
+
+// Handler is http handler for reaction resource.
+type Handler struct {
+	rs Service
+}
+
+// NewHandler creates and returns a new Handler instance.
+func NewHandler(rs Service) *Handler {
+	return &Handler{rs: rs}
+}
+
+// setPageHeaders sets the total count and the next page cursor headers.
+func setPageHeaders(c echo.Context, pi model.PageInfo) {
+	c.Response().Header().Set("X-Total-Count", strconv.FormatInt(pi.Total, 10))
+	if pi.NextCursor != "" {
+		c.Response().Header().Set("X-Next-Cursor", pi.NextCursor)
+	}
+}
+
+// GetAllByPostID returns the list of post's reactions.
+// @Summary Show post's reactions
+// @Descriptions show the page of reactions on the post and users who added them
+// @Tags reactions
+// @ID post-reaction-list
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack,text/csv
+// @Param id path int true "post id"
+// @Param kind query string false "reaction kind" Enums(like, heart, laugh, wow, sad, angry)
+// @Param limit query int false "page size" default(20) maximum(100)
+// @Param offset query int false "number of reactions to skip"
+// @Param cursor query string false "cursor returned by the previous page"
+// @Param sort query string false "sort field" Enums(id, created)
+// @Param order query string false "sort order" Enums(asc, desc)
+// @Success 200 {array} model.Reaction
+// @Header 200 {integer} X-Total-Count "total number of reactions"
+// @Header 200 {string} X-Next-Cursor "cursor of the next page"
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /posts/{id}/reactions [get]
+func (h *Handler) GetAllByPostID(c echo.Context) error {
+	return h.getAll(c, model.ReactionPost)
+}
+
+// AddToPost adds a reaction on a post.
+// @Summary Add a reaction on a post
+// @Descriptions add a reaction on a post, users can add every kind of reaction once
+// @Tags reactions
+// @ID post-reaction-add
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "post id"
+// @Param input body model.Reaction true "reaction data, only kind is used"
+// @Success 201 {object} model.Reaction
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Router /posts/{id}/reactions [post]
+func (h *Handler) AddToPost(c echo.Context) error {
+	return h.add(c, model.ReactionPost)
+}
+
+// RemoveFromPost removes a reaction from a post.
+// @Summary Remove a reaction from a post
+// @Descriptions remove the user's reaction of the kind from a post
+// @Tags reactions
+// @ID post-reaction-remove
+// @Param id path int true "post id"
+// @Param kind path string true "reaction kind"
+// @Success 204 ""
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /posts/{id}/reactions/{kind} [delete]
+func (h *Handler) RemoveFromPost(c echo.Context) error {
+	return h.remove(c, model.ReactionPost)
+}
+
+// GetAllByCommentID returns the list of comment's reactions.
+// @Summary Show comment's reactions
+// @Descriptions show the page of reactions on the comment and users who added them
+// @Tags reactions
+// @ID comment-reaction-list
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack,text/csv
+// @Param id path int true "comment id"
+// @Param kind query string false "reaction kind" Enums(like, heart, laugh, wow, sad, angry)
+// @Param limit query int false "page size" default(20) maximum(100)
+// @Param offset query int false "number of reactions to skip"
+// @Param cursor query string false "cursor returned by the previous page"
+// @Param sort query string false "sort field" Enums(id, created)
+// @Param order query string false "sort order" Enums(asc, desc)
+// @Success 200 {array} model.Reaction
+// @Header 200 {integer} X-Total-Count "total number of reactions"
+// @Header 200 {string} X-Next-Cursor "cursor of the next page"
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /comments/{id}/reactions [get]
+func (h *Handler) GetAllByCommentID(c echo.Context) error {
+	return h.getAll(c, model.ReactionComment)
+}
+
+// AddToComment adds a reaction on a comment.
+// @Summary Add a reaction on a comment
+// @Descriptions add a reaction on a comment, users can add every kind of reaction once
+// @Tags reactions
+// @ID comment-reaction-add
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "comment id"
+// @Param input body model.Reaction true "reaction data, only kind is used"
+// @Success 201 {object} model.Reaction
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Router /comments/{id}/reactions [post]
+func (h *Handler) AddToComment(c echo.Context) error {
+	return h.add(c, model.ReactionComment)
+}
+
+// RemoveFromComment removes a reaction from a comment.
+// @Summary Remove a reaction from a comment
+// @Descriptions remove the user's reaction of the kind from a comment
+// @Tags reactions
+// @ID comment-reaction-remove
+// @Param id path int true "comment id"
+// @Param kind path string true "reaction kind"
+// @Success 204 ""
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /comments/{id}/reactions/{kind} [delete]
+func (h *Handler) RemoveFromComment(c echo.Context) error {
+	return h.remove(c, model.ReactionComment)
+}
+
+// getAll responds with the page of reactions on the item with the type and
+// the ID from the path.
+func (h *Handler) getAll(c echo.Context, targetType string) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	f := model.ReactionFilter{}
+	if err := c.Bind(&f); err != nil {
+		return err
+	}
+	f.Viewer = auth.Viewer(c)
+	pg := model.Page{Limit: model.DefaultPageLimit}
+	if err := c.Bind(&pg); err != nil {
+		return err
+	}
+
+	rs, pi, err := h.rs.GetAll(c.Request().Context(), targetType, id, f, pg)
+	if err == ErrTargetNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+
+	setPageHeaders(c, pi)
+	return render.Respond(c, http.StatusOK, rs)
+}
+
+// add adds the user's reaction on the item with the type and the ID from the
+// path.
+func (h *Handler) add(c echo.Context, targetType string) error {
+	uID := auth.Viewer(c)
+	if uID == "" {
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	rc := model.Reaction{}
+	if err := c.Bind(&rc); err != nil {
+		return err
+	}
+	rc = model.Reaction{TargetType: targetType, TargetID: id, Kind: rc.Kind, UserID: uID}
+
+	rc, err = h.rs.Add(c.Request().Context(), rc)
+	if err == ErrTargetNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err == ErrAlreadyReacted {
+		return echo.NewHTTPError(http.StatusConflict, err)
+	} else if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusCreated, rc)
+}
+
+// remove removes the user's reaction of the kind from the path from the item
+// with the type and the ID from the path.
+func (h *Handler) remove(c echo.Context, targetType string) error {
+	uID := auth.Viewer(c)
+	if uID == "" {
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	rc := model.Reaction{TargetType: targetType, TargetID: id, Kind: c.Param("kind"), UserID: uID}
+	if err := h.rs.Remove(c.Request().Context(), rc); err == ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
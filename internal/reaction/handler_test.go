@@ -0,0 +1,204 @@
+package reaction
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/auth"
+	mockauth "github.com/imarrche/nix-ed/internal/auth/mock"
+	"github.com/imarrche/nix-ed/internal/handlertest"
+	"github.com/imarrche/nix-ed/internal/model"
+	mockreaction "github.com/imarrche/nix-ed/internal/reaction/mock"
+)
+
+// signedIn wraps the handler func in authentication of the user with ID
+// 1.
+func signedIn(c *gomock.Controller, r *http.Request, next echo.HandlerFunc) echo.HandlerFunc {
+	ss := mockauth.NewMockSessions(c)
+	ss.EXPECT().Verify(gomock.Any(), "token").Return(auth.Claims{Subject: "1", Email: "u@t.com", SessionID: "s"}, nil)
+	r.Header.Set("Authorization", "token")
+
+	return auth.Authenticate(ss)(next)
+}
+
+func TestHandler_AddToPost(t *testing.T) {
+	like := model.Reaction{TargetType: model.ReactionPost, TargetID: 1, Kind: "like", UserID: "1"}
+
+	testcases := []struct {
+		name    string
+		mock    func(*mockreaction.MockService)
+		body    string
+		expCode int
+	}{
+		{
+			name: "reaction is added",
+			mock: func(s *mockreaction.MockService) {
+				s.EXPECT().Add(gomock.Any(), like).Return(like, nil)
+			},
+			body:    `{"kind":"like","userId":"2"}`,
+			expCode: http.StatusCreated,
+		},
+		{
+			name: "reaction is already added",
+			mock: func(s *mockreaction.MockService) {
+				s.EXPECT().Add(gomock.Any(), like).Return(model.Reaction{}, ErrAlreadyReacted)
+			},
+			body:    `{"kind":"like"}`,
+			expCode: http.StatusConflict,
+		},
+		{
+			name: "post is not found",
+			mock: func(s *mockreaction.MockService) {
+				s.EXPECT().Add(gomock.Any(), like).Return(model.Reaction{}, ErrTargetNotFound)
+			},
+			body:    `{"kind":"like"}`,
+			expCode: http.StatusNotFound,
+		},
+		{
+			name: "validation errors",
+			mock: func(s *mockreaction.MockService) {
+				rc := like
+				rc.Kind = "dislike"
+				err := validation.Errors{"kind": errors.New("must be a valid value")}
+				s.EXPECT().Add(gomock.Any(), rc).Return(model.Reaction{}, err)
+			},
+			body:    `{"kind":"dislike"}`,
+			expCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		rs := mockreaction.NewMockService(c)
+		tc.mock(rs)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/posts/1/reactions", bytes.NewBufferString(tc.body))
+		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		hf := signedIn(c, r, NewHandler(rs).AddToPost)
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, hf)
+
+		assert.Equal(t, tc.expCode, w.Code)
+	}
+}
+
+func TestHandler_RemoveFromComment(t *testing.T) {
+	like := model.Reaction{TargetType: model.ReactionComment, TargetID: 1, Kind: "like", UserID: "1"}
+
+	testcases := []struct {
+		name    string
+		mock    func(*mockreaction.MockService)
+		expCode int
+	}{
+		{
+			name: "reaction is removed",
+			mock: func(s *mockreaction.MockService) {
+				s.EXPECT().Remove(gomock.Any(), like).Return(nil)
+			},
+			expCode: http.StatusNoContent,
+		},
+		{
+			name: "reaction is not found",
+			mock: func(s *mockreaction.MockService) {
+				s.EXPECT().Remove(gomock.Any(), like).Return(ErrNotFound)
+			},
+			expCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		rs := mockreaction.NewMockService(c)
+		tc.mock(rs)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodDelete, "/comments/1/reactions/like", nil)
+
+		hf := signedIn(c, r, NewHandler(rs).RemoveFromComment)
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id", "kind")
+		ctx.SetParamValues("1", "like")
+
+		handlertest.Serve(ctx, hf)
+
+		assert.Equal(t, tc.expCode, w.Code)
+	}
+}
+
+func TestHandler_GetAllByPostID(t *testing.T) {
+	testcases := []struct {
+		name         string
+		mock         func(*mockreaction.MockService)
+		query        string
+		expReactions []model.Reaction
+		expTotal     string
+		expCode      int
+	}{
+		{
+			name: "reactions are retrieved",
+			mock: func(s *mockreaction.MockService) {
+				f := model.ReactionFilter{Kind: "like"}
+				pg := model.Page{Limit: model.DefaultPageLimit}
+				rs := []model.Reaction{{ID: 1, Kind: "like", UserID: "1"}}
+				s.EXPECT().GetAll(gomock.Any(), model.ReactionPost, 1, f, pg).Return(rs, model.PageInfo{Total: 1}, nil)
+			},
+			query:        "?kind=like",
+			expReactions: []model.Reaction{{ID: 1, Kind: "like", UserID: "1"}},
+			expTotal:     "1",
+			expCode:      http.StatusOK,
+		},
+		{
+			name: "validation errors",
+			mock: func(s *mockreaction.MockService) {
+				f := model.ReactionFilter{Kind: "dislike"}
+				pg := model.Page{Limit: model.DefaultPageLimit}
+				err := validation.Errors{"kind": errors.New("must be a valid value")}
+				s.EXPECT().GetAll(gomock.Any(), model.ReactionPost, 1, f, pg).Return(nil, model.PageInfo{}, err)
+			},
+			query:   "?kind=dislike",
+			expCode: http.StatusBadRequest,
+		},
+		{
+			name:    "invalid query",
+			mock:    func(s *mockreaction.MockService) {},
+			query:   "?limit=ten",
+			expCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		rs := mockreaction.NewMockService(c)
+		tc.mock(rs)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/posts/1/reactions"+tc.query, nil)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, NewHandler(rs).GetAllByPostID)
+
+		var reactions []model.Reaction
+		json.NewDecoder(w.Body).Decode(&reactions)
+
+		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expTotal, w.Header().Get("X-Total-Count"))
+		assert.Equal(t, tc.expReactions, reactions)
+	}
+}
@@ -0,0 +1,28 @@
+// Package reaction provides all reaction domain related logic.
+package reaction
+
+import (
+	"context"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+//go:generate mockgen -source=interface.go -destination=mock/mock.go
+
+// Repo is the interface all reaction repositories must implement.
+type Repo interface {
+	GetAll(context.Context, string, int, model.ReactionFilter, model.Page) ([]model.Reaction, model.PageInfo, error)
+	Create(context.Context, model.Reaction) (model.Reaction, error)
+	Delete(context.Context, model.Reaction) error
+	Count(context.Context, string, []int, string) (map[int][]model.ReactionCount, error)
+	PurgeOrphans(context.Context) (int64, error)
+}
+
+// Service is the interface all reaction services must implement.
+type Service interface {
+	GetAll(context.Context, string, int, model.ReactionFilter, model.Page) ([]model.Reaction, model.PageInfo, error)
+	Add(context.Context, model.Reaction) (model.Reaction, error)
+	Remove(context.Context, model.Reaction) error
+	Count(context.Context, string, []int, string) (map[int][]model.ReactionCount, error)
+	PurgeOrphans(context.Context) (int64, error)
+}
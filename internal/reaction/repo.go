@@ -0,0 +1,151 @@
+package reaction
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/store"
+)
+
+// repo is reaction repository implementation.
+type repo struct {
+	db *gorm.DB
+}
+
+// NewRepo creates and returns a new Repo instance.
+func NewRepo(db *gorm.DB) Repo {
+	return &repo{db}
+}
+
+// sortColumns maps sort parameters to reaction table columns.
+var sortColumns = map[string]string{
+	"":        "id",
+	"id":      "id",
+	"created": "created_at",
+}
+
+// targetTables maps reaction target types to the tables of reacted items.
+var targetTables = map[string]string{
+	model.ReactionPost:    "posts",
+	model.ReactionComment: "comments",
+}
+
+// GetAll gets and returns the page of reactions on the item with specific
+// type and ID matching the filter.
+func (r *repo) GetAll(ctx context.Context, targetType string, targetID int, f model.ReactionFilter, pg model.Page) (
+	rs []model.Reaction, pi model.PageInfo, err error,
+) {
+	q := r.db.WithContext(ctx).Model(&model.Reaction{}).Where("target_type = ? AND target_id = ?", targetType, targetID)
+	if f.Kind != "" {
+		q = q.Where("kind = ?", f.Kind)
+	}
+
+	if err := q.Session(&gorm.Session{}).Count(&pi.Total).Error; err != nil {
+		return nil, pi, store.Wrap(err)
+	}
+	if err := q.Scopes(pg.Paginate(sortColumns[pg.Sort])).Find(&rs).Error; err != nil {
+		return nil, pi, store.Wrap(err)
+	}
+
+	if len(rs) > pg.Limit {
+		rs = rs[:pg.Limit]
+		last := rs[len(rs)-1]
+		if pg.Sort == "created" {
+			pi.NextCursor = pg.NextCursor(last.CreatedAt, last.ID)
+		} else {
+			pi.NextCursor = pg.NextCursor(last.ID, last.ID)
+		}
+	}
+
+	return rs, pi, nil
+}
+
+// Create creates a reaction and returns it.
+func (r *repo) Create(ctx context.Context, rc model.Reaction) (model.Reaction, error) {
+	if err := r.db.WithContext(ctx).Create(&rc).Error; err != nil {
+		// The unique index rejects the reaction the user has already added.
+		if _, err := r.find(ctx, rc); err == nil {
+			return model.Reaction{}, ErrAlreadyReacted
+		}
+
+		return model.Reaction{}, store.Wrap(err)
+	}
+
+	return rc, nil
+}
+
+// Delete deletes the user's reaction of the kind on the item.
+func (r *repo) Delete(ctx context.Context, rc model.Reaction) error {
+	found, err := r.find(ctx, rc)
+	if err != nil {
+		return store.Wrap(err)
+	}
+
+	return store.Wrap(r.db.WithContext(ctx).Delete(&model.Reaction{}, found.ID).Error)
+}
+
+// find gets and returns the user's reaction of the kind on the item.
+func (r *repo) find(ctx context.Context, rc model.Reaction) (found model.Reaction, err error) {
+	err = r.db.WithContext(ctx).Where(
+		"target_type = ? AND target_id = ? AND kind = ? AND user_id = ?",
+		rc.TargetType, rc.TargetID, rc.Kind, rc.UserID,
+	).First(&found).Error
+	if err == gorm.ErrRecordNotFound {
+		return found, ErrNotFound
+	}
+
+	return found, err
+}
+
+// Count gets and returns the numbers of reactions of every kind on the items
+// with specific type and IDs, the most added kinds go first. Reactions added
+// by the user with specific ID are flagged.
+func (r *repo) Count(ctx context.Context, targetType string, ids []int, userID string) (map[int][]model.ReactionCount, error) {
+	var rows []struct {
+		TargetID int
+		Kind     string
+		Count    int64
+		Mine     int
+	}
+	err := r.db.WithContext(ctx).Model(&model.Reaction{}).
+		Select(
+			"target_id, kind, COUNT(*) AS count, MAX(CASE WHEN user_id = ? THEN 1 ELSE 0 END) AS mine",
+			userID,
+		).
+		Where("target_type = ? AND target_id IN ?", targetType, ids).
+		Group("target_id, kind").
+		Order("target_id, count DESC, kind").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, store.Wrap(err)
+	}
+
+	counts := make(map[int][]model.ReactionCount, len(ids))
+	for _, row := range rows {
+		counts[row.TargetID] = append(counts[row.TargetID], model.ReactionCount{
+			Kind: row.Kind, Count: row.Count, ReactedByMe: row.Mine == 1,
+		})
+	}
+
+	return counts, nil
+}
+
+// PurgeOrphans deletes reactions on posts and comments that were deleted
+// permanently and returns the number of deleted reactions. Reactions on items
+// in trash are kept until the items are restored or purged.
+func (r *repo) PurgeOrphans(ctx context.Context) (n int64, err error) {
+	for targetType, table := range targetTables {
+		res := r.db.WithContext(ctx).Where(
+			"target_type = ? AND target_id NOT IN (?)",
+			targetType, r.db.Table(table).Select("id"),
+		).Delete(&model.Reaction{})
+		if res.Error != nil {
+			return n, store.Wrap(res.Error)
+		}
+		n += res.RowsAffected
+	}
+
+	return n, nil
+}
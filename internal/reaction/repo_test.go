@@ -0,0 +1,124 @@
+package reaction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/testdb"
+)
+
+// testModels are the models migrated in test databases.
+var testModels = []interface{}{&model.Tag{}, &model.Category{}, &model.Post{}, &model.Comment{}, &model.Reaction{}}
+
+func TestReactionRepo_CreateDelete(t *testing.T) {
+	r := NewRepo(testdb.New(t, testModels...))
+	like := model.Reaction{TargetType: model.ReactionPost, TargetID: 1, Kind: "like", UserID: "1"}
+
+	rc, err := r.Create(context.Background(), like)
+	assert.NoError(t, err)
+	assert.NotZero(t, rc.ID)
+	_, err = r.Create(context.Background(), like)
+	assert.Equal(t, ErrAlreadyReacted, err)
+
+	// The same kind on a comment with the same ID is another reaction.
+	_, err = r.Create(context.Background(), model.Reaction{TargetType: model.ReactionComment, TargetID: 1, Kind: "like", UserID: "1"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, r.Delete(context.Background(), like))
+	assert.Equal(t, ErrNotFound, r.Delete(context.Background(), like))
+	_, err = r.Create(context.Background(), like)
+	assert.NoError(t, err)
+}
+
+func TestReactionRepo_GetAll(t *testing.T) {
+	r := NewRepo(testdb.New(t, testModels...))
+	for _, rc := range []model.Reaction{
+		{TargetType: model.ReactionPost, TargetID: 1, Kind: "like", UserID: "1"},
+		{TargetType: model.ReactionPost, TargetID: 1, Kind: "heart", UserID: "1"},
+		{TargetType: model.ReactionPost, TargetID: 1, Kind: "like", UserID: "2"},
+		{TargetType: model.ReactionPost, TargetID: 2, Kind: "like", UserID: "3"},
+	} {
+		if _, err := r.Create(context.Background(), rc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rs, pi, err := r.GetAll(context.Background(), model.ReactionPost, 1, model.ReactionFilter{}, model.Page{Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), pi.Total)
+	assert.Len(t, rs, 2)
+	assert.NotEmpty(t, pi.NextCursor)
+	rs, pi, err = r.GetAll(context.Background(), model.ReactionPost, 1, model.ReactionFilter{}, model.Page{Limit: 2, Cursor: pi.NextCursor})
+	assert.NoError(t, err)
+	assert.Len(t, rs, 1)
+	assert.Equal(t, "2", rs[0].UserID)
+	assert.Empty(t, pi.NextCursor)
+
+	rs, pi, err = r.GetAll(context.Background(), model.ReactionPost, 1, model.ReactionFilter{Kind: "like"}, model.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), pi.Total)
+	assert.Equal(t, "1", rs[0].UserID)
+	assert.Equal(t, "2", rs[1].UserID)
+}
+
+func TestReactionRepo_Count(t *testing.T) {
+	r := NewRepo(testdb.New(t, testModels...))
+	for _, rc := range []model.Reaction{
+		{TargetType: model.ReactionPost, TargetID: 1, Kind: "heart", UserID: "1"},
+		{TargetType: model.ReactionPost, TargetID: 1, Kind: "like", UserID: "1"},
+		{TargetType: model.ReactionPost, TargetID: 1, Kind: "like", UserID: "2"},
+		{TargetType: model.ReactionPost, TargetID: 2, Kind: "sad", UserID: "2"},
+		{TargetType: model.ReactionComment, TargetID: 1, Kind: "laugh", UserID: "2"},
+	} {
+		if _, err := r.Create(context.Background(), rc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	counts, err := r.Count(context.Background(), model.ReactionPost, []int{1, 2, 3}, "2")
+	assert.NoError(t, err)
+	assert.Equal(t, map[int][]model.ReactionCount{
+		1: {{Kind: "like", Count: 2, ReactedByMe: true}, {Kind: "heart", Count: 1}},
+		2: {{Kind: "sad", Count: 1, ReactedByMe: true}},
+	}, counts)
+
+	counts, err = r.Count(context.Background(), model.ReactionComment, []int{1}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, map[int][]model.ReactionCount{1: {{Kind: "laugh", Count: 1}}}, counts)
+}
+
+func TestReactionRepo_PurgeOrphans(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	r := NewRepo(db)
+	kept := model.Post{Title: "Kept", Body: "Body", UserID: "1"}
+	trashed := model.Post{Title: "Trashed", Body: "Body", UserID: "1"}
+	for _, p := range []*model.Post{&kept, &trashed} {
+		if err := db.Create(p).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Delete(&model.Post{}, trashed.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, rc := range []model.Reaction{
+		{TargetType: model.ReactionPost, TargetID: kept.ID, Kind: "like", UserID: "1"},
+		{TargetType: model.ReactionPost, TargetID: trashed.ID, Kind: "like", UserID: "1"},
+		{TargetType: model.ReactionPost, TargetID: 100, Kind: "like", UserID: "1"},
+		{TargetType: model.ReactionComment, TargetID: 100, Kind: "like", UserID: "1"},
+	} {
+		if _, err := r.Create(context.Background(), rc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := r.PurgeOrphans(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	var left int64
+	db.Model(&model.Reaction{}).Count(&left)
+	assert.Equal(t, int64(2), left)
+}
@@ -0,0 +1,100 @@
+package reaction
+
+import (
+	"context"
+
+	"github.com/imarrche/nix-ed/internal/comment"
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/post"
+)
+
+// sorts are reaction list sort parameters.
+var sorts = []string{"id", "created"}
+
+// service is reaction service implementation.
+type service struct {
+	r  Repo
+	pr post.Repo
+	cr comment.Repo
+}
+
+// NewService creates and returns a new Service instance.
+func NewService(r Repo, pr post.Repo, cr comment.Repo) Service {
+	return &service{r: r, pr: pr, cr: cr}
+}
+
+// GetAll gets and returns the page of reactions on the item with specific
+// type and ID matching the filter, the item must be visible to the filter's
+// viewer.
+func (s *service) GetAll(ctx context.Context, targetType string, targetID int, f model.ReactionFilter, pg model.Page) (
+	[]model.Reaction, model.PageInfo, error,
+) {
+	if err := pg.Validate(sorts...); err != nil {
+		return nil, model.PageInfo{}, err
+	}
+	if err := f.Validate(); err != nil {
+		return nil, model.PageInfo{}, err
+	}
+	if err := s.targetVisible(ctx, targetType, targetID, f.Viewer); err != nil {
+		return nil, model.PageInfo{}, err
+	}
+
+	return s.r.GetAll(ctx, targetType, targetID, f, pg)
+}
+
+// Add adds the user's reaction on a post or a comment visible to them and
+// returns it.
+func (s *service) Add(ctx context.Context, rc model.Reaction) (model.Reaction, error) {
+	if err := rc.Validate(); err != nil {
+		return model.Reaction{}, err
+	}
+	if err := s.targetVisible(ctx, rc.TargetType, rc.TargetID, rc.UserID); err != nil {
+		return model.Reaction{}, err
+	}
+
+	return s.r.Create(ctx, rc)
+}
+
+// targetVisible returns ErrTargetNotFound when the item with specific type
+// and ID doesn't exist, is in trash or belongs to a post that isn't visible to
+// the user with specific ID.
+func (s *service) targetVisible(ctx context.Context, targetType string, targetID int, viewer string) error {
+	postID := targetID
+	if targetType == model.ReactionComment {
+		c, err := s.cr.GetByID(ctx, targetID)
+		if err == comment.ErrNotFound {
+			return ErrTargetNotFound
+		} else if err != nil {
+			return err
+		}
+		postID = c.PostID
+	}
+
+	p, err := s.pr.GetByID(ctx, postID)
+	if err == post.ErrNotFound || err == nil && !p.VisibleTo(viewer) {
+		return ErrTargetNotFound
+	}
+
+	return err
+}
+
+// Remove removes the user's reaction of the kind from the item.
+func (s *service) Remove(ctx context.Context, rc model.Reaction) error {
+	return s.r.Delete(ctx, rc)
+}
+
+// Count gets and returns the numbers of reactions of every kind on the items
+// with specific type and IDs, reactions of the user with specific ID are
+// flagged.
+func (s *service) Count(ctx context.Context, targetType string, ids []int, userID string) (map[int][]model.ReactionCount, error) {
+	if len(ids) == 0 {
+		return map[int][]model.ReactionCount{}, nil
+	}
+
+	return s.r.Count(ctx, targetType, ids, userID)
+}
+
+// PurgeOrphans deletes reactions on permanently deleted posts and comments.
+func (s *service) PurgeOrphans(ctx context.Context) (int64, error) {
+	return s.r.PurgeOrphans(ctx)
+}
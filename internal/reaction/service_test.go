@@ -0,0 +1,213 @@
+package reaction
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/comment"
+	mockcomment "github.com/imarrche/nix-ed/internal/comment/mock"
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/post"
+	mockpost "github.com/imarrche/nix-ed/internal/post/mock"
+	mockreaction "github.com/imarrche/nix-ed/internal/reaction/mock"
+)
+
+func TestReactionService_Add(t *testing.T) {
+	postLike := model.Reaction{TargetType: model.ReactionPost, TargetID: 1, Kind: "like", UserID: "1"}
+	commentLike := model.Reaction{TargetType: model.ReactionComment, TargetID: 1, Kind: "like", UserID: "1"}
+
+	testcases := []struct {
+		name        string
+		mock        func(*mockreaction.MockRepo, *mockpost.MockRepo, *mockcomment.MockRepo)
+		reaction    model.Reaction
+		expReaction model.Reaction
+		expError    error
+	}{
+		{
+			name: "reaction on a post is added",
+			mock: func(r *mockreaction.MockRepo, pr *mockpost.MockRepo, _ *mockcomment.MockRepo) {
+				pr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostPublished}, nil)
+				added := postLike
+				added.ID = 1
+				r.EXPECT().Create(gomock.Any(), postLike).Return(added, nil)
+			},
+			reaction: postLike,
+			expReaction: model.Reaction{
+				ID: 1, TargetType: model.ReactionPost, TargetID: 1, Kind: "like", UserID: "1",
+			},
+		},
+		{
+			name: "reaction on a comment is added",
+			mock: func(r *mockreaction.MockRepo, pr *mockpost.MockRepo, cr *mockcomment.MockRepo) {
+				cr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Comment{ID: 1, PostID: 2}, nil)
+				pr.EXPECT().GetByID(gomock.Any(), 2).Return(model.Post{ID: 2, Status: model.PostArchived}, nil)
+				r.EXPECT().Create(gomock.Any(), commentLike).Return(commentLike, nil)
+			},
+			reaction:    commentLike,
+			expReaction: commentLike,
+		},
+		{
+			name: "post is not found",
+			mock: func(_ *mockreaction.MockRepo, pr *mockpost.MockRepo, _ *mockcomment.MockRepo) {
+				pr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{}, post.ErrNotFound)
+			},
+			reaction: postLike,
+			expError: ErrTargetNotFound,
+		},
+		{
+			name: "comment is not found",
+			mock: func(_ *mockreaction.MockRepo, _ *mockpost.MockRepo, cr *mockcomment.MockRepo) {
+				cr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Comment{}, comment.ErrNotFound)
+			},
+			reaction: commentLike,
+			expError: ErrTargetNotFound,
+		},
+		{
+			name: "post is a draft of another user",
+			mock: func(_ *mockreaction.MockRepo, pr *mockpost.MockRepo, _ *mockcomment.MockRepo) {
+				pr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostDraft, UserID: "2"}, nil)
+			},
+			reaction: postLike,
+			expError: ErrTargetNotFound,
+		},
+		{
+			name: "comment is on a draft of another user",
+			mock: func(_ *mockreaction.MockRepo, pr *mockpost.MockRepo, cr *mockcomment.MockRepo) {
+				cr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Comment{ID: 1, PostID: 2}, nil)
+				pr.EXPECT().GetByID(gomock.Any(), 2).Return(model.Post{ID: 2, Status: model.PostScheduled, UserID: "2"}, nil)
+			},
+			reaction: commentLike,
+			expError: ErrTargetNotFound,
+		},
+		{
+			name: "reaction is already added",
+			mock: func(r *mockreaction.MockRepo, pr *mockpost.MockRepo, _ *mockcomment.MockRepo) {
+				pr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostPublished}, nil)
+				r.EXPECT().Create(gomock.Any(), postLike).Return(model.Reaction{}, ErrAlreadyReacted)
+			},
+			reaction: postLike,
+			expError: ErrAlreadyReacted,
+		},
+		{
+			name:     "validation errors",
+			mock:     func(_ *mockreaction.MockRepo, _ *mockpost.MockRepo, _ *mockcomment.MockRepo) {},
+			reaction: model.Reaction{TargetType: model.ReactionPost, TargetID: 1, Kind: "dislike", UserID: "1"},
+			expError: validation.Errors{"kind": errors.New("must be a valid value")},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mockreaction.NewMockRepo(c)
+			pr := mockpost.NewMockRepo(c)
+			cr := mockcomment.NewMockRepo(c)
+			tc.mock(repo, pr, cr)
+			s := NewService(repo, pr, cr)
+
+			rc, err := s.Add(context.Background(), tc.reaction)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expReaction, rc)
+		})
+	}
+}
+
+func TestReactionService_GetAll(t *testing.T) {
+	testcases := []struct {
+		name       string
+		mock       func(*mockreaction.MockRepo, *mockpost.MockRepo, *mockcomment.MockRepo)
+		targetType string
+		filter     model.ReactionFilter
+		page       model.Page
+		expError   error
+	}{
+		{
+			name: "reactions are retrieved",
+			mock: func(r *mockreaction.MockRepo, pr *mockpost.MockRepo, _ *mockcomment.MockRepo) {
+				pr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Post{ID: 1, Status: model.PostPublished}, nil)
+				f := model.ReactionFilter{Kind: "like"}
+				r.EXPECT().GetAll(gomock.Any(), model.ReactionPost, 1, f, model.Page{Limit: 10}).Return(nil, model.PageInfo{}, nil)
+			},
+			targetType: model.ReactionPost,
+			filter:     model.ReactionFilter{Kind: "like"},
+			page:       model.Page{Limit: 10},
+		},
+		{
+			name: "comment is on a draft of another user",
+			mock: func(_ *mockreaction.MockRepo, pr *mockpost.MockRepo, cr *mockcomment.MockRepo) {
+				cr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Comment{ID: 1, PostID: 2}, nil)
+				pr.EXPECT().GetByID(gomock.Any(), 2).Return(model.Post{ID: 2, Status: model.PostDraft, UserID: "2"}, nil)
+			},
+			targetType: model.ReactionComment,
+			page:       model.Page{Limit: 10},
+			expError:   ErrTargetNotFound,
+		},
+		{
+			name: "comment is on a draft of the viewer",
+			mock: func(r *mockreaction.MockRepo, pr *mockpost.MockRepo, cr *mockcomment.MockRepo) {
+				cr.EXPECT().GetByID(gomock.Any(), 1).Return(model.Comment{ID: 1, PostID: 2}, nil)
+				pr.EXPECT().GetByID(gomock.Any(), 2).Return(model.Post{ID: 2, Status: model.PostDraft, UserID: "2"}, nil)
+				f := model.ReactionFilter{Viewer: "2"}
+				r.EXPECT().GetAll(gomock.Any(), model.ReactionComment, 1, f, model.Page{Limit: 10}).Return(nil, model.PageInfo{}, nil)
+			},
+			targetType: model.ReactionComment,
+			filter:     model.ReactionFilter{Viewer: "2"},
+			page:       model.Page{Limit: 10},
+		},
+		{
+			name:       "invalid kind",
+			mock:       func(_ *mockreaction.MockRepo, _ *mockpost.MockRepo, _ *mockcomment.MockRepo) {},
+			targetType: model.ReactionPost,
+			filter:     model.ReactionFilter{Kind: "dislike"},
+			page:       model.Page{Limit: 10},
+			expError:   validation.Errors{"kind": errors.New("must be a valid value")},
+		},
+		{
+			name:       "invalid page",
+			mock:       func(_ *mockreaction.MockRepo, _ *mockpost.MockRepo, _ *mockcomment.MockRepo) {},
+			targetType: model.ReactionPost,
+			page:       model.Page{Limit: 10, Sort: "kind"},
+			expError:   validation.Errors{"sort": errors.New("must be a valid value")},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mockreaction.NewMockRepo(c)
+			pr := mockpost.NewMockRepo(c)
+			cr := mockcomment.NewMockRepo(c)
+			tc.mock(repo, pr, cr)
+			s := NewService(repo, pr, cr)
+
+			_, _, err := s.GetAll(context.Background(), tc.targetType, 1, tc.filter, tc.page)
+
+			assert.Equal(t, tc.expError, err)
+		})
+	}
+}
+
+func TestReactionService_Count(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	repo := mockreaction.NewMockRepo(c)
+	s := NewService(repo, nil, nil)
+
+	counts, err := s.Count(context.Background(), model.ReactionPost, nil, "1")
+	assert.NoError(t, err)
+	assert.Empty(t, counts)
+
+	exp := map[int][]model.ReactionCount{1: {{Kind: "like", Count: 1}}}
+	repo.EXPECT().Count(gomock.Any(), model.ReactionPost, []int{1}, "1").Return(exp, nil)
+	counts, err = s.Count(context.Background(), model.ReactionPost, []int{1}, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, exp, counts)
+}
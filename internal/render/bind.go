@@ -0,0 +1,67 @@
+package render
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/ghodss/yaml"
+	"github.com/labstack/echo/v4"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// decoders decode request bodies of the formats echo doesn't, struct fields
+// are named by their json tags.
+var decoders = map[string]func([]byte, interface{}) error{
+	MIMEYAML:                yaml.Unmarshal,
+	"application/x-yaml":    yaml.Unmarshal,
+	"text/yaml":             yaml.Unmarshal,
+	MIMEMsgPack:             decodeMsgPack,
+	"application/x-msgpack": decodeMsgPack,
+}
+
+// Binder is echo binder decoding YAML and MessagePack request bodies besides
+// the JSON, XML and form ones echo decodes. Bodies of other media types are
+// unsupported.
+type Binder struct {
+	echo.DefaultBinder
+}
+
+// Bind binds path and query params and the request body to i.
+func (b *Binder) Bind(i interface{}, c echo.Context) error {
+	req := c.Request()
+	mt, _, _ := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+	decode, ok := decoders[mt]
+	if !ok || req.ContentLength == 0 {
+		return b.DefaultBinder.Bind(i, c)
+	}
+
+	// Params are bound by echo from the request without its body.
+	noBody := *req
+	noBody.Body, noBody.ContentLength = http.NoBody, 0
+	c.SetRequest(&noBody)
+	err := b.DefaultBinder.Bind(i, c)
+	c.SetRequest(req)
+	if err != nil {
+		return err
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	if err := decode(body, i); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+
+	return nil
+}
+
+// decodeMsgPack decodes the MessagePack data to v.
+func decodeMsgPack(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+
+	return dec.Decode(v)
+}
@@ -0,0 +1,170 @@
+package render
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// column is a CSV column of a struct field.
+type column struct {
+	name  string
+	index []int
+}
+
+// encodeCSV encodes the list as CSV with a header row. Columns are the
+// scalar fields of the list's structs named by their json tags, lists and
+// nested objects are left out. Cells that spreadsheets would take for
+// formulas are escaped.
+func encodeCSV(data interface{}) ([]byte, error) {
+	v := reflect.Indirect(reflect.ValueOf(data))
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("csv: %T is not a list", data)
+	}
+	t := v.Type().Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if t.Kind() != reflect.Struct {
+		if err := w.Write([]string{"value"}); err != nil {
+			return nil, err
+		}
+		for i := 0; i < v.Len(); i++ {
+			s, err := csvValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			if err := w.Write([]string{escapeFormula(s)}); err != nil {
+				return nil, err
+			}
+		}
+		w.Flush()
+		return buf.Bytes(), w.Error()
+	}
+
+	cols := columns(t, nil)
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.name
+	}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for i := 0; i < v.Len(); i++ {
+		e := reflect.Indirect(v.Index(i))
+		row := make([]string, len(cols))
+		for j, c := range cols {
+			if !e.IsValid() {
+				continue
+			}
+			s, err := csvValue(e.FieldByIndex(c.index))
+			if err != nil {
+				return nil, err
+			}
+			row[j] = escapeFormula(s)
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+// columns returns the columns of the struct's scalar fields, fields of
+// embedded structs are columns of their own.
+func columns(t reflect.Type, index []int) []column {
+	var cols []column
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		idx := append(append([]int{}, index...), i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			cols = append(cols, columns(f.Type, idx)...)
+			continue
+		}
+		if f.PkgPath != "" || !isScalar(f.Type) {
+			continue
+		}
+
+		name := f.Name
+		if tag := f.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; n != "" {
+				name = n
+			}
+		}
+		cols = append(cols, column{name: name, index: idx})
+	}
+
+	return cols
+}
+
+// isScalar reports whether values of the type fit a single CSV cell.
+func isScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// csvValue returns the cell of the scalar value, nil pointers are empty cells
+// and text marshalers, such as times, are cells of their text.
+func csvValue(v reflect.Value) (string, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	if v.CanAddr() {
+		if m, ok := v.Addr().Interface().(encoding.TextMarshaler); ok {
+			b, err := m.MarshalText()
+			return string(b), err
+		}
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		return string(b), err
+	}
+	if k := v.Kind(); k == reflect.Float32 || k == reflect.Float64 {
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	}
+
+	return fmt.Sprint(v.Interface()), nil
+}
+
+// escapeFormula prefixes the cell with a quote when it starts like a
+// spreadsheet formula, so user content opened in a spreadsheet isn't run.
+// Leading tabs and carriage returns count too, spreadsheets strip them before
+// reading the formula.
+func escapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+
+	return cell
+}
@@ -0,0 +1,213 @@
+// Package render negotiates the format of responses with the Accept header
+// and decodes request bodies by their Content-Type.
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/labstack/echo/v4"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Media types of the supported formats.
+const (
+	MIMEJSON    = echo.MIMEApplicationJSON
+	MIMEXML     = echo.MIMEApplicationXML
+	MIMEYAML    = "application/yaml"
+	MIMEMsgPack = echo.MIMEApplicationMsgpack
+	MIMECSV     = "text/csv"
+)
+
+// format is a response format, it's offered under its media type and the
+// aliases clients commonly use for it.
+type format struct {
+	mime    string
+	aliases []string
+	charset bool
+	encode  func(interface{}) ([]byte, error)
+}
+
+// formats are the response formats in the order they're preferred in when the
+// client accepts several of them equally. CSV is offered for lists only.
+var formats = []format{
+	{mime: MIMEJSON, charset: true, encode: json.Marshal},
+	{mime: MIMEXML, aliases: []string{echo.MIMETextXML}, charset: true, encode: encodeXML},
+	{mime: MIMEYAML, aliases: []string{"application/x-yaml", "text/yaml"}, charset: true, encode: yaml.Marshal},
+	{mime: MIMEMsgPack, aliases: []string{"application/x-msgpack"}, encode: encodeMsgPack},
+	{mime: MIMECSV, charset: true, encode: encodeCSV},
+}
+
+// Respond responds with the data encoded in the format the request's Accept
+// header prefers. Requests accepting none of the formats are not acceptable.
+func Respond(c echo.Context, code int, data interface{}) error {
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+
+	offers, byOffer := []string{}, map[string]format{}
+	for _, f := range formats {
+		if f.mime == MIMECSV && !isList(data) {
+			continue
+		}
+		for _, mt := range append([]string{f.mime}, f.aliases...) {
+			offers = append(offers, mt)
+			byOffer[mt] = f
+		}
+	}
+
+	mt, ok := Negotiate(c.Request().Header.Get(echo.HeaderAccept), offers)
+	if !ok {
+		return echo.NewHTTPError(
+			http.StatusNotAcceptable,
+			fmt.Sprintf("response is available as %s", strings.Join(offers, ", ")),
+		)
+	}
+	f := byOffer[mt]
+	b, err := f.encode(data)
+	if err != nil {
+		return err
+	}
+	if f.charset {
+		mt += "; charset=UTF-8"
+	}
+
+	return c.Blob(code, mt, b)
+}
+
+// mediaRange is a media range of the Accept header with its quality.
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// match returns how specific the range matching the media type is, or -1 when
+// it doesn't match.
+func (r mediaRange) match(typ, subtype string) int {
+	switch {
+	case r.typ == "*" && r.subtype == "*":
+		return 0
+	case r.typ != typ:
+		return -1
+	case r.subtype == "*":
+		return 1
+	case r.subtype == subtype:
+		return 2
+	}
+
+	return -1
+}
+
+// parseAccept returns media ranges of the Accept header, malformed ones are
+// skipped.
+func parseAccept(accept string) []mediaRange {
+	var rs []mediaRange
+	for _, s := range strings.Split(accept, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		mt, params, err := mime.ParseMediaType(s)
+		if err != nil {
+			continue
+		}
+		if mt == "*" {
+			mt = "*/*"
+		}
+		typ, subtype, ok := splitMediaType(mt)
+		if !ok {
+			continue
+		}
+
+		r := mediaRange{typ: typ, subtype: subtype, q: 1}
+		if v, ok := params["q"]; ok {
+			q, err := strconv.ParseFloat(v, 64)
+			if err != nil || q < 0 || q > 1 {
+				continue
+			}
+			r.q = q
+		}
+		rs = append(rs, r)
+	}
+
+	return rs
+}
+
+// splitMediaType splits the media type into its type and subtype.
+func splitMediaType(mt string) (string, string, bool) {
+	i := strings.IndexByte(mt, '/')
+	if i <= 0 || i == len(mt)-1 {
+		return "", "", false
+	}
+
+	return mt[:i], mt[i+1:], true
+}
+
+// Negotiate returns the offered media type the Accept header prefers, the
+// quality of an offer is the one of the most specific range matching it and
+// ties go to the earlier offer. The first offer is returned when the header
+// is missing or has no valid ranges, false is returned when the header
+// accepts none of the offers.
+func Negotiate(accept string, offers []string) (string, bool) {
+	if len(offers) == 0 {
+		return "", false
+	}
+	rs := parseAccept(accept)
+	if len(rs) == 0 {
+		return offers[0], true
+	}
+
+	best, bestQ := "", 0.0
+	for _, o := range offers {
+		typ, subtype, ok := splitMediaType(o)
+		if !ok {
+			continue
+		}
+		q, specificity := 0.0, -1
+		for _, r := range rs {
+			if s := r.match(typ, subtype); s > specificity {
+				q, specificity = r.q, s
+			}
+		}
+		if q > bestQ {
+			best, bestQ = o, q
+		}
+	}
+
+	return best, bestQ > 0
+}
+
+// isList reports whether the data is a slice or an array.
+func isList(data interface{}) bool {
+	k := reflect.Indirect(reflect.ValueOf(data)).Kind()
+	return k == reflect.Slice || k == reflect.Array
+}
+
+// encodeXML encodes the data as XML document.
+func encodeXML(data interface{}) ([]byte, error) {
+	b, err := xml.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
+
+// encodeMsgPack encodes the data as MessagePack, struct fields are named by
+// their json tags like in the other formats.
+func encodeMsgPack(data interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := msgpack.NewEncoder(buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,296 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// item is a listed model with nested fields left out of CSV.
+type item struct {
+	ID        int        `json:"id" xml:"id"`
+	Name      string     `json:"name" xml:"name"`
+	Score     float64    `json:"score" xml:"score"`
+	PublishAt *time.Time `json:"publishAt,omitempty" xml:"publishAt,omitempty"`
+	Tags      []string   `json:"tags" xml:"tags"`
+	Secret    string     `json:"-" xml:"-"`
+}
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{MIMEJSON, MIMEXML, MIMEYAML}
+
+	testcases := []struct {
+		name   string
+		accept string
+		exp    string
+		expOK  bool
+	}{
+		{name: "no header", accept: "", exp: MIMEJSON, expOK: true},
+		{name: "exact type", accept: "application/xml", exp: MIMEXML, expOK: true},
+		{name: "any type", accept: "*/*", exp: MIMEJSON, expOK: true},
+		{name: "subtype wildcard", accept: "text/*, application/*;q=0.5", exp: MIMEJSON, expOK: true},
+		{name: "highest quality", accept: "application/json;q=0.5, application/yaml;q=0.9", exp: MIMEYAML, expOK: true},
+		{name: "quality ties go to earlier offer", accept: "application/yaml, application/xml", exp: MIMEXML, expOK: true},
+		{name: "specific range wins", accept: "application/*, application/json;q=0", exp: MIMEXML, expOK: true},
+		{name: "browser header", accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", exp: MIMEXML, expOK: true},
+		{name: "malformed ranges are skipped", accept: "application/json;q=2, application/yaml", exp: MIMEYAML, expOK: true},
+		{name: "nothing fits", accept: "text/html", expOK: false},
+		{name: "everything refused", accept: "*/*;q=0", expOK: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			mt, ok := Negotiate(tc.accept, offers)
+			assert.Equal(t, tc.expOK, ok)
+			assert.Equal(t, tc.exp, mt)
+		})
+	}
+}
+
+func TestRespond(t *testing.T) {
+	at := time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)
+	items := []item{
+		{ID: 1, Name: "first, quoted \"name\"", Score: 0.5, PublishAt: &at, Tags: []string{"go"}, Secret: "s"},
+		{ID: 2, Name: "second", Score: 1000000},
+	}
+
+	testcases := []struct {
+		name      string
+		accept    string
+		data      interface{}
+		expCode   int
+		expType   string
+		checkBody func(*testing.T, []byte)
+	}{
+		{
+			name:    "json",
+			data:    items[0],
+			expCode: http.StatusOK,
+			expType: "application/json; charset=UTF-8",
+			checkBody: func(t *testing.T, b []byte) {
+				var i item
+				assert.NoError(t, json.Unmarshal(b, &i))
+				assert.Equal(t, item{ID: 1, Name: items[0].Name, Score: 0.5, PublishAt: &at, Tags: []string{"go"}}, i)
+			},
+		},
+		{
+			name:    "xml",
+			accept:  "text/xml",
+			data:    items[1],
+			expCode: http.StatusOK,
+			expType: "text/xml; charset=UTF-8",
+			checkBody: func(t *testing.T, b []byte) {
+				var i item
+				assert.NoError(t, xml.Unmarshal(b, &i))
+				assert.Equal(t, 2, i.ID)
+			},
+		},
+		{
+			name:    "yaml",
+			accept:  "application/json;q=0.1, application/x-yaml",
+			data:    items[0],
+			expCode: http.StatusOK,
+			expType: "application/x-yaml; charset=UTF-8",
+			checkBody: func(t *testing.T, b []byte) {
+				assert.Contains(t, string(b), "publishAt: \"2021-02-03T04:05:06Z\"")
+				var i item
+				assert.NoError(t, yaml.Unmarshal(b, &i))
+				assert.Equal(t, "go", i.Tags[0])
+			},
+		},
+		{
+			name:    "msgpack",
+			accept:  "application/msgpack",
+			data:    items[0],
+			expCode: http.StatusOK,
+			expType: MIMEMsgPack,
+			checkBody: func(t *testing.T, b []byte) {
+				var m map[string]interface{}
+				assert.NoError(t, msgpack.Unmarshal(b, &m))
+				assert.Equal(t, items[0].Name, m["name"])
+				assert.NotContains(t, m, "Secret")
+			},
+		},
+		{
+			name:    "csv",
+			accept:  "text/csv",
+			data:    items,
+			expCode: http.StatusOK,
+			expType: "text/csv; charset=UTF-8",
+			checkBody: func(t *testing.T, b []byte) {
+				assert.Equal(t, "id,name,score,publishAt\n"+
+					"1,\"first, quoted \"\"name\"\"\",0.5,2021-02-03T04:05:06Z\n"+
+					"2,second,1000000,\n", string(b))
+			},
+		},
+		{
+			name:    "csv of scalars",
+			accept:  "text/csv",
+			data:    []string{"a", "b"},
+			expCode: http.StatusOK,
+			expType: "text/csv; charset=UTF-8",
+			checkBody: func(t *testing.T, b []byte) {
+				assert.Equal(t, "value\na\nb\n", string(b))
+			},
+		},
+		{
+			name:    "csv is for lists only",
+			accept:  "text/csv",
+			data:    items[0],
+			expCode: http.StatusNotAcceptable,
+		},
+		{
+			name:    "not acceptable",
+			accept:  "text/html",
+			data:    items,
+			expCode: http.StatusNotAcceptable,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/items", nil)
+			if tc.accept != "" {
+				r.Header.Set(echo.HeaderAccept, tc.accept)
+			}
+
+			err := Respond(echo.New().NewContext(r, w), http.StatusOK, tc.data)
+			assert.Equal(t, echo.HeaderAccept, w.Header().Get(echo.HeaderVary))
+			if tc.expCode != http.StatusOK {
+				var he *echo.HTTPError
+				if assert.True(t, errors.As(err, &he)) {
+					assert.Equal(t, tc.expCode, he.Code)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expCode, w.Code)
+			assert.Equal(t, tc.expType, w.Header().Get(echo.HeaderContentType))
+			tc.checkBody(t, w.Body.Bytes())
+		})
+	}
+}
+
+func TestBinder_Bind(t *testing.T) {
+	packed, err := msgpack.Marshal(map[string]interface{}{"name": "packed", "score": 2.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		name        string
+		contentType string
+		body        []byte
+		expItem     item
+		expCode     int
+	}{
+		{
+			name:        "json",
+			contentType: "application/json",
+			body:        []byte(`{"name":"json","tags":["go"]}`),
+			expItem:     item{ID: 7, Name: "json", Tags: []string{"go"}},
+		},
+		{
+			name:        "xml",
+			contentType: "application/xml",
+			body:        []byte(`<item><name>xml</name></item>`),
+			expItem:     item{ID: 7, Name: "xml"},
+		},
+		{
+			name:        "yaml",
+			contentType: "application/yaml; charset=UTF-8",
+			body:        []byte("name: yaml\ntags:\n  - go\n"),
+			expItem:     item{ID: 7, Name: "yaml", Tags: []string{"go"}},
+		},
+		{
+			name:        "msgpack",
+			contentType: "application/msgpack",
+			body:        packed,
+			expItem:     item{ID: 7, Name: "packed", Score: 2.5},
+		},
+		{
+			name:        "malformed yaml",
+			contentType: "application/yaml",
+			body:        []byte("name: [yaml"),
+			expCode:     http.StatusBadRequest,
+		},
+		{
+			name:        "unsupported media type",
+			contentType: "text/csv",
+			body:        []byte("name\ncsv\n"),
+			expCode:     http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/items", bytes.NewReader(tc.body))
+			r.Header.Set(echo.HeaderContentType, tc.contentType)
+			c := echo.New().NewContext(r, httptest.NewRecorder())
+			c.SetParamNames("id")
+			c.SetParamValues("7")
+
+			i := struct {
+				ID int `param:"id"`
+				item
+			}{}
+			err := (&Binder{}).Bind(&i, c)
+			if tc.expCode != 0 {
+				var he *echo.HTTPError
+				if assert.True(t, errors.As(err, &he)) {
+					assert.Equal(t, tc.expCode, he.Code)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			i.item.ID = i.ID
+			assert.Equal(t, tc.expItem, i.item)
+			assert.Equal(t, r, c.Request())
+		})
+	}
+}
+
+func TestEncodeCSV_NilItems(t *testing.T) {
+	b, err := encodeCSV([]*item{nil, {ID: 1}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "id,name,score,publishAt\n,,,\n1,,0,\n", string(b))
+}
+
+func TestEncodeCSV_Formulas(t *testing.T) {
+	b, err := encodeCSV([]item{
+		{ID: 1, Name: "=SUM(A1:A2)"},
+		{ID: 2, Name: "+1", Score: -2},
+		{ID: 3, Name: "@cmd"},
+		{ID: 4, Name: "a=b"},
+		{ID: 5, Name: "\t=cmd"},
+		{ID: 6, Name: "\r=cmd"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "id,name,score,publishAt\n"+
+		"1,'=SUM(A1:A2),0,\n"+
+		"2,'+1,'-2,\n"+
+		"3,'@cmd,0,\n"+
+		"4,a=b,0,\n"+
+		"5,'\t=cmd,0,\n"+
+		"6,\"'\r=cmd\",0,\n", string(b))
+
+	b, err = encodeCSV([]string{"-x", "y"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "value\n'-x\ny\n", string(b))
+}
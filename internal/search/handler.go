@@ -0,0 +1,51 @@
+package search
+
+import (
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/labstack/echo/v4"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/render"
+)
+
+// Handler is http handler for search.
+type Handler struct {
+	s Service
+}
+
+// NewHandler creates and returns a new Handler instance.
+func NewHandler(s Service) *Handler {
+	return &Handler{s: s}
+}
+
+// Search returns posts and comments matching the query.
+// @Summary Search posts and comments
+// @Descriptions search posts and comments ranked by relevance, matching words are wrapped in <mark> tags
+// @Tags search
+// @ID search
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack,text/csv
+// @Param q query string true "search query" maxlength(200)
+// @Param limit query int false "page size" default(20) maximum(100)
+// @Param offset query int false "number of results to skip"
+// @Success 200 {array} model.SearchResult
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /search [get]
+func (h *Handler) Search(c echo.Context) error {
+	q := model.SearchQuery{Limit: model.DefaultPageLimit}
+	if err := c.Bind(&q); err != nil {
+		return err
+	}
+
+	rs, err := h.s.Search(c.Request().Context(), q)
+	if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusOK, rs)
+}
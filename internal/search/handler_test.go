@@ -0,0 +1,101 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/handlertest"
+	"github.com/imarrche/nix-ed/internal/model"
+	mocksearch "github.com/imarrche/nix-ed/internal/search/mock"
+)
+
+func TestHandler_Search(t *testing.T) {
+	testcases := []struct {
+		name       string
+		mock       func(*mocksearch.MockService, []model.SearchResult)
+		query      string
+		results    []model.SearchResult
+		expResults []model.SearchResult
+		expCode    int
+	}{
+		{
+			name: "results are retrieved",
+			mock: func(s *mocksearch.MockService, rs []model.SearchResult) {
+				q := model.SearchQuery{Q: "go", Limit: model.DefaultPageLimit}
+				s.EXPECT().Search(gomock.Any(), q).Return(rs, nil)
+			},
+			query: "?q=go",
+			results: []model.SearchResult{
+				{Type: model.SearchTypePost, ID: 1, PostID: 1, Title: "<mark>Go</mark>", Body: "Body"},
+				{Type: model.SearchTypeComment, ID: 2, PostID: 1, Body: "<mark>go</mark>"},
+			},
+			expResults: []model.SearchResult{
+				{Type: model.SearchTypePost, ID: 1, PostID: 1, Title: "<mark>Go</mark>", Body: "Body"},
+				{Type: model.SearchTypeComment, ID: 2, PostID: 1, Body: "<mark>go</mark>"},
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "next page is retrieved",
+			mock: func(s *mocksearch.MockService, rs []model.SearchResult) {
+				q := model.SearchQuery{Q: "go", Limit: 1, Offset: 1}
+				s.EXPECT().Search(gomock.Any(), q).Return(rs, nil)
+			},
+			query:      "?q=go&limit=1&offset=1",
+			results:    []model.SearchResult{{Type: model.SearchTypeComment, ID: 2, PostID: 1}},
+			expResults: []model.SearchResult{{Type: model.SearchTypeComment, ID: 2, PostID: 1}},
+			expCode:    http.StatusOK,
+		},
+		{
+			name:    "invalid query",
+			mock:    func(s *mocksearch.MockService, rs []model.SearchResult) {},
+			query:   "?q=go&limit=ten",
+			expCode: http.StatusBadRequest,
+		},
+		{
+			name: "validation errors",
+			mock: func(s *mocksearch.MockService, rs []model.SearchResult) {
+				q := model.SearchQuery{Limit: model.DefaultPageLimit}
+				err := validation.Errors{"q": errors.New("cannot be blank")}
+				s.EXPECT().Search(gomock.Any(), q).Return(nil, err)
+			},
+			expCode: http.StatusBadRequest,
+		},
+		{
+			name: "internal error",
+			mock: func(s *mocksearch.MockService, rs []model.SearchResult) {
+				q := model.SearchQuery{Q: "go", Limit: model.DefaultPageLimit}
+				s.EXPECT().Search(gomock.Any(), q).Return(nil, errors.New("internal error"))
+			},
+			query:   "?q=go",
+			expCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		s := mocksearch.NewMockService(c)
+		tc.mock(s, tc.results)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/search"+tc.query, nil)
+
+		ctx := echo.New().NewContext(r, w)
+
+		handlertest.Serve(ctx, NewHandler(s).Search)
+
+		var results []model.SearchResult
+		json.NewDecoder(w.Body).Decode(&results)
+
+		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expResults, results)
+	}
+}
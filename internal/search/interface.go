@@ -0,0 +1,21 @@
+// Package search provides full-text search over posts and comments.
+package search
+
+import (
+	"context"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+//go:generate mockgen -source=interface.go -destination=mock/mock.go
+
+// Service is the interface all search services must implement. Index methods
+// are called after posts and comments are written, services that search the
+// database directly may ignore them.
+type Service interface {
+	Search(context.Context, model.SearchQuery) ([]model.SearchResult, error)
+	IndexPost(model.Post)
+	RemovePost(int)
+	IndexComment(model.Comment)
+	RemoveComment(int)
+}
@@ -0,0 +1,182 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// titleWeight is how many times a word in post's title outweighs a word in
+// the body.
+const titleWeight = 2
+
+// key identifies an indexed post or comment.
+type key struct {
+	typ string
+	id  int
+}
+
+// document is an indexed post or comment.
+type document struct {
+	postID int
+	title  string
+	body   string
+	terms  map[string]int
+	length int
+}
+
+// memoryService is in-memory inverted index search service implementation.
+type memoryService struct {
+	mu       sync.RWMutex
+	docs     map[key]*document
+	postings map[string]map[key]int
+	// public keeps IDs of indexed posts visible to everyone, other posts and
+	// their comments aren't found.
+	public map[int]bool
+}
+
+// NewMemoryService creates and returns a new in-memory Service instance.
+func NewMemoryService() Service {
+	return &memoryService{
+		docs:     map[key]*document{},
+		postings: map[string]map[key]int{},
+		public:   map[int]bool{},
+	}
+}
+
+// Search finds posts and comments containing any of the query words and ranks
+// them by TF-IDF.
+func (s *memoryService) Search(ctx context.Context, q model.SearchQuery) ([]model.SearchResult, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	terms := termSet(q.Q)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	scores := map[key]float64{}
+	for t := range terms {
+		idf := math.Log(1 + float64(len(s.docs))/float64(len(s.postings[t])))
+		for k, n := range s.postings[t] {
+			d := s.docs[k]
+			if !s.public[d.postID] {
+				continue
+			}
+			scores[k] += float64(n) / float64(d.length) * idf
+		}
+	}
+
+	keys := make([]key, 0, len(scores))
+	for k := range scores {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if scores[keys[i]] != scores[keys[j]] {
+			return scores[keys[i]] > scores[keys[j]]
+		}
+		if keys[i].typ != keys[j].typ {
+			return keys[i].typ == model.SearchTypePost
+		}
+		return keys[i].id < keys[j].id
+	})
+
+	if q.Offset > len(keys) {
+		q.Offset = len(keys)
+	}
+	keys = keys[q.Offset:]
+	if len(keys) > q.Limit {
+		keys = keys[:q.Limit]
+	}
+
+	rs := make([]model.SearchResult, len(keys))
+	for i, k := range keys {
+		d := s.docs[k]
+		rs[i] = result(k.typ, k.id, d.postID, d.title, d.body, scores[k], terms)
+	}
+
+	return rs, nil
+}
+
+// IndexPost adds the post to the index or updates it. The post and its
+// comments are found only while the post is public.
+func (s *memoryService) IndexPost(p model.Post) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.public[p.ID] = p.Public()
+	s.index(key{model.SearchTypePost, p.ID}, &document{postID: p.ID, title: p.Title, body: p.Body})
+}
+
+// RemovePost removes the post and its comments from the index, comments
+// restored together with the post have to be indexed again.
+func (s *memoryService) RemovePost(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, d := range s.docs {
+		if d.postID == id {
+			s.remove(k)
+		}
+	}
+	delete(s.public, id)
+}
+
+// IndexComment adds the comment to the index or updates it.
+func (s *memoryService) IndexComment(c model.Comment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.index(key{model.SearchTypeComment, c.ID}, &document{postID: c.PostID, body: c.Body})
+}
+
+// RemoveComment removes the comment from the index.
+func (s *memoryService) RemoveComment(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(key{model.SearchTypeComment, id})
+}
+
+// index replaces the document with specific key, the caller must hold the lock.
+func (s *memoryService) index(k key, d *document) {
+	s.remove(k)
+
+	d.terms = map[string]int{}
+	for _, t := range tokens(d.title) {
+		d.terms[t] += titleWeight
+	}
+	for _, t := range tokens(d.body) {
+		d.terms[t]++
+	}
+	for t, n := range d.terms {
+		if s.postings[t] == nil {
+			s.postings[t] = map[key]int{}
+		}
+		s.postings[t][k] = n
+		d.length += n
+	}
+	s.docs[k] = d
+}
+
+// remove removes the document with specific key, the caller must hold the lock.
+func (s *memoryService) remove(k key) {
+	d, ok := s.docs[k]
+	if !ok {
+		return
+	}
+
+	for t := range d.terms {
+		delete(s.postings[t], k)
+		if len(s.postings[t]) == 0 {
+			delete(s.postings, t)
+		}
+	}
+	delete(s.docs, k)
+}
@@ -0,0 +1,131 @@
+package search
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// found returns types and IDs of search results.
+func found(t *testing.T, s Service, q string) []string {
+	rs, err := s.Search(context.Background(), model.SearchQuery{Q: q, Limit: model.DefaultPageLimit})
+	assert.NoError(t, err)
+
+	ids := []string{}
+	for _, r := range rs {
+		ids = append(ids, r.Type+":"+strconv.Itoa(r.ID))
+	}
+
+	return ids
+}
+
+func newTestService() Service {
+	s := NewMemoryService()
+	s.IndexPost(model.Post{ID: 1, Title: "Go generics", Body: "Generics are coming to Go.", Status: model.PostPublished})
+	s.IndexPost(model.Post{ID: 2, Title: "Rust", Body: "Ownership and borrowing, unlike go.", Status: model.PostPublished})
+	s.IndexComment(model.Comment{ID: 1, PostID: 1, Body: "Can't wait for generics!"})
+	s.IndexComment(model.Comment{ID: 2, PostID: 2, Body: "Borrow checker is strict."})
+
+	return s
+}
+
+func TestMemoryService_Search(t *testing.T) {
+	testcases := []struct {
+		name   string
+		q      string
+		expIDs []string
+	}{
+		{
+			name:   "title matches rank higher",
+			q:      "go",
+			expIDs: []string{"post:1", "post:2"},
+		},
+		{
+			name:   "posts and comments are found",
+			q:      "GENERICS",
+			expIDs: []string{"post:1", "comment:1"},
+		},
+		{
+			name:   "any word matches",
+			q:      "ownership strict",
+			expIDs: []string{"comment:2", "post:2"},
+		},
+		{
+			name:   "nothing is found",
+			q:      "python",
+			expIDs: []string{},
+		},
+	}
+
+	for _, tc := range testcases {
+		assert.Equal(t, tc.expIDs, found(t, newTestService(), tc.q), tc.name)
+	}
+}
+
+func TestMemoryService_Search_Page(t *testing.T) {
+	s := newTestService()
+
+	rs, err := s.Search(context.Background(), model.SearchQuery{Q: "go generics", Limit: 1, Offset: 1})
+	assert.NoError(t, err)
+	assert.Len(t, rs, 1)
+	assert.Equal(t, model.SearchTypeComment, rs[0].Type)
+
+	rs, err = s.Search(context.Background(), model.SearchQuery{Q: "go", Limit: 1, Offset: 5})
+	assert.NoError(t, err)
+	assert.Empty(t, rs)
+
+	_, err = s.Search(context.Background(), model.SearchQuery{Limit: 1})
+	assert.Error(t, err)
+}
+
+func TestMemoryService_Index(t *testing.T) {
+	s := newTestService()
+
+	s.IndexPost(model.Post{ID: 2, Title: "Rust", Body: "Ownership and borrowing.", Status: model.PostPublished})
+	assert.Equal(t, []string{"post:1"}, found(t, s, "go"))
+
+	s.RemoveComment(1)
+	assert.Equal(t, []string{"post:1"}, found(t, s, "generics"))
+
+	s.RemovePost(2)
+	assert.Equal(t, []string{}, found(t, s, "borrowing borrow"))
+
+	s.IndexPost(model.Post{ID: 2, Title: "Rust", Body: "Ownership and borrowing.", Status: model.PostPublished})
+	assert.Equal(t, []string{"post:2"}, found(t, s, "borrowing borrow"))
+
+	s.IndexComment(model.Comment{ID: 2, PostID: 2, Body: "Borrow checker is strict."})
+	assert.Equal(t, []string{"comment:2", "post:2"}, found(t, s, "borrowing borrow"))
+}
+
+func TestMemoryService_RemovePost(t *testing.T) {
+	s := newTestService().(*memoryService)
+
+	s.RemovePost(1)
+	s.RemovePost(2)
+
+	assert.Empty(t, s.docs)
+	assert.Empty(t, s.postings)
+	assert.Empty(t, s.public)
+}
+
+func TestHighlight(t *testing.T) {
+	terms := map[string]bool{"go": true}
+
+	assert.Equal(t, "<mark>Go</mark> &amp; golang, <mark>go</mark>", highlight("Go & golang, go", terms, 0))
+	assert.Equal(t, "…b <mark>go</mark> c d…", highlight("a a a a a a a b go c d d d d d d", terms, 8))
+	assert.Equal(t, "a a…", highlight("a a a a", terms, 3))
+}
+
+func TestMemoryService_Index_Draft(t *testing.T) {
+	s := newTestService()
+
+	s.IndexPost(model.Post{ID: 1, Title: "Go generics", Body: "Draft.", Status: model.PostDraft})
+	assert.Equal(t, []string{}, found(t, s, "generics"))
+
+	s.IndexPost(model.Post{ID: 1, Title: "Go generics", Body: "Archived.", Status: model.PostArchived})
+	assert.Equal(t, []string{"post:1", "comment:1"}, found(t, s, "generics"))
+}
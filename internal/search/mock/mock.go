@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+
+// Package mock_search is a generated GoMock package.
+package mock_search
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/imarrche/nix-ed/internal/model"
+	reflect "reflect"
+)
+
+// MockService is a mock of Service interface
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method
+func (m *MockService) Search(arg0 context.Context, arg1 model.SearchQuery) ([]model.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", arg0, arg1)
+	ret0, _ := ret[0].([]model.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search
+func (mr *MockServiceMockRecorder) Search(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockService)(nil).Search), arg0, arg1)
+}
+
+// IndexPost mocks base method
+func (m *MockService) IndexPost(arg0 model.Post) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IndexPost", arg0)
+}
+
+// IndexPost indicates an expected call of IndexPost
+func (mr *MockServiceMockRecorder) IndexPost(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexPost", reflect.TypeOf((*MockService)(nil).IndexPost), arg0)
+}
+
+// RemovePost mocks base method
+func (m *MockService) RemovePost(arg0 int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemovePost", arg0)
+}
+
+// RemovePost indicates an expected call of RemovePost
+func (mr *MockServiceMockRecorder) RemovePost(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePost", reflect.TypeOf((*MockService)(nil).RemovePost), arg0)
+}
+
+// IndexComment mocks base method
+func (m *MockService) IndexComment(arg0 model.Comment) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IndexComment", arg0)
+}
+
+// IndexComment indicates an expected call of IndexComment
+func (mr *MockServiceMockRecorder) IndexComment(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IndexComment", reflect.TypeOf((*MockService)(nil).IndexComment), arg0)
+}
+
+// RemoveComment mocks base method
+func (m *MockService) RemoveComment(arg0 int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemoveComment", arg0)
+}
+
+// RemoveComment indicates an expected call of RemoveComment
+func (mr *MockServiceMockRecorder) RemoveComment(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveComment", reflect.TypeOf((*MockService)(nil).RemoveComment), arg0)
+}
@@ -0,0 +1,109 @@
+package search
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/store"
+)
+
+// fulltextIndexes are the FULLTEXT indexes the MySQL service searches with.
+var fulltextIndexes = []struct {
+	model   interface{}
+	table   string
+	name    string
+	columns string
+}{
+	{&model.Post{}, "posts", "idx_posts_fulltext", "title, body"},
+	{&model.Comment{}, "comments", "idx_comments_fulltext", "body"},
+}
+
+// searchSQL searches public posts and their comments in natural language mode.
+const searchSQL = `
+SELECT 'post' AS type, id, id AS post_id, title, body,
+	MATCH(title, body) AGAINST(@q IN NATURAL LANGUAGE MODE) AS score
+FROM posts
+WHERE deleted_at IS NULL AND status IN @statuses
+	AND MATCH(title, body) AGAINST(@q IN NATURAL LANGUAGE MODE)
+UNION ALL
+SELECT 'comment' AS type, id, post_id, '' AS title, body,
+	MATCH(body) AGAINST(@q IN NATURAL LANGUAGE MODE) AS score
+FROM comments
+WHERE deleted_at IS NULL
+	AND post_id IN (SELECT id FROM posts WHERE deleted_at IS NULL AND status IN @statuses)
+	AND MATCH(body) AGAINST(@q IN NATURAL LANGUAGE MODE)
+ORDER BY score DESC, type DESC, id
+LIMIT @limit OFFSET @offset`
+
+// row is a row returned by searchSQL.
+type row struct {
+	Type   string
+	ID     int
+	PostID int
+	Title  string
+	Body   string
+	Score  float64
+}
+
+// mysqlService is MySQL FULLTEXT search service implementation. It searches
+// the tables directly, so index methods do nothing.
+type mysqlService struct {
+	db *gorm.DB
+}
+
+// NewMySQLService creates the FULLTEXT indexes if they don't exist and returns
+// a new MySQL Service instance.
+func NewMySQLService(db *gorm.DB) (Service, error) {
+	for _, i := range fulltextIndexes {
+		if db.Migrator().HasIndex(i.model, i.name) {
+			continue
+		}
+		sql := fmt.Sprintf("ALTER TABLE %s ADD FULLTEXT INDEX %s (%s)", i.table, i.name, i.columns)
+		if err := db.Exec(sql).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return &mysqlService{db: db}, nil
+}
+
+// Search finds posts and comments matching the query.
+func (s *mysqlService) Search(ctx context.Context, q model.SearchQuery) ([]model.SearchResult, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	rows := []row{}
+	args := map[string]interface{}{
+		"q":        q.Q,
+		"statuses": []string{model.PostPublished, model.PostArchived},
+		"limit":    q.Limit,
+		"offset":   q.Offset,
+	}
+	if err := s.db.WithContext(ctx).Raw(searchSQL, args).Scan(&rows).Error; err != nil {
+		return nil, store.Wrap(err)
+	}
+
+	terms := termSet(q.Q)
+	rs := make([]model.SearchResult, len(rows))
+	for i, r := range rows {
+		rs[i] = result(r.Type, r.ID, r.PostID, r.Title, r.Body, r.Score, terms)
+	}
+
+	return rs, nil
+}
+
+// IndexPost does nothing.
+func (s *mysqlService) IndexPost(model.Post) {}
+
+// RemovePost does nothing.
+func (s *mysqlService) RemovePost(int) {}
+
+// IndexComment does nothing.
+func (s *mysqlService) IndexComment(model.Comment) {}
+
+// RemoveComment does nothing.
+func (s *mysqlService) RemoveComment(int) {}
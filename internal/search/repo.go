@@ -0,0 +1,189 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/imarrche/nix-ed/internal/comment"
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/post"
+)
+
+// postRepo is post repository decorator that keeps the search index up to date.
+type postRepo struct {
+	post.Repo
+	cr comment.Repo
+	s  Service
+}
+
+// NewPostRepo wraps the post repository so posts written through it are
+// indexed by the search service. Comments of restored posts are read from the
+// comment repository.
+func NewPostRepo(r post.Repo, cr comment.Repo, s Service) post.Repo {
+	return &postRepo{Repo: r, cr: cr, s: s}
+}
+
+// Create creates, indexes and returns the post.
+func (r *postRepo) Create(ctx context.Context, p model.Post) (model.Post, error) {
+	p, err := r.Repo.Create(ctx, p)
+	if err == nil {
+		r.s.IndexPost(p)
+	}
+
+	return p, err
+}
+
+// Update updates, indexes and returns the post.
+func (r *postRepo) Update(ctx context.Context, p model.Post) (model.Post, error) {
+	p, err := r.Repo.Update(ctx, p)
+	if err == nil {
+		r.s.IndexPost(p)
+	}
+
+	return p, err
+}
+
+// DeleteByID deletes the post and removes it and its comments from the index.
+// Deleted posts aren't indexed, so purging them leaves nothing in the index.
+func (r *postRepo) DeleteByID(ctx context.Context, id int) error {
+	err := r.Repo.DeleteByID(ctx, id)
+	if err == nil {
+		r.s.RemovePost(id)
+	}
+
+	return err
+}
+
+// Restore restores, indexes and returns the post. The comments restored
+// together with it are indexed too, the ones that can't be read are indexed
+// by the next Reindex.
+func (r *postRepo) Restore(ctx context.Context, id int) (model.Post, error) {
+	p, err := r.Repo.Restore(ctx, id)
+	if err != nil {
+		return p, err
+	}
+	r.s.IndexPost(p)
+
+	pg := model.Page{Limit: model.MaxPageLimit}
+	for {
+		cs, pi, err := r.cr.GetAllByPostID(ctx, id, pg)
+		if err != nil {
+			break
+		}
+		for _, c := range cs {
+			r.s.IndexComment(c)
+		}
+		if pg.Cursor = pi.NextCursor; pg.Cursor == "" {
+			break
+		}
+	}
+
+	return p, nil
+}
+
+// UpdateStatus updates post's status, indexes and returns the post.
+func (r *postRepo) UpdateStatus(ctx context.Context, p model.Post) (model.Post, error) {
+	p, err := r.Repo.UpdateStatus(ctx, p)
+	if err == nil {
+		r.s.IndexPost(p)
+	}
+
+	return p, err
+}
+
+// PublishDue publishes due scheduled posts, indexes and returns them.
+func (r *postRepo) PublishDue(ctx context.Context, now time.Time) ([]model.Post, error) {
+	ps, err := r.Repo.PublishDue(ctx, now)
+	for _, p := range ps {
+		r.s.IndexPost(p)
+	}
+
+	return ps, err
+}
+
+// commentRepo is comment repository decorator that keeps the search index up
+// to date.
+type commentRepo struct {
+	comment.Repo
+	s Service
+}
+
+// NewCommentRepo wraps the comment repository so comments written through it
+// are indexed by the search service.
+func NewCommentRepo(r comment.Repo, s Service) comment.Repo {
+	return &commentRepo{Repo: r, s: s}
+}
+
+// Create creates, indexes and returns the comment.
+func (r *commentRepo) Create(ctx context.Context, c model.Comment) (model.Comment, error) {
+	c, err := r.Repo.Create(ctx, c)
+	if err == nil {
+		r.s.IndexComment(c)
+	}
+
+	return c, err
+}
+
+// Update updates, indexes and returns the comment.
+func (r *commentRepo) Update(ctx context.Context, c model.Comment) (model.Comment, error) {
+	c, err := r.Repo.Update(ctx, c)
+	if err == nil {
+		r.s.IndexComment(c)
+	}
+
+	return c, err
+}
+
+// DeleteByID deletes the comment and removes it from the index.
+func (r *commentRepo) DeleteByID(ctx context.Context, id int) error {
+	err := r.Repo.DeleteByID(ctx, id)
+	if err == nil {
+		r.s.RemoveComment(id)
+	}
+
+	return err
+}
+
+// Restore restores, indexes and returns the comment.
+func (r *commentRepo) Restore(ctx context.Context, id int) (model.Comment, error) {
+	c, err := r.Repo.Restore(ctx, id)
+	if err == nil {
+		r.s.IndexComment(c)
+	}
+
+	return c, err
+}
+
+// Reindex indexes all posts and comments that aren't deleted, drafts and
+// scheduled posts included.
+func Reindex(ctx context.Context, s Service, pr post.Repo, cr comment.Repo) error {
+	pg := model.Page{Limit: model.MaxPageLimit}
+	for {
+		ps, pi, err := pr.GetAll(ctx, model.PostFilter{IncludeHidden: true}, pg)
+		if err != nil {
+			return err
+		}
+		for _, p := range ps {
+			s.IndexPost(p)
+		}
+		if pg.Cursor = pi.NextCursor; pg.Cursor == "" {
+			break
+		}
+	}
+
+	pg = model.Page{Limit: model.MaxPageLimit}
+	for {
+		cs, pi, err := cr.GetAll(ctx, model.CommentFilter{IncludeHidden: true}, pg)
+		if err != nil {
+			return err
+		}
+		for _, c := range cs {
+			s.IndexComment(c)
+		}
+		if pg.Cursor = pi.NextCursor; pg.Cursor == "" {
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	mockcomment "github.com/imarrche/nix-ed/internal/comment/mock"
+	"github.com/imarrche/nix-ed/internal/model"
+	mockpost "github.com/imarrche/nix-ed/internal/post/mock"
+)
+
+func TestPostRepo_Restore(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.Background()
+	pr := mockpost.NewMockRepo(c)
+	cr := mockcomment.NewMockRepo(c)
+	s := newTestService()
+	r := NewPostRepo(pr, cr, s)
+
+	p := model.Post{ID: 2, Title: "Rust", Body: "Ownership and borrowing, unlike go.", Status: model.PostPublished}
+	pr.EXPECT().DeleteByID(ctx, 2).Return(nil)
+	assert.NoError(t, r.DeleteByID(ctx, 2))
+	assert.Equal(t, []string{}, found(t, s, "borrowing borrow"))
+
+	pg := model.Page{Limit: model.MaxPageLimit}
+	pr.EXPECT().Restore(ctx, 2).Return(p, nil)
+	cr.EXPECT().GetAllByPostID(ctx, 2, pg).Return(
+		[]model.Comment{{ID: 2, PostID: 2, Body: "Borrow checker is strict."}}, model.PageInfo{NextCursor: "next"}, nil,
+	)
+	pg.Cursor = "next"
+	cr.EXPECT().GetAllByPostID(ctx, 2, pg).Return(
+		[]model.Comment{{ID: 3, PostID: 2, Body: "Borrowing is fine."}}, model.PageInfo{}, nil,
+	)
+	_, err := r.Restore(ctx, 2)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"comment:2", "comment:3", "post:2"}, found(t, s, "borrowing borrow"))
+}
+
+func TestReindex(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ctx := context.Background()
+	pr := mockpost.NewMockRepo(c)
+	cr := mockcomment.NewMockRepo(c)
+	s := NewMemoryService()
+
+	draft := model.Post{ID: 1, Title: "Go", Body: "Generics draft.", UserID: "1", Status: model.PostDraft}
+	pg := model.Page{Limit: model.MaxPageLimit}
+	pr.EXPECT().GetAll(ctx, model.PostFilter{IncludeHidden: true}, pg).Return([]model.Post{draft}, model.PageInfo{}, nil)
+	cr.EXPECT().GetAll(ctx, model.CommentFilter{IncludeHidden: true}, pg).Return(
+		[]model.Comment{{ID: 1, PostID: 1, Body: "Generics at last."}}, model.PageInfo{}, nil,
+	)
+	assert.NoError(t, Reindex(ctx, s, pr, cr))
+	assert.Equal(t, []string{}, found(t, s, "generics"))
+
+	// Publishing indexes only the post, its comment was indexed at startup.
+	published := draft
+	published.Status = model.PostPublished
+	pr.EXPECT().UpdateStatus(ctx, published).Return(published, nil)
+	_, err := NewPostRepo(pr, cr, s).UpdateStatus(ctx, published)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"post:1", "comment:1"}, found(t, s, "generics"))
+}
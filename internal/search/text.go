@@ -0,0 +1,98 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// snippetWidth is the maximum number of characters in highlighted bodies.
+const snippetWidth = 200
+
+// isSeparator reports whether r separates words.
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+// tokens splits text into lower-cased words.
+func tokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), isSeparator)
+}
+
+// termSet returns the set of words of the search query.
+func termSet(q string) map[string]bool {
+	terms := map[string]bool{}
+	for _, t := range tokens(q) {
+		terms[t] = true
+	}
+
+	return terms
+}
+
+// highlight HTML escapes text and wraps words found in terms in <mark> tags.
+// When width is positive, text is cut to width characters around the first
+// matching word.
+func highlight(text string, terms map[string]bool, width int) string {
+	rs := []rune(text)
+
+	var matches [][2]int
+	start := -1
+	for i := 0; i <= len(rs); i++ {
+		if i < len(rs) && !isSeparator(rs[i]) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 && terms[strings.ToLower(string(rs[start:i]))] {
+			matches = append(matches, [2]int{start, i})
+		}
+		start = -1
+	}
+
+	from, to := 0, len(rs)
+	if width > 0 && len(rs) > width {
+		if len(matches) > 0 && matches[0][0] > width/4 {
+			from = matches[0][0] - width/4
+		}
+		if to = from + width; to > len(rs) {
+			from, to = len(rs)-width, len(rs)
+		}
+	}
+
+	b := strings.Builder{}
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, m := range matches {
+		if m[0] < from || m[1] > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(rs[pos:m[0]])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(rs[m[0]:m[1]])))
+		b.WriteString("</mark>")
+		pos = m[1]
+	}
+	b.WriteString(html.EscapeString(string(rs[pos:to])))
+	if to < len(rs) {
+		b.WriteString("…")
+	}
+
+	return b.String()
+}
+
+// result returns the search result with highlighted title and body.
+func result(typ string, id, postID int, title, body string, score float64, terms map[string]bool) model.SearchResult {
+	return model.SearchResult{
+		Type:   typ,
+		ID:     id,
+		PostID: postID,
+		Title:  highlight(title, terms, 0),
+		Body:   highlight(body, terms, snippetWidth),
+		Score:  score,
+	}
+}
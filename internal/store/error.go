@@ -0,0 +1,149 @@
+// Package store provides typed errors of GORM repositories.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotFound is the kind of errors thrown when specified record was not
+	// found in database.
+	ErrNotFound = errors.New("record was not found")
+	// ErrConflict is the kind of errors thrown when a record conflicts with
+	// another one or with its current version.
+	ErrConflict = errors.New("record conflicts with another one")
+	// ErrConstraint is the kind of errors thrown when a record violates a
+	// database constraint.
+	ErrConstraint = errors.New("record violates a constraint")
+	// ErrUnavailable is the kind of errors thrown when database can't be
+	// reached or didn't answer in time.
+	ErrUnavailable = errors.New("database is unavailable")
+)
+
+// Error is repository error of one of the kinds above.
+type Error struct {
+	Kind error
+	Err  error
+
+	// wrapped tells database errors of Wrap from errors of New, messages of
+	// the former are the database's.
+	wrapped bool
+}
+
+// New returns repository error of the kind with the message.
+func New(kind error, message string) error {
+	return &Error{Kind: kind, Err: errors.New(message)}
+}
+
+// Error returns the message of the underlying error.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error is of the target kind.
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+// Wrap returns the database error as repository error of its kind. Nil,
+// repository errors and errors of no kind are returned as they are.
+func Wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	if kind := kindOf(err); kind != nil {
+		return &Error{Kind: kind, Err: err, wrapped: true}
+	}
+
+	return err
+}
+
+// Redact returns the kind of the database error wrapped by Wrap and true,
+// messages of database errors name tables, columns and constraints. Other
+// errors are returned as they are with false.
+func Redact(err error) (error, bool) {
+	var e *Error
+	if errors.As(err, &e) && e.wrapped {
+		return e.Kind, true
+	}
+
+	return err, false
+}
+
+// kindOf returns the kind of the database error, MySQL errors are told apart
+// by their numbers and SQLite ones by their messages.
+func kindOf(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) || errors.Is(err, mysql.ErrInvalidConn) {
+		return ErrUnavailable
+	}
+	var ne net.Error
+	if errors.As(err, &ne) {
+		return ErrUnavailable
+	}
+
+	var me *mysql.MySQLError
+	if errors.As(err, &me) {
+		switch me.Number {
+		case 1062: // ER_DUP_ENTRY
+			return ErrConflict
+		case 1048, 1406, 1451, 1452, 3819: // NULL, too long, foreign key and check
+			return ErrConstraint
+		case 1040, 1053, 1205, 1213: // connections, shutdown, lock wait and deadlock
+			return ErrUnavailable
+		}
+		return nil
+	}
+
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "UNIQUE constraint failed"):
+		return ErrConflict
+	case strings.HasPrefix(msg, "FOREIGN KEY constraint failed"),
+		strings.HasPrefix(msg, "NOT NULL constraint failed"),
+		strings.HasPrefix(msg, "CHECK constraint failed"):
+		return ErrConstraint
+	case strings.HasPrefix(msg, "database is locked"), msg == "sql: database is closed":
+		return ErrUnavailable
+	}
+
+	return nil
+}
+
+// Status returns the HTTP status of the repository error's kind, errors of
+// no kind are internal server errors.
+func Status(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, ErrConstraint):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrUnavailable):
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/imarrche/nix-ed/internal/testdb"
+)
+
+// record is a model with unique, not null and foreign key constraints.
+type record struct {
+	ID       int
+	Name     string `gorm:"uniqueIndex;not null"`
+	ParentID *int
+	Parent   *record
+}
+
+// sqliteErrors returns errors of SQLite constraint violations.
+func sqliteErrors(t *testing.T) (unique, notNull, foreignKey error) {
+	db := testdb.New(t, &record{})
+
+	if err := db.Create(&record{Name: "a"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	unique = db.Create(&record{Name: "a"}).Error
+	notNull = db.Exec("INSERT INTO records (id) VALUES (5)").Error
+	parent := 10
+	foreignKey = db.Create(&record{Name: "b", ParentID: &parent}).Error
+
+	return unique, notNull, foreignKey
+}
+
+func TestWrap(t *testing.T) {
+	unique, notNull, foreignKey := sqliteErrors(t)
+	errUnknown := errors.New("unknown")
+
+	testcases := []struct {
+		name      string
+		err       error
+		expKind   error
+		expStatus int
+	}{
+		{name: "record not found", err: gorm.ErrRecordNotFound, expKind: ErrNotFound, expStatus: http.StatusNotFound},
+		{name: "repository error", err: New(ErrConflict, "taken"), expKind: ErrConflict, expStatus: http.StatusConflict},
+		{name: "sqlite unique", err: unique, expKind: ErrConflict, expStatus: http.StatusConflict},
+		{name: "sqlite not null", err: notNull, expKind: ErrConstraint, expStatus: http.StatusUnprocessableEntity},
+		{name: "sqlite foreign key", err: foreignKey, expKind: ErrConstraint, expStatus: http.StatusUnprocessableEntity},
+		{
+			name: "mysql duplicate entry", err: &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"},
+			expKind: ErrConflict, expStatus: http.StatusConflict,
+		},
+		{
+			name: "mysql foreign key", err: &mysql.MySQLError{Number: 1452, Message: "Cannot add or update"},
+			expKind: ErrConstraint, expStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "mysql deadlock", err: &mysql.MySQLError{Number: 1213, Message: "Deadlock found"},
+			expKind: ErrUnavailable, expStatus: http.StatusServiceUnavailable,
+		},
+		{name: "bad connection", err: driver.ErrBadConn, expKind: ErrUnavailable, expStatus: http.StatusServiceUnavailable},
+		{
+			name: "deadline exceeded", err: fmt.Errorf("query: %w", context.DeadlineExceeded),
+			expKind: ErrUnavailable, expStatus: http.StatusServiceUnavailable,
+		},
+		{name: "unknown", err: errUnknown, expStatus: http.StatusInternalServerError},
+		{name: "nil", err: nil},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Wrap(tc.err)
+			if tc.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, errors.Is(err, tc.err))
+			for _, kind := range []error{ErrNotFound, ErrConflict, ErrConstraint, ErrUnavailable} {
+				assert.Equal(t, kind == tc.expKind, errors.Is(err, kind))
+			}
+			assert.Equal(t, tc.expStatus, Status(err))
+		})
+	}
+}
+
+func TestWrap_Idempotent(t *testing.T) {
+	err := New(ErrNotFound, "specified record was not found")
+
+	assert.Equal(t, err, Wrap(err))
+	assert.Equal(t, err, Wrap(Wrap(err)))
+	assert.EqualError(t, Wrap(gorm.ErrRecordNotFound), gorm.ErrRecordNotFound.Error())
+}
+
+func TestRedact(t *testing.T) {
+	unique, _, _ := sqliteErrors(t)
+
+	err, ok := Redact(Wrap(unique))
+	assert.True(t, ok)
+	assert.Equal(t, ErrConflict, err)
+
+	err, ok = Redact(fmt.Errorf("create: %w", Wrap(&mysql.MySQLError{Number: 1452, Message: "Cannot add or update"})))
+	assert.True(t, ok)
+	assert.Equal(t, ErrConstraint, err)
+
+	taken := New(ErrConflict, "name is already taken")
+	err, ok = Redact(taken)
+	assert.False(t, ok)
+	assert.Equal(t, taken, err)
+}
@@ -0,0 +1,10 @@
+package tag
+
+import "github.com/imarrche/nix-ed/internal/store"
+
+var (
+	// ErrNotFound is thrown when specified tag was not found in database.
+	ErrNotFound = store.New(store.ErrNotFound, "specified tag was not found")
+	// ErrNameTaken is thrown when another tag has the same name.
+	ErrNameTaken = store.New(store.ErrConflict, "specified tag name is taken")
+)
@@ -0,0 +1,214 @@
+package tag
+
+import (
+	"net/http"
+	"strconv"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/labstack/echo/v4"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/render"
+)
+
+// Handler is http handler for tag resource.
+type Handler struct {
+	ts Service
+}
+
+// NewHandler creates and returns a new Handler instance.
+func NewHandler(ts Service) *Handler {
+	return &Handler{ts: ts}
+}
+
+// setPageHeaders sets the total count and the next page cursor headers.
+func setPageHeaders(c echo.Context, pi model.PageInfo) {
+	c.Response().Header().Set("X-Total-Count", strconv.FormatInt(pi.Total, 10))
+	if pi.NextCursor != "" {
+		c.Response().Header().Set("X-Next-Cursor", pi.NextCursor)
+	}
+}
+
+// GetAll returns tag list.
+// @Summary Show all tags
+// @Descriptions show the page of tags
+// @Tags tags
+// @ID tag-list
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack,text/csv
+// @Param limit query int false "page size" default(20) maximum(100)
+// @Param offset query int false "number of tags to skip"
+// @Param cursor query string false "cursor returned by the previous page"
+// @Param sort query string false "sort field" Enums(id, name)
+// @Param order query string false "sort order" Enums(asc, desc)
+// @Success 200 {array} model.Tag
+// @Header 200 {integer} X-Total-Count "total number of tags"
+// @Header 200 {string} X-Next-Cursor "cursor of the next page"
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /tags [get]
+func (h *Handler) GetAll(c echo.Context) error {
+	pg := model.Page{Limit: model.DefaultPageLimit}
+	if err := c.Bind(&pg); err != nil {
+		return err
+	}
+
+	ts, pi, err := h.ts.GetAll(c.Request().Context(), pg)
+	if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+
+	setPageHeaders(c, pi)
+	return render.Respond(c, http.StatusOK, ts)
+}
+
+// Create creates a tag.
+// @Summary Create a tag
+// @Descriptions create a tag
+// @Tags tags
+// @ID tag-create
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param input body model.Tag true "tag data"
+// @Success 201 {object} model.Tag
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Router /tags [post]
+func (h *Handler) Create(c echo.Context) error {
+	t := model.Tag{}
+	if err := c.Bind(&t); err != nil {
+		return err
+	}
+	t.ID = 0
+
+	t, err := h.ts.Create(c.Request().Context(), t)
+	if err == ErrNameTaken {
+		return echo.NewHTTPError(http.StatusConflict, err)
+	} else if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusCreated, t)
+}
+
+// GetByID returns tag detail.
+// @Summary Tag detail
+// @Descriptions tag detail
+// @Tags tags
+// @ID tag-detail
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "tag id"
+// @Success 200 {object} model.Tag
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /tags/{id} [get]
+func (h *Handler) GetByID(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	t, err := h.ts.GetByID(c.Request().Context(), id)
+	if err == ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusOK, t)
+}
+
+// Update updates a tag.
+// @Summary Tag update
+// @Descriptions rename a tag
+// @Tags tags
+// @ID tag-update
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "tag id"
+// @Param input body model.Tag true "tag data"
+// @Success 200 {object} model.Tag
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Router /tags/{id} [patch]
+func (h *Handler) Update(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	t := model.Tag{}
+	if err := c.Bind(&t); err != nil {
+		return err
+	}
+	t.ID = id
+
+	t, err = h.ts.Update(c.Request().Context(), t)
+	if err == ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err == ErrNameTaken {
+		return echo.NewHTTPError(http.StatusConflict, err)
+	} else if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusOK, t)
+}
+
+// DeleteByID deletes a tag.
+// @Summary Tag delete
+// @Descriptions delete a tag and remove it from posts
+// @Tags tags
+// @ID tag-delete
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "tag id"
+// @Success 204 ""
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /tags/{id} [delete]
+func (h *Handler) DeleteByID(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	err = h.ts.DeleteByID(c.Request().Context(), id)
+	if err == ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetCounts returns tag post counts.
+// @Summary Show tag counts
+// @Descriptions show the number of public posts tagged with every tag, the most used tags go first
+// @Tags tags
+// @ID tag-counts
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack,text/csv
+// @Success 200 {array} model.TagCount
+// @Failure 500 {object} problem.Problem
+// @Router /tags/counts [get]
+func (h *Handler) GetCounts(c echo.Context) error {
+	cs, err := h.ts.GetCounts(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusOK, cs)
+}
@@ -0,0 +1,288 @@
+package tag
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/auth"
+	mockauth "github.com/imarrche/nix-ed/internal/auth/mock"
+	"github.com/imarrche/nix-ed/internal/handlertest"
+	"github.com/imarrche/nix-ed/internal/model"
+	mocktag "github.com/imarrche/nix-ed/internal/tag/mock"
+)
+
+func TestHandler_GetAll(t *testing.T) {
+	testcases := []struct {
+		name     string
+		mock     func(*mocktag.MockService)
+		query    string
+		expTags  []model.Tag
+		expTotal string
+		expCode  int
+	}{
+		{
+			name: "tags are retrieved",
+			mock: func(s *mocktag.MockService) {
+				pg := model.Page{Limit: model.DefaultPageLimit, Sort: "name"}
+				s.EXPECT().GetAll(gomock.Any(), pg).Return([]model.Tag{{Name: "go"}}, model.PageInfo{Total: 1}, nil)
+			},
+			query:    "?sort=name",
+			expTags:  []model.Tag{{Name: "go"}},
+			expTotal: "1",
+			expCode:  http.StatusOK,
+		},
+		{
+			name:    "invalid query",
+			mock:    func(s *mocktag.MockService) {},
+			query:   "?limit=ten",
+			expCode: http.StatusBadRequest,
+		},
+		{
+			name: "validation errors",
+			mock: func(s *mocktag.MockService) {
+				pg := model.Page{Limit: model.DefaultPageLimit, Sort: "body"}
+				err := validation.Errors{"sort": errors.New("must be a valid value")}
+				s.EXPECT().GetAll(gomock.Any(), pg).Return(nil, model.PageInfo{}, err)
+			},
+			query:   "?sort=body",
+			expCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ts := mocktag.NewMockService(c)
+		tc.mock(ts)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/tags"+tc.query, nil)
+
+		ctx := echo.New().NewContext(r, w)
+
+		handlertest.Serve(ctx, NewHandler(ts).GetAll)
+
+		var tags []model.Tag
+		json.NewDecoder(w.Body).Decode(&tags)
+
+		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expTotal, w.Header().Get("X-Total-Count"))
+		assert.Equal(t, tc.expTags, tags)
+	}
+}
+
+func TestHandler_Create(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mock    func(*mocktag.MockService, model.Tag)
+		tag     model.Tag
+		expCode int
+	}{
+		{
+			name: "tag is created",
+			mock: func(s *mocktag.MockService, tg model.Tag) {
+				s.EXPECT().Create(gomock.Any(), tg).Return(tg, nil)
+			},
+			tag:     model.Tag{Name: "go"},
+			expCode: http.StatusCreated,
+		},
+		{
+			name: "name is taken",
+			mock: func(s *mocktag.MockService, tg model.Tag) {
+				s.EXPECT().Create(gomock.Any(), tg).Return(model.Tag{}, ErrNameTaken)
+			},
+			tag:     model.Tag{Name: "go"},
+			expCode: http.StatusConflict,
+		},
+		{
+			name: "validation errors",
+			mock: func(s *mocktag.MockService, tg model.Tag) {
+				err := validation.Errors{"name": errors.New("cannot be blank")}
+				s.EXPECT().Create(gomock.Any(), tg).Return(model.Tag{}, err)
+			},
+			tag:     model.Tag{},
+			expCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ts := mocktag.NewMockService(c)
+		tc.mock(ts, tc.tag)
+		w := httptest.NewRecorder()
+
+		b := &bytes.Buffer{}
+		json.NewEncoder(b).Encode(tc.tag)
+		r := httptest.NewRequest(http.MethodPost, "/tags", b)
+		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		ctx := echo.New().NewContext(r, w)
+
+		handlertest.Serve(ctx, NewHandler(ts).Create)
+
+		assert.Equal(t, tc.expCode, w.Code)
+	}
+}
+
+func TestHandler_Create_Permission(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mock    func(*mocktag.MockService)
+		role    string
+		expCode int
+	}{
+		{
+			name: "moderator creates a tag",
+			mock: func(s *mocktag.MockService) {
+				s.EXPECT().Create(gomock.Any(), model.Tag{Name: "go"}).Return(model.Tag{ID: 1, Name: "go"}, nil)
+			},
+			role:    model.RoleModerator,
+			expCode: http.StatusCreated,
+		},
+		{
+			name:    "user can't manage taxonomy",
+			mock:    func(s *mocktag.MockService) {},
+			role:    model.RoleUser,
+			expCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ts := mocktag.NewMockService(c)
+		tc.mock(ts)
+		ss := mockauth.NewMockSessions(c)
+		ss.EXPECT().Verify(gomock.Any(), "token").Return(auth.Claims{Subject: "1", SessionID: "s", Role: tc.role}, nil)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/tags", bytes.NewBufferString(`{"name":"go"}`))
+		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		r.Header.Set("Authorization", "token")
+
+		hf := auth.Require(model.PermManageTaxonomy)(NewHandler(ts).Create)
+		handlertest.Serve(echo.New().NewContext(r, w), auth.Authenticate(ss)(hf))
+
+		assert.Equal(t, tc.expCode, w.Code, tc.name)
+	}
+}
+
+func TestHandler_Update(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mock    func(*mocktag.MockService, model.Tag)
+		tag     model.Tag
+		expCode int
+	}{
+		{
+			name: "tag is updated",
+			mock: func(s *mocktag.MockService, tg model.Tag) {
+				s.EXPECT().Update(gomock.Any(), tg).Return(tg, nil)
+			},
+			tag:     model.Tag{ID: 1, Name: "go"},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "tag not found",
+			mock: func(s *mocktag.MockService, tg model.Tag) {
+				s.EXPECT().Update(gomock.Any(), tg).Return(model.Tag{}, ErrNotFound)
+			},
+			tag:     model.Tag{ID: 1, Name: "go"},
+			expCode: http.StatusNotFound,
+		},
+		{
+			name: "name is taken",
+			mock: func(s *mocktag.MockService, tg model.Tag) {
+				s.EXPECT().Update(gomock.Any(), tg).Return(model.Tag{}, ErrNameTaken)
+			},
+			tag:     model.Tag{ID: 1, Name: "go"},
+			expCode: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ts := mocktag.NewMockService(c)
+		tc.mock(ts, tc.tag)
+		w := httptest.NewRecorder()
+
+		b := &bytes.Buffer{}
+		json.NewEncoder(b).Encode(tc.tag)
+		r := httptest.NewRequest(http.MethodPatch, "/tags/1", b)
+		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, NewHandler(ts).Update)
+
+		assert.Equal(t, tc.expCode, w.Code)
+	}
+}
+
+func TestHandler_DeleteByID(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mock    func(*mocktag.MockService)
+		expCode int
+	}{
+		{
+			name: "tag is deleted",
+			mock: func(s *mocktag.MockService) {
+				s.EXPECT().DeleteByID(gomock.Any(), 1).Return(nil)
+			},
+			expCode: http.StatusNoContent,
+		},
+		{
+			name: "tag not found",
+			mock: func(s *mocktag.MockService) {
+				s.EXPECT().DeleteByID(gomock.Any(), 1).Return(ErrNotFound)
+			},
+			expCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		ts := mocktag.NewMockService(c)
+		tc.mock(ts)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodDelete, "/tags/1", nil)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, NewHandler(ts).DeleteByID)
+
+		assert.Equal(t, tc.expCode, w.Code)
+	}
+}
+
+func TestHandler_GetCounts(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	ts := mocktag.NewMockService(c)
+	counts := []model.TagCount{{ID: 1, Name: "go", Count: 2}}
+	ts.EXPECT().GetCounts(gomock.Any()).Return(counts, nil)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/tags/counts", nil)
+
+	handlertest.Serve(echo.New().NewContext(r, w), NewHandler(ts).GetCounts)
+
+	var cs []model.TagCount
+	json.NewDecoder(w.Body).Decode(&cs)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, counts, cs)
+}
@@ -0,0 +1,30 @@
+// Package tag provides all tag domain related logic.
+package tag
+
+import (
+	"context"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+//go:generate mockgen -source=interface.go -destination=mock/mock.go
+
+// Repo is the interface all tag repositories must implement.
+type Repo interface {
+	GetAll(context.Context, model.Page) ([]model.Tag, model.PageInfo, error)
+	Create(context.Context, model.Tag) (model.Tag, error)
+	GetByID(context.Context, int) (model.Tag, error)
+	Update(context.Context, model.Tag) (model.Tag, error)
+	DeleteByID(context.Context, int) error
+	GetCounts(context.Context) ([]model.TagCount, error)
+}
+
+// Service is the interface all tag services must implement.
+type Service interface {
+	GetAll(context.Context, model.Page) ([]model.Tag, model.PageInfo, error)
+	Create(context.Context, model.Tag) (model.Tag, error)
+	GetByID(context.Context, int) (model.Tag, error)
+	Update(context.Context, model.Tag) (model.Tag, error)
+	DeleteByID(context.Context, int) error
+	GetCounts(context.Context) ([]model.TagCount, error)
+}
@@ -0,0 +1,137 @@
+package tag
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/store"
+)
+
+// repo is tag repository implementation.
+type repo struct {
+	db *gorm.DB
+}
+
+// NewRepo creates and returns a new Repo instance.
+func NewRepo(db *gorm.DB) Repo {
+	return &repo{db}
+}
+
+// sortColumns maps sort parameters to tag table columns.
+var sortColumns = map[string]string{
+	"":     "id",
+	"id":   "id",
+	"name": "name",
+}
+
+// GetAll gets and returns the page of tags.
+func (r *repo) GetAll(ctx context.Context, pg model.Page) (ts []model.Tag, pi model.PageInfo, err error) {
+	if err := r.db.WithContext(ctx).Model(&model.Tag{}).Count(&pi.Total).Error; err != nil {
+		return nil, pi, store.Wrap(err)
+	}
+	if err := r.db.WithContext(ctx).Scopes(pg.Paginate(sortColumns[pg.Sort])).Find(&ts).Error; err != nil {
+		return nil, pi, store.Wrap(err)
+	}
+
+	if len(ts) > pg.Limit {
+		ts = ts[:pg.Limit]
+		last := ts[len(ts)-1]
+		if pg.Sort == "name" {
+			pi.NextCursor = pg.NextCursor(last.Name, last.ID)
+		} else {
+			pi.NextCursor = pg.NextCursor(last.ID, last.ID)
+		}
+	}
+
+	return ts, pi, nil
+}
+
+// Create creates a tag and returns it.
+func (r *repo) Create(ctx context.Context, t model.Tag) (model.Tag, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := nameTaken(tx, t); err != nil {
+			return err
+		}
+
+		return tx.Create(&t).Error
+	})
+
+	return t, store.Wrap(err)
+}
+
+// GetByID gets and returns the tag with specific ID.
+func (r *repo) GetByID(ctx context.Context, id int) (t model.Tag, err error) {
+	err = r.db.WithContext(ctx).First(&t, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return t, ErrNotFound
+	}
+
+	return t, store.Wrap(err)
+}
+
+// Update updates the tag and returns it.
+func (r *repo) Update(ctx context.Context, t model.Tag) (model.Tag, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := nameTaken(tx, t); err != nil {
+			return err
+		}
+
+		t.UpdatedAt = tx.NowFunc()
+		return tx.Model(&model.Tag{}).Where("id = ?", t.ID).
+			Updates(map[string]interface{}{"name": t.Name, "updated_at": t.UpdatedAt}).Error
+	})
+	if err != nil {
+		return model.Tag{}, store.Wrap(err)
+	}
+
+	return t, nil
+}
+
+// nameTaken returns ErrNameTaken when another tag has the same name as t.
+func nameTaken(tx *gorm.DB, t model.Tag) error {
+	var n int64
+	err := tx.Model(&model.Tag{}).Where("name = ? AND id <> ?", t.Name, t.ID).Count(&n).Error
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return ErrNameTaken
+	}
+
+	return nil
+}
+
+// DeleteByID deletes the tag with specific ID and removes it from posts.
+func (r *repo) DeleteByID(ctx context.Context, id int) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return store.Wrap(err)
+	}
+
+	return store.Wrap(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM post_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&model.Tag{}, id).Error
+	}))
+}
+
+// GetCounts gets and returns the numbers of public posts tagged with every
+// tag, the most used tags go first.
+func (r *repo) GetCounts(ctx context.Context) (cs []model.TagCount, err error) {
+	err = r.db.WithContext(ctx).Table("tags").
+		Select("tags.id, tags.name, COUNT(posts.id) AS count").
+		Joins("LEFT JOIN post_tags ON post_tags.tag_id = tags.id").
+		Joins(
+			"LEFT JOIN posts ON posts.id = post_tags.post_id AND posts.deleted_at IS NULL AND posts.status IN ?",
+			[]string{model.PostPublished, model.PostArchived},
+		).
+		Group("tags.id, tags.name").
+		Order("count DESC, tags.name").
+		Scan(&cs).Error
+
+	return cs, store.Wrap(err)
+}
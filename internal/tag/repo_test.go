@@ -0,0 +1,91 @@
+package tag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/testdb"
+)
+
+// testModels are the models migrated in test databases.
+var testModels = []interface{}{&model.Tag{}, &model.Category{}, &model.Post{}}
+
+func TestTagRepo_CRUD(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	r := NewRepo(db)
+
+	tg, err := r.Create(context.Background(), model.Tag{Name: "go"})
+	assert.NoError(t, err)
+	_, err = r.Create(context.Background(), model.Tag{Name: "sql"})
+	assert.NoError(t, err)
+	_, err = r.Create(context.Background(), model.Tag{Name: "go"})
+	assert.Equal(t, ErrNameTaken, err)
+
+	tg.Name = "sql"
+	_, err = r.Update(context.Background(), tg)
+	assert.Equal(t, ErrNameTaken, err)
+	tg.Name = "golang"
+	_, err = r.Update(context.Background(), tg)
+	assert.NoError(t, err)
+
+	ts, pi, err := r.GetAll(context.Background(), model.Page{Limit: 1, Sort: "name"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), pi.Total)
+	assert.Len(t, ts, 1)
+	assert.Equal(t, "golang", ts[0].Name)
+	ts, _, err = r.GetAll(context.Background(), model.Page{Limit: 1, Sort: "name", Cursor: pi.NextCursor})
+	assert.NoError(t, err)
+	assert.Equal(t, "sql", ts[0].Name)
+
+	assert.NoError(t, r.DeleteByID(context.Background(), tg.ID))
+	_, err = r.GetByID(context.Background(), tg.ID)
+	assert.Equal(t, ErrNotFound, err)
+	assert.Equal(t, ErrNotFound, r.DeleteByID(context.Background(), tg.ID))
+}
+
+func TestTagRepo_GetCounts(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	r := NewRepo(db)
+	var tags []model.Tag
+	for _, name := range []string{"go", "sql", "rust"} {
+		tg, err := r.Create(context.Background(), model.Tag{Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tags = append(tags, tg)
+	}
+	posts := []model.Post{
+		{Status: model.PostPublished, Tags: tags[:2]},
+		{Status: model.PostArchived, Tags: tags[1:2]},
+		{Status: model.PostDraft, Tags: tags[:1]},
+	}
+	for _, p := range posts {
+		p.Title, p.Body, p.UserID = "Title", "Body.", "1"
+		if err := db.Create(&p).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Deleted posts aren't counted.
+	p := model.Post{Title: "Title", Body: "Body.", UserID: "1", Status: model.PostPublished, Tags: tags[:1]}
+	if err := db.Create(&p).Error; err != nil {
+		t.Fatal(err)
+	}
+	db.Delete(&p)
+
+	cs, err := r.GetCounts(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TagCount{
+		{ID: tags[1].ID, Name: "sql", Count: 2},
+		{ID: tags[0].ID, Name: "go", Count: 1},
+		{ID: tags[2].ID, Name: "rust", Count: 0},
+	}, cs)
+
+	// Deleting a tag removes it from posts.
+	assert.NoError(t, r.DeleteByID(context.Background(), tags[1].ID))
+	var n int64
+	db.Table("post_tags").Where("tag_id = ?", tags[1].ID).Count(&n)
+	assert.Zero(t, n)
+}
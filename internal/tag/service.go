@@ -0,0 +1,71 @@
+package tag
+
+import (
+	"context"
+	"strings"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// sorts are tag list sort parameters.
+var sorts = []string{"id", "name"}
+
+// service is tag service implementation.
+type service struct {
+	r Repo
+}
+
+// NewService creates and returns a new Service instance.
+func NewService(r Repo) Service {
+	return &service{r: r}
+}
+
+// GetAll gets and returns the page of tags.
+func (s *service) GetAll(ctx context.Context, pg model.Page) ([]model.Tag, model.PageInfo, error) {
+	if err := pg.Validate(sorts...); err != nil {
+		return nil, model.PageInfo{}, err
+	}
+
+	return s.r.GetAll(ctx, pg)
+}
+
+// Create creates a tag and returns it.
+func (s *service) Create(ctx context.Context, t model.Tag) (model.Tag, error) {
+	t.Name = strings.TrimSpace(t.Name)
+	if err := t.Validate(); err != nil {
+		return model.Tag{}, err
+	}
+
+	return s.r.Create(ctx, t)
+}
+
+// GetByID gets and returns the tag with specific ID.
+func (s *service) GetByID(ctx context.Context, id int) (model.Tag, error) {
+	return s.r.GetByID(ctx, id)
+}
+
+// Update updates the tag and returns it.
+func (s *service) Update(ctx context.Context, t model.Tag) (model.Tag, error) {
+	ut, err := s.r.GetByID(ctx, t.ID)
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	ut.Name = strings.TrimSpace(t.Name)
+	if err := ut.Validate(); err != nil {
+		return model.Tag{}, err
+	}
+
+	return s.r.Update(ctx, ut)
+}
+
+// DeleteByID deletes the tag with specific ID, posts lose the tag.
+func (s *service) DeleteByID(ctx context.Context, id int) error {
+	return s.r.DeleteByID(ctx, id)
+}
+
+// GetCounts gets and returns the numbers of public posts tagged with every
+// tag.
+func (s *service) GetCounts(ctx context.Context) ([]model.TagCount, error) {
+	return s.r.GetCounts(ctx)
+}
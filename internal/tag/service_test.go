@@ -0,0 +1,132 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	mocktag "github.com/imarrche/nix-ed/internal/tag/mock"
+)
+
+func TestTagService_Create(t *testing.T) {
+	testcases := []struct {
+		name     string
+		mock     func(*mocktag.MockRepo)
+		tag      model.Tag
+		expTag   model.Tag
+		expError error
+	}{
+		{
+			name: "tag is created",
+			mock: func(r *mocktag.MockRepo) {
+				r.EXPECT().Create(gomock.Any(), model.Tag{Name: "go"}).Return(model.Tag{ID: 1, Name: "go"}, nil)
+			},
+			tag:      model.Tag{Name: " go "},
+			expTag:   model.Tag{ID: 1, Name: "go"},
+			expError: nil,
+		},
+		{
+			name: "name is taken",
+			mock: func(r *mocktag.MockRepo) {
+				r.EXPECT().Create(gomock.Any(), model.Tag{Name: "go"}).Return(model.Tag{}, ErrNameTaken)
+			},
+			tag:      model.Tag{Name: "go"},
+			expError: ErrNameTaken,
+		},
+		{
+			name:     "validation errors",
+			mock:     func(_ *mocktag.MockRepo) {},
+			tag:      model.Tag{Name: " "},
+			expError: validation.Errors{"name": errors.New("cannot be blank")},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mocktag.NewMockRepo(c)
+			tc.mock(repo)
+			s := NewService(repo)
+
+			tg, err := s.Create(context.Background(), tc.tag)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expTag, tg)
+		})
+	}
+}
+
+func TestTagService_Update(t *testing.T) {
+	testcases := []struct {
+		name     string
+		mock     func(*mocktag.MockRepo)
+		tag      model.Tag
+		expTag   model.Tag
+		expError error
+	}{
+		{
+			name: "tag is updated",
+			mock: func(r *mocktag.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Tag{ID: 1, Name: "go"}, nil)
+				r.EXPECT().Update(gomock.Any(), model.Tag{ID: 1, Name: "golang"}).Return(model.Tag{ID: 1, Name: "golang"}, nil)
+			},
+			tag:      model.Tag{ID: 1, Name: "golang"},
+			expTag:   model.Tag{ID: 1, Name: "golang"},
+			expError: nil,
+		},
+		{
+			name: "tag not found",
+			mock: func(r *mocktag.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Tag{}, ErrNotFound)
+			},
+			tag:      model.Tag{ID: 1, Name: "golang"},
+			expError: ErrNotFound,
+		},
+		{
+			name: "validation errors",
+			mock: func(r *mocktag.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), 1).Return(model.Tag{ID: 1, Name: "go"}, nil)
+			},
+			tag:      model.Tag{ID: 1},
+			expError: validation.Errors{"name": errors.New("cannot be blank")},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mocktag.NewMockRepo(c)
+			tc.mock(repo)
+			s := NewService(repo)
+
+			tg, err := s.Update(context.Background(), tc.tag)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expTag, tg)
+		})
+	}
+}
+
+func TestTagService_GetAll(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+	repo := mocktag.NewMockRepo(c)
+	pg := model.Page{Limit: 20, Sort: "name"}
+	repo.EXPECT().GetAll(gomock.Any(), pg).Return([]model.Tag{{Name: "go"}}, model.PageInfo{Total: 1}, nil)
+	s := NewService(repo)
+
+	ts, pi, err := s.GetAll(context.Background(), pg)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Tag{{Name: "go"}}, ts)
+	assert.Equal(t, model.PageInfo{Total: 1}, pi)
+
+	_, _, err = s.GetAll(context.Background(), model.Page{Limit: 20, Sort: "created"})
+	assert.Equal(t, validation.Errors{"sort": errors.New("must be a valid value")}, err)
+}
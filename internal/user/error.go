@@ -0,0 +1,19 @@
+package user
+
+import (
+	"errors"
+
+	"github.com/imarrche/nix-ed/internal/store"
+)
+
+var (
+	// ErrNotFound is thrown when specified user was not found in database.
+	ErrNotFound = store.New(store.ErrNotFound, "specified user was not found")
+	// ErrLastAdmin is thrown when the role of the last admin is changed.
+	ErrLastAdmin = store.New(store.ErrConflict, "the last admin can't lose the admin role")
+	// ErrAdminExists is thrown when bootstrapping an admin while there's one.
+	ErrAdminExists = errors.New("an admin already exists")
+	// ErrAmbiguousEmail is thrown when more than one user has specified
+	// email.
+	ErrAmbiguousEmail = errors.New("more than one user has specified email")
+)
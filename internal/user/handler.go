@@ -0,0 +1,114 @@
+package user
+
+import (
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/labstack/echo/v4"
+
+	"github.com/imarrche/nix-ed/internal/auth"
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/render"
+)
+
+// Handler is http handler for user resource.
+type Handler struct {
+	us Service
+}
+
+// NewHandler creates and returns a new Handler instance.
+func NewHandler(us Service) *Handler {
+	return &Handler{us: us}
+}
+
+// GetByID returns user profile.
+// @Summary User profile
+// @Descriptions user's public profile
+// @Tags users
+// @ID user-detail
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path string true "user id"
+// @Success 200 {object} model.User
+// @Failure 404 {object} problem.Problem
+// @Router /users/{id} [get]
+func (h *Handler) GetByID(c echo.Context) error {
+	u, err := h.us.GetByID(c.Request().Context(), c.Param("id"))
+	if err == ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusOK, u)
+}
+
+// UpdateMe updates the profile of the user who made a request.
+// @Summary Own profile update
+// @Descriptions update name, avatar URL and bio of the signed in user, fields left out keep their values
+// @Tags users
+// @ID user-update-me
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param input body model.ProfileUpdate true "profile data"
+// @Success 200 {object} model.User
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /users/me [patch]
+func (h *Handler) UpdateMe(c echo.Context) error {
+	uID := auth.Viewer(c)
+	if uID == "" {
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	pu := model.ProfileUpdate{}
+	if err := c.Bind(&pu); err != nil {
+		return err
+	}
+
+	u, err := h.us.Update(c.Request().Context(), uID, pu)
+	if err == ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusOK, u)
+}
+
+// SetRole changes the role of a user.
+// @Summary User role change
+// @Descriptions change the role of a user, only admins can change roles
+// @Tags users
+// @ID user-set-role
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path string true "user id"
+// @Param input body model.RoleChange true "role"
+// @Success 200 {object} model.User
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Router /users/{id}/role [put]
+func (h *Handler) SetRole(c echo.Context) error {
+	rc := model.RoleChange{}
+	if err := c.Bind(&rc); err != nil {
+		return err
+	}
+
+	u, err := h.us.SetRole(c.Request().Context(), c.Param("id"), rc)
+	if err == ErrNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	} else if err == ErrLastAdmin {
+		return echo.NewHTTPError(http.StatusConflict, err)
+	} else if _, ok := err.(validation.Errors); ok {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	} else if err != nil {
+		return err
+	}
+
+	return render.Respond(c, http.StatusOK, u)
+}
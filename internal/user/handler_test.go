@@ -0,0 +1,209 @@
+package user
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/auth"
+	mockauth "github.com/imarrche/nix-ed/internal/auth/mock"
+	"github.com/imarrche/nix-ed/internal/handlertest"
+	"github.com/imarrche/nix-ed/internal/model"
+	mockuser "github.com/imarrche/nix-ed/internal/user/mock"
+)
+
+func TestHandler_GetByID(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mock    func(*mockuser.MockService)
+		expUser model.User
+		expCode int
+	}{
+		{
+			name: "user is retrieved",
+			mock: func(s *mockuser.MockService) {
+				s.EXPECT().GetByID(gomock.Any(), "1").Return(model.User{ID: "1", Email: "u@t.com", Name: "User"}, nil)
+			},
+			expUser: model.User{ID: "1", Name: "User"},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "user is not found",
+			mock: func(s *mockuser.MockService) {
+				s.EXPECT().GetByID(gomock.Any(), "1").Return(model.User{}, ErrNotFound)
+			},
+			expCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		us := mockuser.NewMockService(c)
+		tc.mock(us)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("1")
+
+		handlertest.Serve(ctx, NewHandler(us).GetByID)
+
+		var u model.User
+		json.NewDecoder(w.Body).Decode(&u)
+
+		assert.Equal(t, tc.expCode, w.Code)
+		assert.Equal(t, tc.expUser, u)
+	}
+}
+
+func TestHandler_UpdateMe(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mock    func(*mockuser.MockService)
+		body    string
+		expCode int
+	}{
+		{
+			name: "profile is updated",
+			mock: func(s *mockuser.MockService) {
+				pu := model.ProfileUpdate{Name: strPtr("Name"), Bio: strPtr("Bio")}
+				s.EXPECT().Update(gomock.Any(), "1", pu).Return(model.User{ID: "1", Name: "Name", Bio: "Bio"}, nil)
+			},
+			body:    `{"id":"2","name":"Name","bio":"Bio"}`,
+			expCode: http.StatusOK,
+		},
+		{
+			name: "only present fields are updated",
+			mock: func(s *mockuser.MockService) {
+				s.EXPECT().Update(gomock.Any(), "1", model.ProfileUpdate{Bio: strPtr("x")}).
+					Return(model.User{ID: "1", Name: "Name", AvatarURL: "https://t.com/a.png", Bio: "x"}, nil)
+			},
+			body:    `{"bio":"x"}`,
+			expCode: http.StatusOK,
+		},
+		{
+			name: "validation errors",
+			mock: func(s *mockuser.MockService) {
+				err := validation.Errors{"name": errors.New("cannot be blank")}
+				s.EXPECT().Update(gomock.Any(), "1", model.ProfileUpdate{Name: strPtr("")}).Return(model.User{}, err)
+			},
+			body:    `{"name":""}`,
+			expCode: http.StatusBadRequest,
+		},
+		{
+			name: "user is not found",
+			mock: func(s *mockuser.MockService) {
+				s.EXPECT().Update(gomock.Any(), "1", model.ProfileUpdate{Name: strPtr("Name")}).Return(model.User{}, ErrNotFound)
+			},
+			body:    `{"name":"Name"}`,
+			expCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		us := mockuser.NewMockService(c)
+		tc.mock(us)
+		ss := mockauth.NewMockSessions(c)
+		ss.EXPECT().Verify(gomock.Any(), "token").Return(auth.Claims{Subject: "1", Email: "u@t.com", SessionID: "s"}, nil)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPatch, "/users/me", bytes.NewBufferString(tc.body))
+		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		r.Header.Set("Authorization", "token")
+
+		ctx := echo.New().NewContext(r, w)
+
+		handlertest.Serve(ctx, auth.Authenticate(ss)(NewHandler(us).UpdateMe))
+
+		assert.Equal(t, tc.expCode, w.Code)
+	}
+}
+
+func TestHandler_SetRole(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mock    func(*mockuser.MockService)
+		role    string
+		body    string
+		expCode int
+	}{
+		{
+			name: "role is set",
+			mock: func(s *mockuser.MockService) {
+				s.EXPECT().SetRole(gomock.Any(), "2", model.RoleChange{Role: model.RoleModerator}).
+					Return(model.User{ID: "2", Role: model.RoleModerator}, nil)
+			},
+			role:    model.RoleAdmin,
+			body:    `{"role":"moderator"}`,
+			expCode: http.StatusOK,
+		},
+		{
+			name:    "user is not an admin",
+			mock:    func(s *mockuser.MockService) {},
+			role:    model.RoleModerator,
+			body:    `{"role":"admin"}`,
+			expCode: http.StatusForbidden,
+		},
+		{
+			name: "validation errors",
+			mock: func(s *mockuser.MockService) {
+				err := validation.Errors{"role": errors.New("must be a valid value")}
+				s.EXPECT().SetRole(gomock.Any(), "2", model.RoleChange{Role: "owner"}).Return(model.User{}, err)
+			},
+			role:    model.RoleAdmin,
+			body:    `{"role":"owner"}`,
+			expCode: http.StatusBadRequest,
+		},
+		{
+			name: "user is not found",
+			mock: func(s *mockuser.MockService) {
+				s.EXPECT().SetRole(gomock.Any(), "2", model.RoleChange{Role: model.RoleUser}).Return(model.User{}, ErrNotFound)
+			},
+			role:    model.RoleAdmin,
+			body:    `{"role":"user"}`,
+			expCode: http.StatusNotFound,
+		},
+		{
+			name: "last admin",
+			mock: func(s *mockuser.MockService) {
+				s.EXPECT().SetRole(gomock.Any(), "2", model.RoleChange{Role: model.RoleUser}).Return(model.User{}, ErrLastAdmin)
+			},
+			role:    model.RoleAdmin,
+			body:    `{"role":"user"}`,
+			expCode: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testcases {
+		c := gomock.NewController(t)
+		defer c.Finish()
+		us := mockuser.NewMockService(c)
+		tc.mock(us)
+		ss := mockauth.NewMockSessions(c)
+		ss.EXPECT().Verify(gomock.Any(), "token").Return(auth.Claims{Subject: "1", SessionID: "s", Role: tc.role}, nil)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, "/users/2/role", bytes.NewBufferString(tc.body))
+		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		r.Header.Set("Authorization", "token")
+
+		ctx := echo.New().NewContext(r, w)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("2")
+
+		hf := auth.Require(model.PermManageRoles)(NewHandler(us).SetRole)
+		handlertest.Serve(ctx, auth.Authenticate(ss)(hf))
+
+		assert.Equal(t, tc.expCode, w.Code, tc.name)
+	}
+}
@@ -0,0 +1,29 @@
+// Package user provides all user domain related logic.
+package user
+
+import (
+	"context"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+//go:generate mockgen -source=interface.go -destination=mock/mock.go
+
+// Repo is the interface all user repositories must implement.
+type Repo interface {
+	GetByID(context.Context, string) (model.User, error)
+	GetAllByEmail(context.Context, string) ([]model.User, error)
+	CountByRole(context.Context, string) (int64, error)
+	SignIn(context.Context, model.User) (model.User, error)
+	Update(context.Context, model.User) (model.User, error)
+	SetRole(ctx context.Context, id, role string) (model.User, error)
+}
+
+// Service is the interface all user services must implement.
+type Service interface {
+	GetByID(context.Context, string) (model.User, error)
+	SignIn(context.Context, model.User) (model.User, error)
+	Update(ctx context.Context, id string, pu model.ProfileUpdate) (model.User, error)
+	SetRole(ctx context.Context, id string, rc model.RoleChange) (model.User, error)
+	BootstrapAdmin(ctx context.Context, ref string) (model.User, error)
+}
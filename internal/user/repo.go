@@ -0,0 +1,147 @@
+package user
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/store"
+)
+
+// repo is user repository implementation.
+type repo struct {
+	db *gorm.DB
+}
+
+// NewRepo creates and returns a new Repo instance.
+func NewRepo(db *gorm.DB) Repo {
+	return &repo{db}
+}
+
+// GetByID gets and returns the user with specific ID.
+func (r *repo) GetByID(ctx context.Context, id string) (u model.User, err error) {
+	err = r.db.WithContext(ctx).Where("id = ?", id).First(&u).Error
+	if err == gorm.ErrRecordNotFound {
+		return u, ErrNotFound
+	}
+
+	return u, store.Wrap(err)
+}
+
+// SignIn creates the user signing in for the first time or updates the
+// email of the user signing in again, and returns the user. Profile fields
+// are set only when they're empty, so users' changes are kept. Comments
+// written with user's email before are linked to the user when the email is
+// verified.
+func (r *repo) SignIn(ctx context.Context, u model.User) (model.User, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		cur := model.User{}
+		err := tx.Where("id = ?", u.ID).First(&cur).Error
+		if err == gorm.ErrRecordNotFound {
+			// Roles are granted only by admins.
+			u.Role = model.RoleUser
+			if err := tx.Create(&u).Error; err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		} else {
+			cur.Email, cur.EmailVerified = u.Email, u.EmailVerified
+			if cur.Name == "" {
+				cur.Name = u.Name
+			}
+			if cur.AvatarURL == "" {
+				cur.AvatarURL = u.AvatarURL
+			}
+			if err := tx.Save(&cur).Error; err != nil {
+				return err
+			}
+			u = cur
+		}
+
+		if !u.EmailVerified {
+			return nil
+		}
+		return tx.Model(&model.Comment{}).Unscoped().
+			Where("email = ? AND user_id IS NULL", u.Email).
+			UpdateColumn("user_id", u.ID).Error
+	})
+	if err != nil {
+		return model.User{}, store.Wrap(err)
+	}
+
+	return u, nil
+}
+
+// Update updates user's profile and returns the user.
+func (r *repo) Update(ctx context.Context, u model.User) (model.User, error) {
+	u.UpdatedAt = r.db.NowFunc()
+	res := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", u.ID).Updates(map[string]interface{}{
+		"name": u.Name, "avatar_url": u.AvatarURL, "bio": u.Bio, "updated_at": u.UpdatedAt,
+	})
+	if res.Error != nil {
+		return model.User{}, store.Wrap(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return model.User{}, ErrNotFound
+	}
+
+	return r.GetByID(ctx, u.ID)
+}
+
+// GetAllByEmail gets and returns users with specific email.
+func (r *repo) GetAllByEmail(ctx context.Context, email string) (us []model.User, err error) {
+	err = r.db.WithContext(ctx).Where("email = ?", email).Order("id").Find(&us).Error
+
+	return us, store.Wrap(err)
+}
+
+// CountByRole returns the number of users with the role.
+func (r *repo) CountByRole(ctx context.Context, role string) (n int64, err error) {
+	err = r.db.WithContext(ctx).Model(&model.User{}).Where("role = ?", role).Count(&n).Error
+
+	return n, store.Wrap(err)
+}
+
+// SetRole sets the role of the user and their sessions and returns the user.
+// The last admin can't lose the admin role.
+func (r *repo) SetRole(ctx context.Context, id, role string) (model.User, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		u := model.User{}
+		err := tx.Where("id = ?", id).First(&u).Error
+		if err == gorm.ErrRecordNotFound {
+			return ErrNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if u.Role == model.RoleAdmin && role != model.RoleAdmin {
+			// Admins are locked until the transaction ends, so two admins
+			// demoting each other can't both see the other one left.
+			var admins int64
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(&model.User{}).
+				Where("role = ?", model.RoleAdmin).Count(&admins).Error
+			if err != nil {
+				return err
+			}
+			if admins <= 1 {
+				return ErrLastAdmin
+			}
+		}
+
+		err = tx.Model(&model.User{}).Where("id = ?", id).
+			Updates(map[string]interface{}{"role": role, "updated_at": tx.NowFunc()}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Session{}).Where("user_id = ?", id).UpdateColumn("role", role).Error
+	})
+	if err != nil {
+		return model.User{}, store.Wrap(err)
+	}
+
+	return r.GetByID(ctx, id)
+}
@@ -0,0 +1,120 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	"github.com/imarrche/nix-ed/internal/testdb"
+)
+
+// testModels are the models migrated in test databases.
+var testModels = []interface{}{&model.User{}, &model.Tag{}, &model.Category{}, &model.Post{}, &model.Comment{}, &model.Session{}}
+
+func TestUserRepo_SignIn(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	r := NewRepo(db)
+
+	p := model.Post{Title: "Post", Body: "Body", UserID: "1"}
+	assert.Error(t, db.Create(&p).Error, "posts must reference users")
+
+	u, err := r.SignIn(context.Background(), model.User{ID: "1", Email: "u@t.com", Name: "User", AvatarURL: "https://t.com/a.png", Role: model.RoleAdmin})
+	assert.NoError(t, err)
+	assert.Equal(t, "User", u.Name)
+	assert.Equal(t, model.RoleUser, u.Role, "signing in doesn't grant roles")
+	assert.NoError(t, db.Create(&p).Error)
+
+	u.Name = "Renamed"
+	_, err = r.Update(context.Background(), u)
+	assert.NoError(t, err)
+
+	// Signing in again keeps the profile and updates the email.
+	u, err = r.SignIn(context.Background(), model.User{ID: "1", Email: "new@t.com", Name: "User", AvatarURL: "https://t.com/b.png"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed", u.Name)
+	assert.Equal(t, "https://t.com/a.png", u.AvatarURL)
+	assert.Equal(t, "new@t.com", u.Email)
+
+	u, err = r.GetByID(context.Background(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "new@t.com", u.Email)
+	_, err = r.GetByID(context.Background(), "2")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestUserRepo_SignIn_LinksComments(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	r := NewRepo(db)
+
+	if _, err := r.SignIn(context.Background(), model.User{ID: "1", Email: "author@t.com", Name: "Author"}); err != nil {
+		t.Fatal(err)
+	}
+	p := model.Post{Title: "Post", Body: "Body", UserID: "1"}
+	if err := db.Create(&p).Error; err != nil {
+		t.Fatal(err)
+	}
+	cm := model.Comment{Name: "C", Email: "c@t.com", Body: "Body", PostID: p.ID}
+	if err := db.Create(&cm).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Unverified emails don't link comments.
+	_, err := r.SignIn(context.Background(), model.User{ID: "2", Email: "c@t.com", Name: "C"})
+	assert.NoError(t, err)
+	db.First(&cm, cm.ID)
+	assert.Nil(t, cm.UserID)
+
+	_, err = r.SignIn(context.Background(), model.User{ID: "2", Email: "c@t.com", EmailVerified: true, Name: "C"})
+	assert.NoError(t, err)
+	db.First(&cm, cm.ID)
+	if assert.NotNil(t, cm.UserID) {
+		assert.Equal(t, "2", *cm.UserID)
+	}
+}
+
+func TestUserRepo_Update_NotFound(t *testing.T) {
+	r := NewRepo(testdb.New(t, testModels...))
+
+	_, err := r.Update(context.Background(), model.User{ID: "1", Name: "User"})
+
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestUserRepo_SetRole(t *testing.T) {
+	db := testdb.New(t, testModels...)
+	r := NewRepo(db)
+	for _, id := range []string{"1", "2"} {
+		if _, err := r.SignIn(context.Background(), model.User{ID: id, Email: id + "@t.com", Name: "User"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ss := model.Session{ID: "s", UserID: "2", RefreshHash: "h", ExpiresAt: db.NowFunc()}
+	if err := db.Create(&ss).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := r.SetRole(context.Background(), "1", model.RoleAdmin)
+	assert.NoError(t, err)
+	assert.Equal(t, model.RoleAdmin, u.Role)
+	_, err = r.SetRole(context.Background(), "2", model.RoleAdmin)
+	assert.NoError(t, err)
+
+	// Sessions get the new role.
+	db.First(&ss, "id = ?", "s")
+	assert.Equal(t, model.RoleAdmin, ss.Role)
+
+	n, err := r.CountByRole(context.Background(), model.RoleAdmin)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	// The last admin keeps the role.
+	_, err = r.SetRole(context.Background(), "1", model.RoleModerator)
+	assert.NoError(t, err)
+	_, err = r.SetRole(context.Background(), "2", model.RoleUser)
+	assert.Equal(t, ErrLastAdmin, err)
+
+	_, err = r.SetRole(context.Background(), "3", model.RoleAdmin)
+	assert.Equal(t, ErrNotFound, err)
+}
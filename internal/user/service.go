@@ -0,0 +1,99 @@
+package user
+
+import (
+	"context"
+	"strings"
+
+	"github.com/imarrche/nix-ed/internal/model"
+)
+
+// service is user service implementation.
+type service struct {
+	r Repo
+}
+
+// NewService creates and returns a new Service instance.
+func NewService(r Repo) Service {
+	return &service{r: r}
+}
+
+// GetByID gets and returns the user with specific ID.
+func (s *service) GetByID(ctx context.Context, id string) (model.User, error) {
+	return s.r.GetByID(ctx, id)
+}
+
+// SignIn creates or updates the user signing in and returns the user. Users
+// without a name in their provider's profile are named after their email.
+func (s *service) SignIn(ctx context.Context, u model.User) (model.User, error) {
+	if u.Name == "" {
+		u.Name = strings.SplitN(u.Email, "@", 2)[0]
+	}
+
+	return s.r.SignIn(ctx, u)
+}
+
+// Update updates the name, avatar URL and bio of the user with specific ID
+// that are present in the profile update and returns the user.
+func (s *service) Update(ctx context.Context, id string, pu model.ProfileUpdate) (model.User, error) {
+	u, err := s.r.GetByID(ctx, id)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if pu.Name != nil {
+		u.Name = strings.TrimSpace(*pu.Name)
+	}
+	if pu.AvatarURL != nil {
+		u.AvatarURL = strings.TrimSpace(*pu.AvatarURL)
+	}
+	if pu.Bio != nil {
+		u.Bio = strings.TrimSpace(*pu.Bio)
+	}
+	if err := u.Validate(); err != nil {
+		return model.User{}, err
+	}
+
+	return s.r.Update(ctx, u)
+}
+
+// SetRole validates the role change and sets the role of the user with
+// specific ID.
+func (s *service) SetRole(ctx context.Context, id string, rc model.RoleChange) (model.User, error) {
+	rc.Role = strings.TrimSpace(rc.Role)
+	if err := rc.Validate(); err != nil {
+		return model.User{}, err
+	}
+
+	return s.r.SetRole(ctx, id, rc.Role)
+}
+
+// BootstrapAdmin makes the user with specific ID or email the first admin,
+// it fails when there's an admin already.
+func (s *service) BootstrapAdmin(ctx context.Context, ref string) (model.User, error) {
+	n, err := s.r.CountByRole(ctx, model.RoleAdmin)
+	if err != nil {
+		return model.User{}, err
+	}
+	if n > 0 {
+		return model.User{}, ErrAdminExists
+	}
+
+	u, err := s.r.GetByID(ctx, ref)
+	if err == ErrNotFound {
+		us, err := s.r.GetAllByEmail(ctx, ref)
+		if err != nil {
+			return model.User{}, err
+		}
+		if len(us) == 0 {
+			return model.User{}, ErrNotFound
+		}
+		if len(us) > 1 {
+			return model.User{}, ErrAmbiguousEmail
+		}
+		u = us[0]
+	} else if err != nil {
+		return model.User{}, err
+	}
+
+	return s.r.SetRole(ctx, u.ID, model.RoleAdmin)
+}
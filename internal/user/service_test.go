@@ -0,0 +1,248 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/imarrche/nix-ed/internal/model"
+	mockuser "github.com/imarrche/nix-ed/internal/user/mock"
+)
+
+func TestUserService_SignIn(t *testing.T) {
+	testcases := []struct {
+		name    string
+		mock    func(*mockuser.MockRepo)
+		user    model.User
+		expUser model.User
+	}{
+		{
+			name: "user with a name signs in",
+			mock: func(r *mockuser.MockRepo) {
+				u := model.User{ID: "1", Email: "u@t.com", Name: "User"}
+				r.EXPECT().SignIn(gomock.Any(), u).Return(u, nil)
+			},
+			user:    model.User{ID: "1", Email: "u@t.com", Name: "User"},
+			expUser: model.User{ID: "1", Email: "u@t.com", Name: "User"},
+		},
+		{
+			name: "user without a name is named after the email",
+			mock: func(r *mockuser.MockRepo) {
+				u := model.User{ID: "1", Email: "u@t.com", Name: "u"}
+				r.EXPECT().SignIn(gomock.Any(), u).Return(u, nil)
+			},
+			user:    model.User{ID: "1", Email: "u@t.com"},
+			expUser: model.User{ID: "1", Email: "u@t.com", Name: "u"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mockuser.NewMockRepo(c)
+			tc.mock(repo)
+			s := NewService(repo)
+
+			u, err := s.SignIn(context.Background(), tc.user)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expUser, u)
+		})
+	}
+}
+
+// strPtr returns a pointer to s.
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestUserService_Update(t *testing.T) {
+	cur := model.User{ID: "1", Email: "u@t.com", Name: "User", AvatarURL: "https://t.com/a.png", Bio: "Bio"}
+
+	testcases := []struct {
+		name     string
+		mock     func(*mockuser.MockRepo)
+		update   model.ProfileUpdate
+		expUser  model.User
+		expError error
+	}{
+		{
+			name: "profile is updated",
+			mock: func(r *mockuser.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), "1").Return(cur, nil)
+				u := model.User{ID: "1", Email: "u@t.com", Name: "Name", Bio: "New bio"}
+				r.EXPECT().Update(gomock.Any(), u).Return(u, nil)
+			},
+			update:  model.ProfileUpdate{Name: strPtr(" Name "), AvatarURL: strPtr(""), Bio: strPtr("New bio ")},
+			expUser: model.User{ID: "1", Email: "u@t.com", Name: "Name", Bio: "New bio"},
+		},
+		{
+			name: "fields left out keep their values",
+			mock: func(r *mockuser.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), "1").Return(cur, nil)
+				u := cur
+				u.Bio = "x"
+				r.EXPECT().Update(gomock.Any(), u).Return(u, nil)
+			},
+			update:  model.ProfileUpdate{Bio: strPtr("x")},
+			expUser: model.User{ID: "1", Email: "u@t.com", Name: "User", AvatarURL: "https://t.com/a.png", Bio: "x"},
+		},
+		{
+			name: "user is not found",
+			mock: func(r *mockuser.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), "1").Return(model.User{}, ErrNotFound)
+			},
+			update:   model.ProfileUpdate{Name: strPtr("Name")},
+			expError: ErrNotFound,
+		},
+		{
+			name: "validation errors",
+			mock: func(r *mockuser.MockRepo) {
+				r.EXPECT().GetByID(gomock.Any(), "1").Return(cur, nil)
+			},
+			update: model.ProfileUpdate{Name: strPtr(" "), AvatarURL: strPtr("avatar")},
+			expError: validation.Errors{
+				"name":      errors.New("cannot be blank"),
+				"avatarUrl": errors.New("must be a valid URL"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mockuser.NewMockRepo(c)
+			tc.mock(repo)
+			s := NewService(repo)
+
+			u, err := s.Update(context.Background(), "1", tc.update)
+
+			assert.Equal(t, tc.expError, err)
+			assert.Equal(t, tc.expUser, u)
+		})
+	}
+}
+
+func TestUserService_SetRole(t *testing.T) {
+	testcases := []struct {
+		name   string
+		mock   func(*mockuser.MockRepo)
+		role   string
+		expErr bool
+	}{
+		{
+			name: "role is set",
+			mock: func(r *mockuser.MockRepo) {
+				r.EXPECT().SetRole(gomock.Any(), "1", model.RoleModerator).Return(model.User{ID: "1", Role: model.RoleModerator}, nil)
+			},
+			role: " moderator ",
+		},
+		{
+			name:   "unknown role",
+			mock:   func(r *mockuser.MockRepo) {},
+			role:   "owner",
+			expErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mockuser.NewMockRepo(c)
+			tc.mock(repo)
+			s := NewService(repo)
+
+			_, err := s.SetRole(context.Background(), "1", model.RoleChange{Role: tc.role})
+
+			if tc.expErr {
+				_, ok := err.(validation.Errors)
+				assert.True(t, ok)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUserService_BootstrapAdmin(t *testing.T) {
+	admin := model.User{ID: "1", Email: "u@t.com", Role: model.RoleAdmin}
+
+	testcases := []struct {
+		name   string
+		mock   func(*mockuser.MockRepo)
+		ref    string
+		expErr error
+	}{
+		{
+			name: "user is found by ID",
+			mock: func(r *mockuser.MockRepo) {
+				r.EXPECT().CountByRole(gomock.Any(), model.RoleAdmin).Return(int64(0), nil)
+				r.EXPECT().GetByID(gomock.Any(), "1").Return(model.User{ID: "1"}, nil)
+				r.EXPECT().SetRole(gomock.Any(), "1", model.RoleAdmin).Return(admin, nil)
+			},
+			ref: "1",
+		},
+		{
+			name: "user is found by email",
+			mock: func(r *mockuser.MockRepo) {
+				r.EXPECT().CountByRole(gomock.Any(), model.RoleAdmin).Return(int64(0), nil)
+				r.EXPECT().GetByID(gomock.Any(), "u@t.com").Return(model.User{}, ErrNotFound)
+				r.EXPECT().GetAllByEmail(gomock.Any(), "u@t.com").Return([]model.User{{ID: "1"}}, nil)
+				r.EXPECT().SetRole(gomock.Any(), "1", model.RoleAdmin).Return(admin, nil)
+			},
+			ref: "u@t.com",
+		},
+		{
+			name: "email of more than one user",
+			mock: func(r *mockuser.MockRepo) {
+				r.EXPECT().CountByRole(gomock.Any(), model.RoleAdmin).Return(int64(0), nil)
+				r.EXPECT().GetByID(gomock.Any(), "u@t.com").Return(model.User{}, ErrNotFound)
+				r.EXPECT().GetAllByEmail(gomock.Any(), "u@t.com").Return([]model.User{{ID: "1"}, {ID: "2"}}, nil)
+			},
+			ref:    "u@t.com",
+			expErr: ErrAmbiguousEmail,
+		},
+		{
+			name: "user is not found",
+			mock: func(r *mockuser.MockRepo) {
+				r.EXPECT().CountByRole(gomock.Any(), model.RoleAdmin).Return(int64(0), nil)
+				r.EXPECT().GetByID(gomock.Any(), "u@t.com").Return(model.User{}, ErrNotFound)
+				r.EXPECT().GetAllByEmail(gomock.Any(), "u@t.com").Return(nil, nil)
+			},
+			ref:    "u@t.com",
+			expErr: ErrNotFound,
+		},
+		{
+			name: "admin exists",
+			mock: func(r *mockuser.MockRepo) {
+				r.EXPECT().CountByRole(gomock.Any(), model.RoleAdmin).Return(int64(1), nil)
+			},
+			ref:    "1",
+			expErr: ErrAdminExists,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			repo := mockuser.NewMockRepo(c)
+			tc.mock(repo)
+			s := NewService(repo)
+
+			u, err := s.BootstrapAdmin(context.Background(), tc.ref)
+
+			assert.Equal(t, tc.expErr, err)
+			if tc.expErr == nil {
+				assert.Equal(t, admin, u)
+			}
+		})
+	}
+}